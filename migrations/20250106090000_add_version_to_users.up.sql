@@ -0,0 +1,2 @@
+ALTER TABLE `users`
+    ADD COLUMN `version` int unsigned NOT NULL DEFAULT 1 AFTER `firstname`;
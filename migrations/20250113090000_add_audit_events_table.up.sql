@@ -0,0 +1,18 @@
+CREATE TABLE
+    IF NOT EXISTS `audit_events`
+(
+    `id`          varchar(36)  NOT NULL,
+    `actor_id`    varchar(36)  NOT NULL DEFAULT '',
+    `action`      varchar(15)  NOT NULL,
+    `target_type` varchar(31)  NOT NULL,
+    `target_id`   varchar(36)  NOT NULL,
+    `request_id`  varchar(36)  NOT NULL DEFAULT '',
+    `ip`          varchar(45)  NOT NULL DEFAULT '',
+    `changes`     json         NOT NULL,
+    `created_at`  datetime(3)  NOT NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_audit_events_actor_id` (`actor_id`),
+    KEY `idx_audit_events_target` (`target_type`, `target_id`),
+    KEY `idx_audit_events_created_at` (`created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,4 @@
+ALTER TABLE `users`
+    DROP KEY `idx_users_status`,
+    DROP COLUMN `status_reason`,
+    DROP COLUMN `status`;
@@ -0,0 +1,4 @@
+ALTER TABLE `users`
+    ADD COLUMN `status`        varchar(15)  NOT NULL DEFAULT 'active' AFTER `firstname`,
+    ADD COLUMN `status_reason` varchar(255) NOT NULL DEFAULT '' AFTER `status`,
+    ADD KEY `idx_users_status` (`status`);
@@ -0,0 +1,2 @@
+ALTER TABLE `users`
+    ADD COLUMN `avatar` varchar(255) NOT NULL DEFAULT '' AFTER `firstname`;
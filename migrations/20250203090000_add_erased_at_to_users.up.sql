@@ -0,0 +1,2 @@
+ALTER TABLE `users`
+    ADD COLUMN `erased_at` datetime(3) DEFAULT NULL AFTER `deleted_at`;
@@ -0,0 +1,20 @@
+CREATE TABLE
+    IF NOT EXISTS `outbox_events`
+(
+    `seq`             bigint unsigned NOT NULL AUTO_INCREMENT,
+    `id`              varchar(36)     NOT NULL,
+    `aggregate_type`  varchar(31)     NOT NULL,
+    `aggregate_id`    varchar(36)     NOT NULL,
+    `event_type`      varchar(63)     NOT NULL,
+    `payload`         json            NOT NULL,
+    `request_id`      varchar(36)     NOT NULL DEFAULT '',
+    `attempts`        int unsigned    NOT NULL DEFAULT 0,
+    `last_error`      text            NULL,
+    `created_at`      datetime(3)     NOT NULL,
+    `next_attempt_at` datetime(3)     NOT NULL,
+    `published_at`    datetime(3)     NULL,
+    PRIMARY KEY (`seq`),
+    UNIQUE KEY `idx_outbox_events_id` (`id`),
+    KEY `idx_outbox_events_published_at` (`published_at`, `seq`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,10 @@
+ALTER TABLE `audit_events`
+    DROP KEY `idx_audit_events_organization_id`,
+    DROP COLUMN `organization_id`;
+
+ALTER TABLE `users`
+    DROP KEY `email`,
+    ADD UNIQUE KEY `email` (`email`),
+    DROP COLUMN `organization_id`;
+
+DROP TABLE IF EXISTS `organizations`;
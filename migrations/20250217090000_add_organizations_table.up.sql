@@ -0,0 +1,30 @@
+CREATE TABLE
+    IF NOT EXISTS `organizations`
+(
+    `id`         varchar(36)  NOT NULL,
+    `name`       varchar(127) NOT NULL,
+    `slug`       varchar(63)  NOT NULL,
+    `created_at` datetime(3)  NOT NULL,
+    `updated_at` datetime(3)  NOT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `slug` (`slug`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
+
+-- Users and audit events existing before multi-tenancy belong to the default organization
+INSERT INTO `organizations` (`id`, `name`, `slug`, `created_at`, `updated_at`)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'default', NOW(3), NOW(3));
+
+-- Emails are unique per organization
+ALTER TABLE `users`
+    ADD COLUMN `organization_id` varchar(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' AFTER `id`,
+    DROP KEY `email`,
+    ADD UNIQUE KEY `email` (`organization_id`, `email`);
+ALTER TABLE `users`
+    ALTER COLUMN `organization_id` DROP DEFAULT;
+
+ALTER TABLE `audit_events`
+    ADD COLUMN `organization_id` varchar(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' AFTER `id`,
+    ADD KEY `idx_audit_events_organization_id` (`organization_id`, `created_at`);
+ALTER TABLE `audit_events`
+    ALTER COLUMN `organization_id` DROP DEFAULT;
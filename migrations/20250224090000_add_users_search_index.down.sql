@@ -0,0 +1,2 @@
+ALTER TABLE `users`
+    DROP KEY `idx_users_search`;
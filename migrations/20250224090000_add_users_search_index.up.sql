@@ -0,0 +1,3 @@
+-- Full-text search of the users by names and email (see UserSearchMysqlRepository)
+ALTER TABLE `users`
+    ADD FULLTEXT KEY `idx_users_search` (`lastname`, `firstname`, `email`);
@@ -0,0 +1,17 @@
+CREATE TABLE
+    IF NOT EXISTS `job_runs`
+(
+    `id`           varchar(36)   NOT NULL,
+    `job`          varchar(63)   NOT NULL,
+    `instance`     varchar(255)  NOT NULL,
+    `status`       varchar(15)   NOT NULL,
+    `result`       varchar(255)  NOT NULL DEFAULT '',
+    `error`        varchar(1023) NOT NULL DEFAULT '',
+    `scheduled_at` datetime(3)   NOT NULL,
+    `started_at`   datetime(3)   NOT NULL,
+    `finished_at`  datetime(3)   NULL,
+    PRIMARY KEY (`id`),
+    KEY `idx_job_runs_job` (`job`, `scheduled_at`),
+    KEY `idx_job_runs_started_at` (`started_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4;
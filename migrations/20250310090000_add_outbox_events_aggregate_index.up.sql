@@ -0,0 +1,3 @@
+-- Pending events are read without the aggregates blocked by an older event (see OutboxRepository.GetPending)
+ALTER TABLE `outbox_events`
+    ADD KEY `idx_outbox_events_aggregate` (`aggregate_type`, `aggregate_id`, `seq`);
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations so that the binary does not need the files at runtime.
+package migrations
+
+import "embed"
+
+// FS contains the MySQL migrations at its root and the PostgreSQL and SQLite ones
+// in the "postgres" and "sqlite" directories.
+//
+//go:embed *.sql postgres/*.sql sqlite/*.sql
+var FS embed.FS
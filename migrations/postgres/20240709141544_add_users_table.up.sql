@@ -0,0 +1,18 @@
+CREATE TABLE
+    IF NOT EXISTS "users"
+(
+    "id"         varchar(36)  NOT NULL,
+    "email"      varchar(127) NOT NULL,
+    "password"   varchar(191) NOT NULL,
+    "lastname"   varchar(63)  NOT NULL,
+    "firstname"  varchar(63)  NOT NULL,
+    "created_at" timestamp(3) NOT NULL,
+    "updated_at" timestamp(3) NOT NULL,
+    "deleted_at" timestamp(3) DEFAULT NULL,
+    PRIMARY KEY ("id")
+);
+
+-- Case-insensitive like the MySQL utf8mb4_general_ci collation
+CREATE UNIQUE INDEX IF NOT EXISTS "users_email_key" ON "users" (LOWER("email"));
+CREATE INDEX IF NOT EXISTS "idx_users_password" ON "users" ("password");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
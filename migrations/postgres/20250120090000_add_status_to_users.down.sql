@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "idx_users_status";
+
+ALTER TABLE "users"
+    DROP COLUMN "status_reason",
+    DROP COLUMN "status";
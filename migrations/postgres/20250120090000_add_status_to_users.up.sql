@@ -0,0 +1,5 @@
+ALTER TABLE "users"
+    ADD COLUMN "status"        varchar(15)  NOT NULL DEFAULT 'active',
+    ADD COLUMN "status_reason" varchar(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS "idx_users_status" ON "users" ("status");
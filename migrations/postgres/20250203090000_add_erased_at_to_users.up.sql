@@ -0,0 +1,2 @@
+ALTER TABLE "users"
+    ADD COLUMN "erased_at" timestamp(3) DEFAULT NULL;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS "idx_audit_events_organization_id";
+
+ALTER TABLE "audit_events"
+    DROP COLUMN "organization_id";
+
+DROP INDEX IF EXISTS "users_email_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "users_email_key" ON "users" (LOWER("email"));
+
+ALTER TABLE "users"
+    DROP COLUMN "organization_id";
+
+DROP TABLE IF EXISTS "organizations";
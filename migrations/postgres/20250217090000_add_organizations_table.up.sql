@@ -0,0 +1,32 @@
+CREATE TABLE
+    IF NOT EXISTS "organizations"
+(
+    "id"         varchar(36)  NOT NULL,
+    "name"       varchar(127) NOT NULL,
+    "slug"       varchar(63)  NOT NULL,
+    "created_at" timestamp(3) NOT NULL,
+    "updated_at" timestamp(3) NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "organizations_slug_key" ON "organizations" ("slug");
+
+-- Users and audit events existing before multi-tenancy belong to the default organization
+INSERT INTO "organizations" ("id", "name", "slug", "created_at", "updated_at")
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'default', NOW(), NOW());
+
+ALTER TABLE "users"
+    ADD COLUMN "organization_id" varchar(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE "users"
+    ALTER COLUMN "organization_id" DROP DEFAULT;
+
+-- Emails are unique per organization
+DROP INDEX IF EXISTS "users_email_key";
+CREATE UNIQUE INDEX IF NOT EXISTS "users_email_key" ON "users" ("organization_id", LOWER("email"));
+
+ALTER TABLE "audit_events"
+    ADD COLUMN "organization_id" varchar(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE "audit_events"
+    ALTER COLUMN "organization_id" DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS "idx_audit_events_organization_id" ON "audit_events" ("organization_id", "created_at");
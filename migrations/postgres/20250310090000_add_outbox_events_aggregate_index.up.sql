@@ -0,0 +1,2 @@
+-- Pending events are read without the aggregates blocked by an older event (see OutboxRepository.GetPending)
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_aggregate" ON "outbox_events" ("aggregate_type", "aggregate_id", "seq");
@@ -0,0 +1,17 @@
+CREATE TABLE
+    IF NOT EXISTS "users"
+(
+    "id"         varchar(36)  NOT NULL,
+    "email"      varchar(127) NOT NULL COLLATE NOCASE,
+    "password"   varchar(191) NOT NULL,
+    "lastname"   varchar(63)  NOT NULL,
+    "firstname"  varchar(63)  NOT NULL,
+    "created_at" datetime     NOT NULL,
+    "updated_at" datetime     NOT NULL,
+    "deleted_at" datetime DEFAULT NULL,
+    PRIMARY KEY ("id"),
+    UNIQUE ("email")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_users_password" ON "users" ("password");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
@@ -0,0 +1,18 @@
+CREATE TABLE
+    IF NOT EXISTS "audit_events"
+(
+    "id"          varchar(36) NOT NULL,
+    "actor_id"    varchar(36) NOT NULL DEFAULT '',
+    "action"      varchar(15) NOT NULL,
+    "target_type" varchar(31) NOT NULL,
+    "target_id"   varchar(36) NOT NULL,
+    "request_id"  varchar(36) NOT NULL DEFAULT '',
+    "ip"          varchar(45) NOT NULL DEFAULT '',
+    "changes"     text        NOT NULL,
+    "created_at"  datetime    NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_audit_events_actor_id" ON "audit_events" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_events_target" ON "audit_events" ("target_type", "target_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_events_created_at" ON "audit_events" ("created_at");
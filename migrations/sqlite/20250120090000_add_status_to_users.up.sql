@@ -0,0 +1,6 @@
+ALTER TABLE "users"
+    ADD COLUMN "status" varchar(15) NOT NULL DEFAULT 'active';
+ALTER TABLE "users"
+    ADD COLUMN "status_reason" varchar(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS "idx_users_status" ON "users" ("status");
@@ -0,0 +1,19 @@
+CREATE TABLE
+    IF NOT EXISTS "outbox_events"
+(
+    "seq"             integer     NOT NULL PRIMARY KEY AUTOINCREMENT,
+    "id"              varchar(36) NOT NULL,
+    "aggregate_type"  varchar(31) NOT NULL,
+    "aggregate_id"    varchar(36) NOT NULL,
+    "event_type"      varchar(63) NOT NULL,
+    "payload"         text        NOT NULL,
+    "request_id"      varchar(36) NOT NULL DEFAULT '',
+    "attempts"        integer     NOT NULL DEFAULT 0,
+    "last_error"      text        NULL,
+    "created_at"      datetime    NOT NULL,
+    "next_attempt_at" datetime    NOT NULL,
+    "published_at"    datetime    NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_outbox_events_id" ON "outbox_events" ("id");
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_published_at" ON "outbox_events" ("published_at", "seq");
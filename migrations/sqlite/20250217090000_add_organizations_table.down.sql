@@ -0,0 +1,39 @@
+DROP INDEX IF EXISTS "idx_audit_events_organization_id";
+
+ALTER TABLE "audit_events"
+    DROP COLUMN "organization_id";
+
+-- The users table is rebuilt with globally unique emails
+CREATE TABLE "users_global"
+(
+    "id"            varchar(36)  NOT NULL,
+    "email"         varchar(127) NOT NULL COLLATE NOCASE,
+    "password"      varchar(191) NOT NULL,
+    "lastname"      varchar(63)  NOT NULL,
+    "firstname"     varchar(63)  NOT NULL,
+    "created_at"    datetime     NOT NULL,
+    "updated_at"    datetime     NOT NULL,
+    "deleted_at"    datetime DEFAULT NULL,
+    "version"       integer      NOT NULL DEFAULT 1,
+    "status"        varchar(15)  NOT NULL DEFAULT 'active',
+    "status_reason" varchar(255) NOT NULL DEFAULT '',
+    "avatar"        varchar(255) NOT NULL DEFAULT '',
+    "erased_at"     datetime DEFAULT NULL,
+    PRIMARY KEY ("id"),
+    UNIQUE ("email")
+);
+
+INSERT INTO "users_global" ("id", "email", "password", "lastname", "firstname", "created_at", "updated_at",
+                            "deleted_at", "version", "status", "status_reason", "avatar", "erased_at")
+SELECT "id", "email", "password", "lastname", "firstname", "created_at", "updated_at",
+       "deleted_at", "version", "status", "status_reason", "avatar", "erased_at"
+FROM "users";
+
+DROP TABLE "users";
+ALTER TABLE "users_global" RENAME TO "users";
+
+CREATE INDEX IF NOT EXISTS "idx_users_password" ON "users" ("password");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_users_status" ON "users" ("status");
+
+DROP TABLE IF EXISTS "organizations";
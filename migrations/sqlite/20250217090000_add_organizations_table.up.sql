@@ -0,0 +1,54 @@
+CREATE TABLE
+    IF NOT EXISTS "organizations"
+(
+    "id"         varchar(36)  NOT NULL,
+    "name"       varchar(127) NOT NULL,
+    "slug"       varchar(63)  NOT NULL,
+    "created_at" datetime     NOT NULL,
+    "updated_at" datetime     NOT NULL,
+    PRIMARY KEY ("id"),
+    UNIQUE ("slug")
+);
+
+-- Users and audit events existing before multi-tenancy belong to the default organization
+INSERT INTO "organizations" ("id", "name", "slug", "created_at", "updated_at")
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'default', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+-- SQLite cannot change a UNIQUE constraint: the users table is rebuilt with emails unique per organization
+CREATE TABLE "users_organizations"
+(
+    "id"              varchar(36)  NOT NULL,
+    "organization_id" varchar(36)  NOT NULL,
+    "email"           varchar(127) NOT NULL COLLATE NOCASE,
+    "password"        varchar(191) NOT NULL,
+    "lastname"        varchar(63)  NOT NULL,
+    "firstname"       varchar(63)  NOT NULL,
+    "created_at"      datetime     NOT NULL,
+    "updated_at"      datetime     NOT NULL,
+    "deleted_at"      datetime DEFAULT NULL,
+    "version"         integer      NOT NULL DEFAULT 1,
+    "status"          varchar(15)  NOT NULL DEFAULT 'active',
+    "status_reason"   varchar(255) NOT NULL DEFAULT '',
+    "avatar"          varchar(255) NOT NULL DEFAULT '',
+    "erased_at"       datetime DEFAULT NULL,
+    PRIMARY KEY ("id"),
+    UNIQUE ("organization_id", "email")
+);
+
+INSERT INTO "users_organizations" ("id", "organization_id", "email", "password", "lastname", "firstname", "created_at",
+                                   "updated_at", "deleted_at", "version", "status", "status_reason", "avatar", "erased_at")
+SELECT "id", '00000000-0000-0000-0000-000000000001', "email", "password", "lastname", "firstname", "created_at",
+       "updated_at", "deleted_at", "version", "status", "status_reason", "avatar", "erased_at"
+FROM "users";
+
+DROP TABLE "users";
+ALTER TABLE "users_organizations" RENAME TO "users";
+
+CREATE INDEX IF NOT EXISTS "idx_users_password" ON "users" ("password");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_users_status" ON "users" ("status");
+
+ALTER TABLE "audit_events"
+    ADD COLUMN "organization_id" varchar(36) NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+
+CREATE INDEX IF NOT EXISTS "idx_audit_events_organization_id" ON "audit_events" ("organization_id", "created_at");
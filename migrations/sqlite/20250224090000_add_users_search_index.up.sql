@@ -0,0 +1 @@
+-- Users are searched with the embedded index built at startup (see search.UserIndex): no database change
@@ -0,0 +1,16 @@
+CREATE TABLE
+    IF NOT EXISTS "job_runs"
+(
+    "id"           varchar(36)   NOT NULL PRIMARY KEY,
+    "job"          varchar(63)   NOT NULL,
+    "instance"     varchar(255)  NOT NULL,
+    "status"       varchar(15)   NOT NULL,
+    "result"       varchar(255)  NOT NULL DEFAULT '',
+    "error"        varchar(1023) NOT NULL DEFAULT '',
+    "scheduled_at" datetime      NOT NULL,
+    "started_at"   datetime      NOT NULL,
+    "finished_at"  datetime      NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_job_runs_job" ON "job_runs" ("job", "scheduled_at");
+CREATE INDEX IF NOT EXISTS "idx_job_runs_started_at" ON "job_runs" ("started_at");
@@ -0,0 +1,260 @@
+package cache
+
+import (
+	"bufio"
+	"chi_boilerplate/pkg/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCache runs the same tests against every Cache implementation
+func testCache(t *testing.T, c repositories.Cache) {
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, "missing")
+	assert.ErrorIs(t, err, repositories.ErrCacheMiss)
+
+	assert.Nil(t, c.Set(ctx, "a", []byte("value a"), time.Minute))
+	assert.Nil(t, c.Set(ctx, "b", []byte("value\r\nb"), 0))
+
+	value, err := c.Get(ctx, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value a"), value)
+
+	value, err = c.Get(ctx, "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value\r\nb"), value)
+
+	assert.Nil(t, c.Set(ctx, "a", []byte("new value a"), time.Minute))
+	value, err = c.Get(ctx, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("new value a"), value)
+
+	assert.Nil(t, c.Delete(ctx, "a", "b", "missing"))
+	_, err = c.Get(ctx, "a")
+	assert.ErrorIs(t, err, repositories.ErrCacheMiss)
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, repositories.ErrCacheMiss)
+}
+
+func TestMemoryCache(t *testing.T) {
+	testCache(t, NewMemoryCache(10))
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2)
+
+	c.Set(ctx, "a", []byte("a"), 0)
+	c.Set(ctx, "b", []byte("b"), 0)
+	c.Get(ctx, "a") // b is now the least recently used entry
+	c.Set(ctx, "c", []byte("c"), 0)
+
+	assert.Equal(t, 2, c.Len())
+	_, err := c.Get(ctx, "b")
+	assert.ErrorIs(t, err, repositories.ErrCacheMiss)
+	_, err = c.Get(ctx, "a")
+	assert.Nil(t, err)
+	_, err = c.Get(ctx, "c")
+	assert.Nil(t, err)
+}
+
+func TestMemoryCacheExpiration(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	c := NewMemoryCache(10)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "a", []byte("a"), time.Minute)
+	c.Set(ctx, "b", []byte("b"), 0)
+
+	now = now.Add(59 * time.Second)
+	_, err := c.Get(ctx, "a")
+	assert.Nil(t, err)
+
+	now = now.Add(time.Second)
+	_, err = c.Get(ctx, "a")
+	assert.ErrorIs(t, err, repositories.ErrCacheMiss)
+	assert.Equal(t, 1, c.Len())
+
+	_, err = c.Get(ctx, "b")
+	assert.Nil(t, err)
+}
+
+func TestRedisCache(t *testing.T) {
+	server := newRedisStandIn(t, "secret")
+
+	_, err := NewRedisCache(RedisConfig{Addr: server.addr, Password: "wrong"})
+	assert.NotNil(t, err)
+
+	c, err := NewRedisCache(RedisConfig{Addr: server.addr, Password: "secret", DB: 1, Prefix: "test:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	testCache(t, c)
+
+	// Keys are prefixed and expire on the server
+	assert.Nil(t, c.Set(context.Background(), "a", []byte("a"), 1500*time.Millisecond))
+	assert.Equal(t, int64(1500), server.ttl("test:a"))
+}
+
+func TestRedisCacheUnavailable(t *testing.T) {
+	server := newRedisStandIn(t, "")
+
+	c, err := NewRedisCache(RedisConfig{Addr: server.addr, Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.listener.Close()
+	server.closeConns()
+
+	_, err = c.Get(context.Background(), "a")
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, repositories.ErrCacheMiss))
+}
+
+// redisStandIn is a minimal server of the Redis protocol supporting the commands of RedisCache
+type redisStandIn struct {
+	addr     string
+	password string
+	listener net.Listener
+
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]int64
+	conns  []net.Conn
+}
+
+func newRedisStandIn(t *testing.T, password string) *redisStandIn {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &redisStandIn{
+		addr:     l.Addr().String(),
+		password: password,
+		listener: l,
+		values:   make(map[string][]byte),
+		ttls:     make(map[string]int64),
+	}
+	t.Cleanup(func() {
+		l.Close()
+		s.closeConns()
+	})
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns = append(s.conns, conn)
+			s.mu.Unlock()
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *redisStandIn) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *redisStandIn) ttl(key string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ttls[key]
+}
+
+func (s *redisStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+
+		var args []string
+		for _, arg := range reply.([]any) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		var response string
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "AUTH":
+			authenticated = args[1] == s.password
+			response = "+OK\r\n"
+			if !authenticated {
+				response = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			response = "-NOAUTH Authentication required\r\n"
+		case cmd == "PING":
+			response = "+PONG\r\n"
+		case cmd == "SELECT":
+			response = "+OK\r\n"
+		default:
+			response = s.exec(cmd, args[1:])
+		}
+
+		if _, err := conn.Write([]byte(response)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *redisStandIn) exec(cmd string, args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch cmd {
+	case "GET":
+		value, ok := s.values[args[0]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "SET":
+		s.values[args[0]] = []byte(args[1])
+		delete(s.ttls, args[0])
+		if len(args) == 4 && strings.ToUpper(args[2]) == "PX" {
+			s.ttls[args[0]], _ = strconv.ParseInt(args[3], 10, 64)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		n := 0
+		for _, key := range args {
+			if _, ok := s.values[key]; ok {
+				delete(s.values, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	default:
+		return "-ERR unknown command '" + cmd + "'\r\n"
+	}
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryEntry is an entry of the LRU list
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // Zero if the entry never expires
+}
+
+// MemoryCache is an implementation of the Cache interface in the memory of the process.
+// The least recently used entries are evicted when the cache is full.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List // Most recently used first
+	now     func() time.Time
+}
+
+// NewMemoryCache creates a new MemoryCache holding up to size entries (Default: 10000)
+func NewMemoryCache(size int) *MemoryCache {
+	if size <= 0 {
+		size = 10000
+	}
+
+	return &MemoryCache{
+		size:    size,
+		entries: make(map[string]*list.Element, size),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// Get returns the value of a key or ErrCacheMiss
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, repositories.ErrCacheMiss
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.remove(elem)
+		return nil, repositories.ErrCacheMiss
+	}
+	c.lru.MoveToFront(elem)
+
+	return entry.value, nil
+}
+
+// Set stores the value of a key for ttl (0: no expiration)
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.lru.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.lru.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+
+	return nil
+}
+
+// Delete removes keys, missing keys are ignored
+func (c *MemoryCache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of entries, expired entries included
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *MemoryCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryEntry).key)
+}
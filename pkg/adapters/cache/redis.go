@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"bufio"
+	"chi_boilerplate/pkg/domain/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// errRedisNil is the null reply of Redis
+var errRedisNil = errors.New("redis: nil")
+
+// RedisConfig is the configuration of a RedisCache
+type RedisConfig struct {
+	Addr     string        // host:port
+	Password string        // Empty without authentication
+	DB       int           // Database number
+	Prefix   string        // Prefix of the keys (ex: "app:")
+	Timeout  time.Duration // Timeout of the connection and of each command without context deadline (Default: 1s)
+	PoolSize int           // Maximum number of idle connections (Default: 10)
+}
+
+// RedisCache is an implementation of the Cache interface using a server speaking the Redis protocol (RESP)
+// like Redis, Valkey or KeyDB.
+type RedisCache struct {
+	config RedisConfig
+	pool   chan *redisConn
+}
+
+// redisConn is a connection to the server
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// NewRedisCache creates a new RedisCache and checks the connection to the server
+func NewRedisCache(config RedisConfig) (*RedisCache, error) {
+	if config.Addr == "" {
+		return nil, errors.New("missing Redis address")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Second
+	}
+	if config.PoolSize <= 0 {
+		config.PoolSize = 10
+	}
+
+	c := &RedisCache{config: config, pool: make(chan *redisConn, config.PoolSize)}
+	if _, err := c.do(context.Background(), "PING"); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Get returns the value of a key or ErrCacheMiss
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.do(ctx, "GET", c.config.Prefix+key)
+	if errors.Is(err, errRedisNil) {
+		return nil, repositories.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return value.([]byte), nil
+}
+
+// Set stores the value of a key for ttl (0: no expiration)
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []any{"SET", c.config.Prefix + key, value}
+	if ttl > 0 {
+		args = append(args, "PX", ttl.Milliseconds())
+	}
+
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Delete removes keys, missing keys are ignored
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(keys)+1)
+	args = append(args, "DEL")
+	for _, key := range keys {
+		args = append(args, c.config.Prefix+key)
+	}
+
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Close closes the idle connections
+func (c *RedisCache) Close() error {
+	for {
+		select {
+		case conn := <-c.pool:
+			conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do sends a command and returns its reply: string, int64, []byte, []any or errRedisNil
+func (c *RedisCache) do(ctx context.Context, args ...any) (any, error) {
+	conn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(c.config.Timeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reply, err := conn.command(args...)
+	var redisErr redisError
+	if err != nil && !errors.Is(err, errRedisNil) && !errors.As(err, &redisErr) {
+		// The connection state is unknown after a network error
+		conn.Close()
+		return nil, err
+	}
+
+	select {
+	case c.pool <- conn:
+	default:
+		conn.Close()
+	}
+
+	return reply, err
+}
+
+// conn returns an idle connection or a new one
+func (c *RedisCache) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-c.pool:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.config.Timeout}
+	nc, err := dialer.DialContext(ctx, "tcp", c.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{Conn: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	if err := conn.SetDeadline(time.Now().Add(c.config.Timeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if c.config.Password != "" {
+		if _, err := conn.command("AUTH", c.config.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if c.config.DB != 0 {
+		if _, err := conn.command("SELECT", c.config.DB); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// redisError is an error reply of the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// command writes a command as an array of bulk strings and reads its reply
+func (c *redisConn) command(args ...any) (any, error) {
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		case int:
+			b = strconv.AppendInt(nil, int64(v), 10)
+		case int64:
+			b = strconv.AppendInt(nil, v, 10)
+		default:
+			return nil, fmt.Errorf("redis: unsupported argument type %T", arg)
+		}
+		fmt.Fprintf(c.w, "$%d\r\n", len(b))
+		c.w.Write(b)
+		c.w.WriteString("\r\n")
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return readReply(c.r)
+}
+
+// readReply reads a RESP reply
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: invalid reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, errRedisNil
+		}
+
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, errRedisNil
+		}
+
+		values := make([]any, n)
+		for i := range values {
+			if values[i], err = readReply(r); err != nil && !errors.Is(err, errRedisNil) {
+				return nil, err
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: invalid reply %q", line)
+	}
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultConnectTimeout is the default maximum wait of the database at startup
+	DefaultConnectTimeout = 30 * time.Second
+
+	// connectRetryInitialDelay is the delay before the first connection retry, doubled at each retry
+	connectRetryInitialDelay = 250 * time.Millisecond
+
+	// connectRetryMaxDelay is the maximum delay between two connection retries
+	connectRetryMaxDelay = 5 * time.Second
+)
+
+// pinger is a connection which can check that the database is reachable
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// closer is a connection holding a pool of connections to the database
+type closer interface {
+	Close() error
+}
+
+// WaitForConnection pings the database of the connection until it answers, with an exponential back-off,
+// and returns an error if it is still unreachable after timeout (Default: 30s).
+// Connections without database (in-memory repositories) are always ready.
+// Retries are logged with l if it is not nil.
+func WaitForConnection(ctx context.Context, conn Connection, timeout time.Duration, l logger.CustomLogger) error {
+	p, ok := conn.(pinger)
+	if !ok {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultConnectTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	delay := connectRetryInitialDelay
+	for attempt := 1; ; attempt++ {
+		err := p.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if l != nil {
+			l.Warn("Database unreachable, retrying", logger.Fields{
+				logger.NewField("attempt", "int", attempt),
+				logger.NewField("retry_in", "string", delay.String()),
+				logger.NewField("error", "error", err),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s database unreachable after %s: %w", conn.DriverName(), timeout, err)
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, connectRetryMaxDelay)
+	}
+}
+
+// Close closes the connections to the database (primary and replicas).
+// Connections without database (in-memory repositories) have nothing to close.
+func Close(conn Connection) error {
+	c, ok := conn.(closer)
+	if !ok {
+		return nil
+	}
+
+	return c.Close()
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingPinger is a connection whose database is reachable after some failed pings
+type failingPinger struct {
+	Connection
+	failures int
+	pings    int
+}
+
+func (p *failingPinger) Ping(ctx context.Context) error {
+	p.pings++
+	if p.pings <= p.failures {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+func (p *failingPinger) DriverName() string {
+	return DriverMySQL
+}
+
+func TestWaitForConnection(t *testing.T) {
+	conn, err := NewSqlxSQLite(&Config{Database: SQLiteMemory})
+	assert.Nil(t, err)
+	defer conn.DB.Close()
+
+	assert.Nil(t, WaitForConnection(context.Background(), conn, time.Second, nil))
+}
+
+func TestWaitForConnectionWithRetries(t *testing.T) {
+	l := &testLogger{}
+	conn := &failingPinger{failures: 2}
+
+	assert.Nil(t, WaitForConnection(context.Background(), conn, 5*time.Second, l))
+	assert.Equal(t, 3, conn.pings)
+	assert.Len(t, l.entries, 2)
+	assert.Equal(t, 2, l.entries[1].fields["attempt"])
+	assert.Equal(t, "500ms", l.entries[1].fields["retry_in"])
+}
+
+func TestWaitForConnectionWithUnreachableDatabase(t *testing.T) {
+	conn, err := NewSqlxMySQL(&Config{
+		Host:     "127.0.0.1",
+		Port:     1,
+		Username: "root",
+		Password: "root",
+		Database: "test",
+	})
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	start := time.Now()
+	err = WaitForConnection(context.Background(), conn, 300*time.Millisecond, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "mysql database unreachable after 300ms")
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestClose(t *testing.T) {
+	conn, err := NewSqlxSQLite(&Config{Database: SQLiteMemory})
+	assert.Nil(t, err)
+
+	assert.Nil(t, Close(conn))
+	assert.NotNil(t, conn.DB.Ping(), "the pool is closed")
+
+	// Connections without pool have nothing to close
+	assert.Nil(t, Close(&failingPinger{}))
+}
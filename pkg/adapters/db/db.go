@@ -1,8 +1,10 @@
 package db
 
 import (
+	"chi_boilerplate/pkg/infrastructure/logger"
 	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -16,21 +18,60 @@ const (
 	DefaultSlowThreshold time.Duration = 200 * time.Millisecond
 )
 
-// Config represents the MySQL database configuration
+// Database drivers
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory" // In-memory repositories (demo and tests)
+
+	// SQLiteMemory is the database name of an in-memory SQLite database
+	SQLiteMemory = ":memory:"
+)
+
+// Database backends (libraries used to query the database)
+const (
+	BackendSqlx = "sqlx"
+	BackendGorm = "gorm" // MySQL only
+)
+
+// Connection is a database connection, whatever its driver
+type Connection interface {
+	DriverName() string
+	DSN() (string, error)
+	Database(string)
+}
+
+// Config represents the database configuration
 type Config struct {
+	Driver          string // mysql | postgres | sqlite (Default: mysql)
+	Backend         string // sqlx | gorm (Default: sqlx)
 	Host            string
 	Username        string
 	Password        string
 	Port            int
-	Database        string
+	Database        string // Database name or file path for SQLite (":memory:" for an in-memory database)
 	Charset         string
 	Collation       string
 	Location        string
+	SSLMode         string        // PostgreSQL only (Default: disable)
 	MaxIdleConns    int           // Sets the maximum number of connections in the idle connection pool
 	MaxOpenConns    int           // Sets the maximum number of open connections to the database
 	ConnMaxLifetime time.Duration // Sets the maximum amount of time a connection may be reused
 	ConnMaxIdleTime time.Duration // Sets the maximum amount of time a connection in the idle may be reused
 	SlowThreshold   time.Duration // Slow SQL threshold (Default: 200ms)
+	LogParams       bool          // Log the queries parameters (Default: false, parameters may hold personal data)
+	TxIsolation     string        // read-uncommitted | read-committed | repeatable-read | serializable (Default: database default)
+
+	ReplicaDSNs                []string      // Read replicas DSNs (MySQL with sqlx only)
+	ReplicaHealthCheckInterval time.Duration // Interval between two health checks of the replicas (Default: 5s)
+
+	Logger logger.CustomLogger // Logger of the SQL queries (Default: nil, no logs)
+}
+
+// queryLogger returns the SQL queries logger of the configuration or nil if there is no logger
+func (c *Config) queryLogger() *QueryLogger {
+	return NewQueryLogger(c.Logger, c.SlowThreshold, c.LogParams)
 }
 
 // dsn returns the DSN if the configuration is OK or an error in other case
@@ -57,6 +98,47 @@ func (c *Config) dsn() (dsn string, err error) {
 	return
 }
 
+// postgresDSN returns the PostgreSQL DSN if the configuration is OK or an error in other case
+func (c *Config) postgresDSN() (dsn string, err error) {
+	if c.Host == "" || c.Port == 0 || c.Username == "" || c.Password == "" {
+		return dsn, errors.New("error in database configuration")
+	}
+
+	sslMode := c.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(c.Username, c.Password),
+		Host:   fmt.Sprintf("%s:%d", c.Host, c.Port),
+		Path:   "/" + c.Database,
+	}
+	q := url.Values{}
+	q.Set("sslmode", sslMode)
+	if c.Location == "UTC" {
+		q.Set("timezone", "UTC")
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// sqliteDSN returns the SQLite DSN if the configuration is OK or an error in other case
+func (c *Config) sqliteDSN() (dsn string, err error) {
+	if c.Database == "" {
+		return dsn, errors.New("error in database configuration")
+	}
+
+	dsn = "file:" + c.Database
+	if c.Database == SQLiteMemory {
+		dsn = SQLiteMemory
+	}
+
+	return dsn + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", nil
+}
+
 // PaginateValues transforms page and limit into offset and limit.
 func PaginateValues(p, l string) (offset int, limit int) {
 	page, err := strconv.Atoi(p)
@@ -74,29 +156,42 @@ func PaginateValues(p, l string) (offset int, limit int) {
 	return
 }
 
-// orderValues transforms list of fields to sort into a map.
-func orderValues(list string, prefixes ...string) []string {
-	r := make([]string, 0)
+// Sort is a field to sort by
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSorts parses a list of fields to sort.
+// Example: "+created_at,-id" will produce [{created_at false} {id true}].
+// Fields without "+" or "-" prefix are ignored.
+func ParseSorts(list string) []Sort {
+	r := make([]Sort, 0)
 
-	if len(list) <= 0 {
-		return r
+	for _, s := range strings.Split(list, ",") {
+		if len(s) > 2 && (strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-")) {
+			r = append(r, Sort{Field: s[1:], Desc: s[0] == '-'})
+		}
 	}
 
+	return r
+}
+
+// orderValues transforms list of fields to sort into a list of ORDER BY expressions.
+func orderValues(list string, prefixes ...string) []string {
+	r := make([]string, 0)
+
 	prefix := ""
 	if len(prefixes) == 1 {
 		prefix = prefixes[0] + "."
 	}
 
-	sorts := strings.Split(list, ",")
-	for _, s := range sorts {
-		if len(s) > 0 {
-			key := fmt.Sprintf("%s%s", prefix, s[1:])
-			if strings.HasPrefix(s, "+") && len(s[1:]) > 1 {
-				r = append(r, fmt.Sprintf("%s ASC", key))
-			} else if strings.HasPrefix(s, "-") && len(s[1:]) > 1 {
-				r = append(r, fmt.Sprintf("%s DESC", key))
-			}
+	for _, s := range ParseSorts(list) {
+		direction := "ASC"
+		if s.Desc {
+			direction = "DESC"
 		}
+		r = append(r, fmt.Sprintf("%s%s %s", prefix, s.Field, direction))
 	}
 
 	return r
@@ -113,3 +208,14 @@ func OrderValues(list string, prefixes ...string) (s string) {
 
 	return
 }
+
+// MySQLBooleanQuery returns a MySQL full-text query in boolean mode requiring all the terms as word prefixes
+// (ex: "+john* +doe*"). Terms must only contain letters and digits, which are not operators.
+func MySQLBooleanQuery(terms []string) string {
+	words := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words = append(words, "+"+term+"*")
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// mysqlErrDupEntry is the MySQL error number for a duplicate entry in a unique index
+	mysqlErrDupEntry = 1062
+
+	// postgresErrUniqueViolation is the PostgreSQL error code for a unique index violation
+	postgresErrUniqueViolation = "23505"
+)
+
+// IsDuplicateKeyError checks if an error returned by a database driver is a violation
+// of the unique index named index. If index is empty, any unique index violation matches.
+func IsDuplicateKeyError(err error, index string) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry {
+		// MySQL message: Duplicate entry 'value' for key 'index' (or 'table.index' since MySQL 8)
+		return index == "" ||
+			strings.HasSuffix(mysqlErr.Message, "'"+index+"'") ||
+			strings.HasSuffix(mysqlErr.Message, "."+index+"'")
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == postgresErrUniqueViolation {
+		// PostgreSQL constraint: index or <table>_<index>_key (default name of UNIQUE constraints)
+		return index == "" ||
+			pqErr.Constraint == index ||
+			strings.HasSuffix(pqErr.Constraint, "_"+index+"_key")
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY) {
+		// SQLite message: constraint failed: UNIQUE constraint failed: table.column (code)
+		msg := sqliteErr.Error()
+		if i := strings.LastIndex(msg, " ("); i > 0 {
+			msg = msg[:i]
+		}
+		return index == "" || strings.HasSuffix(msg, "."+index)
+	}
+
+	return false
+}
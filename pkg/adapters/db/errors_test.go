@@ -0,0 +1,112 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDuplicateKeyError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		index  string
+		wanted bool
+	}{
+		{
+			name:   "MySQL 5.7 duplicate entry",
+			err:    &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'test@test.com' for key 'email'"},
+			index:  "email",
+			wanted: true,
+		},
+		{
+			name:   "MySQL 8 duplicate entry",
+			err:    &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'test@test.com' for key 'users.email'"},
+			index:  "email",
+			wanted: true,
+		},
+		{
+			name:   "Wrapped duplicate entry",
+			err:    fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'test@test.com' for key 'email'"}),
+			index:  "email",
+			wanted: true,
+		},
+		{
+			name:   "Duplicate entry on another index",
+			err:    &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'abc' for key 'PRIMARY'"},
+			index:  "email",
+			wanted: false,
+		},
+		{
+			name:   "Duplicate entry on any index",
+			err:    &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'abc' for key 'PRIMARY'"},
+			index:  "",
+			wanted: true,
+		},
+		{
+			name:   "Other MySQL error",
+			err:    &mysql.MySQLError{Number: 1146, Message: "Table 'users' doesn't exist"},
+			index:  "email",
+			wanted: false,
+		},
+		{
+			name:   "PostgreSQL unique violation",
+			err:    &pq.Error{Code: "23505", Constraint: "users_email_key"},
+			index:  "email",
+			wanted: true,
+		},
+		{
+			name:   "PostgreSQL unique violation on another index",
+			err:    &pq.Error{Code: "23505", Constraint: "users_pkey"},
+			index:  "email",
+			wanted: false,
+		},
+		{
+			name:   "Other PostgreSQL error",
+			err:    &pq.Error{Code: "42P01"},
+			index:  "email",
+			wanted: false,
+		},
+		{
+			name:   "Other error",
+			err:    errors.New("connection refused"),
+			index:  "email",
+			wanted: false,
+		},
+		{
+			name:   "Nil error",
+			err:    nil,
+			index:  "email",
+			wanted: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wanted, IsDuplicateKeyError(tt.err, tt.index))
+		})
+	}
+}
+
+func TestIsDuplicateKeyErrorWithSQLite(t *testing.T) {
+	db, err := NewSqlxSQLite(&Config{Database: SQLiteMemory})
+	assert.Nil(t, err)
+	defer db.DB.Close()
+
+	_, err = db.DB.Exec("CREATE TABLE users (id TEXT PRIMARY KEY, email TEXT NOT NULL COLLATE NOCASE UNIQUE)")
+	assert.Nil(t, err)
+	_, err = db.DB.Exec("INSERT INTO users (id, email) VALUES ('1', 'test@test.com')")
+	assert.Nil(t, err)
+
+	_, err = db.DB.Exec("INSERT INTO users (id, email) VALUES ('2', 'TEST@test.com')")
+	assert.True(t, IsDuplicateKeyError(err, "email"))
+	assert.False(t, IsDuplicateKeyError(err, "id"))
+
+	_, err = db.DB.Exec("INSERT INTO users (id, email) VALUES ('1', 'other@test.com')")
+	assert.True(t, IsDuplicateKeyError(err, ""))
+	assert.False(t, IsDuplicateKeyError(err, "email"))
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger is a GORM logger writing with the application logger (see QueryLogger).
+// ErrRecordNotFound errors are not logged, as they are handled by the repositories.
+type GormLogger struct {
+	queries *QueryLogger
+	level   gormlogger.LogLevel
+}
+
+// NewGormLogger creates a new GormLogger logging all queries
+func NewGormLogger(queries *QueryLogger) *GormLogger {
+	return &GormLogger{queries: queries, level: gormlogger.Info}
+}
+
+// LogMode returns a copy of the logger with the log level
+func (g *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	l := *g
+	l.level = level
+	return &l
+}
+
+// Info logs a GORM info message
+func (g *GormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if g.level >= gormlogger.Info {
+		g.queries.logger.Info(fmt.Sprintf(msg, data...), gormFields(ctx))
+	}
+}
+
+// Warn logs a GORM warning message
+func (g *GormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if g.level >= gormlogger.Warn {
+		g.queries.logger.Warn(fmt.Sprintf(msg, data...), gormFields(ctx))
+	}
+}
+
+// Error logs a GORM error message
+func (g *GormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if g.level >= gormlogger.Error {
+		g.queries.logger.Error(fmt.Sprintf(msg, data...), gormFields(ctx))
+	}
+}
+
+// Trace logs a query executed by GORM
+func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if g.level <= gormlogger.Silent {
+		return
+	}
+
+	duration := time.Since(begin)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+
+	switch {
+	case err != nil && g.level >= gormlogger.Error,
+		duration > g.queries.slowThreshold && g.level >= gormlogger.Warn,
+		g.level >= gormlogger.Info:
+		sql, rows := fc()
+		g.queries.Log(ctx, sql, nil, duration, rows, err)
+	}
+}
+
+// ParamsFilter removes the parameters of the logged queries unless they must be logged.
+// Parameters are then displayed as placeholders in the SQL.
+func (g *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	if g.queries.logParams {
+		return sql, params
+	}
+	return sql, nil
+}
+
+// gormFields returns the log fields of a GORM message
+func gormFields(ctx context.Context) logger.Fields {
+	fields := logger.Fields{}
+	if id := logger.RequestID(ctx); id != "" {
+		fields = append(fields, logger.NewField("request_id", "string", id))
+	}
+	return fields
+}
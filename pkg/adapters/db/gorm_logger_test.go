@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLoggerTrace(t *testing.T) {
+	l := &testLogger{}
+	g := NewGormLogger(NewQueryLogger(l, 100*time.Millisecond, false))
+	ctx := context.Background()
+	fc := func() (string, int64) { return "SELECT * FROM users WHERE id = ?", 1 }
+
+	g.Trace(ctx, time.Now(), fc, nil)
+	g.Trace(ctx, time.Now(), fc, gorm.ErrRecordNotFound)
+	g.Trace(ctx, time.Now().Add(-time.Second), fc, nil)
+	g.Trace(ctx, time.Now(), fc, errors.New("error"))
+
+	assert.Len(t, l.entries, 4)
+	assert.Equal(t, "debug", l.entries[0].level)
+	assert.Equal(t, 1, l.entries[0].fields["rows"])
+	assert.Equal(t, "debug", l.entries[1].level)
+	assert.Equal(t, "warn", l.entries[2].level)
+	assert.Equal(t, "error", l.entries[3].level)
+}
+
+func TestGormLoggerLogMode(t *testing.T) {
+	l := &testLogger{}
+	g := NewGormLogger(NewQueryLogger(l, 100*time.Millisecond, false))
+	ctx := context.Background()
+	fc := func() (string, int64) { return "SELECT 1", -1 }
+
+	g.LogMode(gormlogger.Silent).Trace(ctx, time.Now(), fc, errors.New("error"))
+	assert.Len(t, l.entries, 0)
+
+	warn := g.LogMode(gormlogger.Warn)
+	warn.Trace(ctx, time.Now(), fc, nil)
+	assert.Len(t, l.entries, 0)
+	warn.Trace(ctx, time.Now().Add(-time.Second), fc, nil)
+	warn.Info(ctx, "info %d", 1)
+	warn.Warn(ctx, "warn %d", 2)
+	assert.Len(t, l.entries, 2)
+	assert.Equal(t, "warn 2", l.entries[1].msg)
+}
+
+func TestGormLoggerParamsFilter(t *testing.T) {
+	g := NewGormLogger(NewQueryLogger(&testLogger{}, 0, false))
+	sql, params := g.ParamsFilter(context.Background(), "SELECT ?", "secret")
+	assert.Equal(t, "SELECT ?", sql)
+	assert.Nil(t, params)
+
+	g = NewGormLogger(NewQueryLogger(&testLogger{}, 0, true))
+	_, params = g.ParamsFilter(context.Background(), "SELECT ?", "secret")
+	assert.Equal(t, []any{"secret"}, params)
+}
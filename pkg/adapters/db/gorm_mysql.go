@@ -1,10 +1,7 @@
 package db
 
 import (
-	"io"
-	"log"
-	"os"
-	"path"
+	"context"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -15,43 +12,28 @@ import (
 type GormMySQL struct {
 	DB     *gorm.DB
 	config *Config
-	// SlowThreshold   time.Duration // Slow SQL threshold (Default: 200ms)
 }
 
+// NewGormMySQL creates a new MySQL database connection using GORM
 func NewGormMySQL(config *Config) (*GormMySQL, error) {
 	dsn, err := config.dsn()
 	if err != nil {
 		return nil, err
 	}
-
-	if config.SlowThreshold == 0 {
-		config.SlowThreshold = DefaultSlowThreshold
-	}
-
-	// GORM logger configuration
-	// TODO: Get from .env file
-	env := "development"
-	level := getGormLogLevel("info", env)
-	output, err := getGormLogOutput("", "", env)
-	if err != nil {
+	if _, err := IsolationLevel(config.TxIsolation); err != nil {
 		return nil, err
 	}
 
-	// Logger
-	// TODO: Add a custom logger for GORM like https://www.soberkoder.com/go-gorm-logging/
-	// Or try something like this: https://github.com/moul/zapgorm2
-	customLogger := logger.New(
-		log.New(output, "\r\n", log.LstdFlags), // io writer
-		logger.Config{
-			SlowThreshold:             config.SlowThreshold, // Slow SQL threshold (Default: 200ms)
-			LogLevel:                  level,                // Log level (Silent, Error, Warn, Info) (Default: Warn)
-			IgnoreRecordNotFoundError: true,                 // Ignore ErrRecordNotFound error for logger (Default: false)
-			Colorful:                  true,                 // Disable color (Default: true)
-		},
-	)
+	// Queries are logged with the application logger if there is one
+	var gormLogger logger.Interface = logger.Discard
+	if queries := config.queryLogger(); queries != nil {
+		gormLogger = NewGormLogger(queries)
+	}
 
+	// The database is not pinged when opened, it is waited for with WaitForConnection
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: customLogger,
+		Logger:               gormLogger,
+		DisableAutomaticPing: true,
 	})
 	if err != nil {
 		return nil, err
@@ -78,6 +60,11 @@ func NewGormMySQL(config *Config) (*GormMySQL, error) {
 	}, nil
 }
 
+// DriverName returns the name of the database driver
+func (m *GormMySQL) DriverName() string {
+	return DriverMySQL
+}
+
 func (m *GormMySQL) DSN() (string, error) {
 	return m.config.dsn()
 }
@@ -86,46 +73,13 @@ func (m *GormMySQL) Database(d string) {
 	m.config.Database = d
 }
 
-// getGormLogLevel returns the log level for GORM.
-// If APP_ENV is development, the default log level is info,
-// warn in other case.
-// TODO: Add test
-func getGormLogLevel(level, env string) logger.LogLevel {
-	switch level {
-	case "silent":
-		return logger.Silent
-	case "info":
-		return logger.Info
-	case "warn":
-		return logger.Warn
-	case "error":
-		return logger.Error
-	default:
-		if env == "development" {
-			return logger.Warn
-		}
-		return logger.Error
-	}
-}
-
-// getGormLogOutput returns GORM log output.
-// The default value is os.Stdout.
-// In development mode, the ouput is set to os.Stdout.
-func getGormLogOutput(output, filePath, env string) (file io.Writer, err error) {
-	if env == "development" {
-		return os.Stdout, nil
-	}
-
-	switch output {
-	case "file":
-		f, err := os.OpenFile(path.Clean(filePath), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
-		if err != nil {
-			return nil, err
-		}
-		return f, nil
-	default:
-		return os.Stdout, nil
+// Ping checks that the database is reachable
+func (m *GormMySQL) Ping(ctx context.Context) error {
+	sqlDB, err := m.DB.DB()
+	if err != nil {
+		return err
 	}
+	return sqlDB.PingContext(ctx)
 }
 
 // GormPaginate creates a GORM scope to paginate queries.
@@ -143,10 +97,34 @@ func GormOrder(list string, prefixes ...string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		values := orderValues(list, prefixes...)
 
-		for s := range values {
-			db.Order(s)
+		for _, s := range values {
+			db = db.Order(s)
 		}
 
 		return db
 	}
 }
+
+// TxManager returns a transaction manager using the configured isolation level
+func (m *GormMySQL) TxManager() *GormTxManager {
+	isolation, _ := IsolationLevel(m.config.TxIsolation)
+	return NewGormTxManager(m.DB, isolation)
+}
+
+// JobLocker returns the advisory locks electing the instance running a scheduled job
+func (m *GormMySQL) JobLocker() (*MySQLLocker, error) {
+	sqlDB, err := m.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	return NewMySQLLocker(sqlDB, m.config.Database), nil
+}
+
+// Close closes the connection
+func (m *GormMySQL) Close() error {
+	sqlDB, err := m.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
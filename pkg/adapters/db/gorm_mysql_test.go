@@ -0,0 +1,68 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// dryRunGorm returns a GORM instance which builds SQL statements without executing them
+func dryRunGorm(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:password@tcp(localhost:3306)/db",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestGormOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		wanted string
+	}{
+		{
+			name:   "Simple sort",
+			args:   []string{"+id"},
+			wanted: "SELECT * FROM `users` ORDER BY id ASC",
+		},
+		{
+			name:   "Many fields",
+			args:   []string{"+id,-name,+created_at"},
+			wanted: "SELECT * FROM `users` ORDER BY id ASC,name DESC,created_at ASC",
+		},
+		{
+			name:   "Empty",
+			args:   []string{""},
+			wanted: "SELECT * FROM `users`",
+		},
+		{
+			name:   "With prefix",
+			args:   []string{"+id,-name", "u"},
+			wanted: "SELECT * FROM `users` ORDER BY u.id ASC,u.name DESC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var users []map[string]any
+			stmt := dryRunGorm(t).Table("users").Scopes(GormOrder(tt.args[0], tt.args[1:]...)).Find(&users).Statement
+
+			assert.Equal(t, tt.wanted, stmt.SQL.String())
+		})
+	}
+}
+
+func TestGormPaginate(t *testing.T) {
+	var users []map[string]any
+	stmt := dryRunGorm(t).Table("users").Scopes(GormPaginate("3", "20")).Find(&users).Statement
+
+	assert.Equal(t, "SELECT * FROM `users` LIMIT ? OFFSET ?", stmt.SQL.String())
+	assert.Equal(t, []any{20, 40}, stmt.Vars)
+}
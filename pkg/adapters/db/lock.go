@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ErrLocked is returned when a lock is held by another process
+var ErrLocked = errors.New("locked by another process")
+
+// mysqlLockNameMaxLength is the maximum length of the name of a MySQL lock
+const mysqlLockNameMaxLength = 64
+
+// MySQLLocker is a JobLocker using the advisory locks of MySQL (GET_LOCK), shared by all the instances
+// connected to the database: only one of them runs a job at a time.
+//
+// A lock is held by a dedicated connection of the pool until it is released.
+// If the instance holding it dies, MySQL releases it when the connection is closed.
+type MySQLLocker struct {
+	db     *sql.DB
+	prefix string
+}
+
+// NewMySQLLocker creates a new MySQLLocker. The names of the locks are prefixed with prefix
+// (the name of the database), so that the applications of a server don't share their locks.
+func NewMySQLLocker(db *sql.DB, prefix string) *MySQLLocker {
+	return &MySQLLocker{db: db, prefix: prefix}
+}
+
+// TryLock acquires the lock of a job without waiting: ok is false if another instance holds it
+func (l *MySQLLocker) TryLock(ctx context.Context, job string) (func(), bool, error) {
+	name := l.lockName(job)
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", name).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, false, err
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		var released sql.NullInt64
+		_ = conn.QueryRowContext(context.WithoutCancel(ctx), "SELECT RELEASE_LOCK(?)", name).Scan(&released)
+		_ = conn.Close()
+	}
+
+	return unlock, true, nil
+}
+
+// lockName returns the name of the lock of a job, hashed if it is longer than the MySQL limit
+func (l *MySQLLocker) lockName(job string) string {
+	name := l.prefix + ".job." + job
+	if len(name) <= mysqlLockNameMaxLength {
+		return name
+	}
+
+	sum := sha1.Sum([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+// PostgresLocker is a JobLocker using the advisory locks of PostgreSQL (pg_try_advisory_lock), shared by all the instances
+// connected to the database: only one of them runs a job at a time.
+//
+// A lock is held by a dedicated connection of the pool until it is released.
+// If the instance holding it dies, PostgreSQL releases it when the session ends.
+type PostgresLocker struct {
+	db *sql.DB
+}
+
+// NewPostgresLocker creates a new PostgresLocker. Advisory locks are scoped to the database.
+func NewPostgresLocker(db *sql.DB) *PostgresLocker {
+	return &PostgresLocker{db: db}
+}
+
+// TryLock acquires the lock of a job without waiting: ok is false if another instance holds it
+func (l *PostgresLocker) TryLock(ctx context.Context, job string) (func(), bool, error) {
+	key := postgresLockKey(job)
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		var released bool
+		_ = conn.QueryRowContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", key).Scan(&released)
+		_ = conn.Close()
+	}
+
+	return unlock, true, nil
+}
+
+// postgresLockKey returns the key of the advisory lock of a job (PostgreSQL locks are identified by a bigint)
+func postgresLockKey(job string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("job." + job))
+
+	return int64(h.Sum64())
+}
+
+// LocalLocker is a JobLocker for a single instance: the locks only exclude the runs of the current process.
+// It is used by the drivers without advisory locks.
+type LocalLocker struct {
+	mu     sync.Mutex
+	locked map[string]bool
+}
+
+// NewLocalLocker creates a new LocalLocker
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{locked: make(map[string]bool)}
+}
+
+// TryLock acquires the lock of a job without waiting: ok is false if it is already held
+func (l *LocalLocker) TryLock(ctx context.Context, job string) (func(), bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.locked[job] {
+		return nil, false, nil
+	}
+	l.locked[job] = true
+
+	var once sync.Once
+	unlock := func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			delete(l.locked, job)
+		})
+	}
+
+	return unlock, true, nil
+}
+
+// lockFile holds an exclusive lock on the SQLite database file path until unlock is called or the process exits.
+// The lock is an exclusive transaction left open on a dedicated connection: SQLite relies on the locks of the
+// file system, released by the operating system when the process dies.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(0)&_pragma=journal_mode(memory)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	conn, err := db.Conn(ctx)
+	if err == nil {
+		if _, err = conn.ExecContext(ctx, "BEGIN EXCLUSIVE"); err != nil {
+			_ = conn.Close()
+		}
+	}
+	if err != nil {
+		_ = db.Close()
+
+		var sqliteErr *sqlite.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY {
+			return nil, fmt.Errorf("%s: %w", path, ErrLocked)
+		}
+		return nil, err
+	}
+
+	var once sync.Once
+	unlock := func() {
+		once.Do(func() {
+			_, _ = conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK")
+			_ = conn.Close()
+			_ = db.Close()
+		})
+	}
+
+	return unlock, nil
+}
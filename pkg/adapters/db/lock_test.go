@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalLocker(t *testing.T) {
+	locker := NewLocalLocker()
+	ctx := context.Background()
+
+	unlock, ok, err := locker.TryLock(ctx, "job")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = locker.TryLock(ctx, "job")
+	assert.Nil(t, err)
+	assert.False(t, ok, "a held lock is not acquired again")
+
+	unlockOther, ok, err := locker.TryLock(ctx, "other")
+	assert.Nil(t, err)
+	assert.True(t, ok, "the locks of the jobs are independent")
+	unlockOther()
+
+	unlock()
+	unlock() // Releasing twice is harmless
+
+	unlock, ok, err = locker.TryLock(ctx, "job")
+	assert.Nil(t, err)
+	assert.True(t, ok, "a released lock is acquired")
+	unlock()
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, ok, err = locker.TryLock(cancelled, "job")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, ok)
+}
+
+func TestMySQLLockerLockName(t *testing.T) {
+	locker := NewMySQLLocker(nil, "api")
+	assert.Equal(t, "api.job.users-erasure", locker.lockName("users-erasure"))
+
+	name := locker.lockName(strings.Repeat("x", 64))
+	assert.Len(t, name, 40)
+	assert.Equal(t, name, locker.lockName(strings.Repeat("x", 64)))
+}
+
+func TestPostgresLockKey(t *testing.T) {
+	assert.Equal(t, postgresLockKey("users-erasure"), postgresLockKey("users-erasure"))
+	assert.NotEqual(t, postgresLockKey("users-erasure"), postgresLockKey("job-runs-purge"))
+}
+
+func TestSQLiteLockInstance(t *testing.T) {
+	ctx := context.Background()
+	database := filepath.Join(t.TempDir(), "api.db")
+
+	first, err := NewSqlxSQLite(&Config{Database: database})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { first.Close() })
+	second, err := NewSqlxSQLite(&Config{Database: database})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { second.Close() })
+
+	unlock, err := first.LockInstance(ctx, "scheduler")
+	assert.Nil(t, err)
+
+	_, err = second.LockInstance(ctx, "scheduler")
+	assert.ErrorIs(t, err, ErrLocked, "a held lock is not acquired by another connection")
+
+	unlockOther, err := second.LockInstance(ctx, "other")
+	assert.Nil(t, err, "the locks are independent")
+	unlockOther()
+
+	unlock()
+	unlock() // Releasing twice is harmless
+
+	unlock, err = second.LockInstance(ctx, "scheduler")
+	assert.Nil(t, err, "a released lock is acquired")
+	unlock()
+
+	// In-memory databases are private to the process
+	memory, err := NewSqlxSQLite(&Config{Database: SQLiteMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { memory.Close() })
+
+	unlock, err = memory.LockInstance(ctx, "scheduler")
+	assert.Nil(t, err)
+	unlock()
+}
@@ -0,0 +1,222 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// MigrationVersionFormat is the time format of the version of a new migration
+const MigrationVersionFormat = "20060102150405"
+
+// migrationsDirs are the migrations directories of the drivers, relative to the migrations root
+var migrationsDirs = map[string]string{
+	DriverMySQL:    ".",
+	DriverPostgres: "postgres",
+	DriverSQLite:   "sqlite",
+}
+
+// migrationNameRegexp validates the name of a new migration
+var migrationNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// MigrationsDir returns the migrations directory of a driver, relative to the migrations root
+func MigrationsDir(driver string) (string, error) {
+	dir, ok := migrationsDirs[driver]
+	if !ok {
+		return "", fmt.Errorf("no migrations for database driver: %s", driver)
+	}
+
+	return dir, nil
+}
+
+// MigrationStatus is the status of a migration
+type MigrationStatus struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// Migrator applies the SQL migrations to the database of a connection
+type Migrator struct {
+	m      *migrate.Migrate
+	fsys   fs.FS
+	dir    string
+	shared bool // The database connection is the one of the caller and must not be closed
+}
+
+// NewMigrator creates a new Migrator using the migrations of the connection driver in fsys.
+// MySQL migrations are at the root of fsys, PostgreSQL and SQLite ones in "postgres" and "sqlite".
+func NewMigrator(conn Connection, fsys fs.FS) (*Migrator, error) {
+	dir, err := MigrationsDir(conn.DriverName())
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// An in-memory SQLite database only exists through its connection, so migrations use it
+	if c, ok := conn.(*SqlxSQLite); ok {
+		driver, err := sqlite.WithInstance(c.DB.DB, &sqlite.Config{})
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := migrate.NewWithInstance("iofs", src, DriverSQLite, driver)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Migrator{m: m, fsys: fsys, dir: dir, shared: true}, nil
+	}
+
+	dsn, err := conn.DSN()
+	if err != nil {
+		return nil, err
+	}
+	if conn.DriverName() == DriverMySQL {
+		dsn = "mysql://" + dsn
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{m: m, fsys: fsys, dir: dir}, nil
+}
+
+// Up applies all the migrations not applied yet
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.m.Up())
+}
+
+// Down reverts the n last applied migrations
+func (m *Migrator) Down(n int) error {
+	if n < 1 {
+		return errors.New("the number of migrations to revert must be positive")
+	}
+
+	return ignoreNoChange(m.m.Steps(-n))
+}
+
+// Goto applies or reverts migrations to reach a version
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.m.Migrate(version))
+}
+
+// Force sets the version without running migrations and clears the dirty state (-1: no version)
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+// Version returns the current version (0 if no migration is applied) and whether it is dirty
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
+
+// Status returns all the migrations sorted by version with their applied state
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	version, _, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := m.list()
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Applied = list[i].Version <= version
+	}
+
+	return list, nil
+}
+
+// list returns the up migrations of the source sorted by version
+func (m *Migrator) list() ([]MigrationStatus, error) {
+	entries, err := fs.ReadDir(m.fsys, m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]MigrationStatus, 0)
+	for _, entry := range entries {
+		migration, err := source.Parse(entry.Name())
+		if err != nil || migration.Direction != source.Up {
+			continue
+		}
+
+		list = append(list, MigrationStatus{Version: migration.Version, Name: migration.Identifier})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+
+	return list, nil
+}
+
+// Close closes the migrations source and the database connection opened by the migrator
+func (m *Migrator) Close() error {
+	if m.shared {
+		return nil
+	}
+
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// ignoreNoChange returns nil if there was no migration to run
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
+
+// CreateMigration creates empty up and down migration files for every driver in the migrations root
+// and returns their paths. The version of the migration is the time t.
+func CreateMigration(root, name string, t time.Time) ([]string, error) {
+	if !migrationNameRegexp.MatchString(name) {
+		return nil, errors.New("the migration name must only contain lowercase letters, digits and underscores")
+	}
+
+	version := t.UTC().Format(MigrationVersionFormat)
+	dirs := []string{migrationsDirs[DriverMySQL], migrationsDirs[DriverPostgres], migrationsDirs[DriverSQLite]}
+
+	files := make([]string, 0, len(dirs)*2)
+	for _, dir := range dirs {
+		for _, direction := range []source.Direction{source.Up, source.Down} {
+			p := filepath.Join(root, dir, fmt.Sprintf("%s_%s.%s.sql", version, name, direction))
+
+			f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+			if err != nil {
+				return files, err
+			}
+			if err := f.Close(); err != nil {
+				return files, err
+			}
+			files = append(files, p)
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"chi_boilerplate/migrations"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestMigrator returns a migrator of the embedded migrations on an in-memory SQLite database
+func newTestMigrator(t *testing.T) *Migrator {
+	conn, err := NewSqlxSQLite(&Config{Database: SQLiteMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.DB.Close() })
+
+	m, err := NewMigrator(conn, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	return m
+}
+
+// appliedCount returns the number of applied migrations
+func appliedCount(t *testing.T, m *Migrator) int {
+	list, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for _, migration := range list {
+		if migration.Applied {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestMigrationsDir(t *testing.T) {
+	dir, err := MigrationsDir(DriverMySQL)
+	assert.Nil(t, err)
+	assert.Equal(t, ".", dir)
+
+	dir, err = MigrationsDir(DriverSQLite)
+	assert.Nil(t, err)
+	assert.Equal(t, "sqlite", dir)
+
+	_, err = MigrationsDir(DriverMemory)
+	assert.NotNil(t, err)
+}
+
+func TestEmbeddedMigrationsHaveSameVersions(t *testing.T) {
+	versions := make(map[string][]uint)
+	for driver, dir := range migrationsDirs {
+		list, err := (&Migrator{fsys: migrations.FS, dir: dir}).list()
+		assert.Nil(t, err)
+		assert.NotEmpty(t, list)
+
+		for _, migration := range list {
+			versions[driver] = append(versions[driver], migration.Version)
+		}
+	}
+
+	assert.Equal(t, versions[DriverMySQL], versions[DriverPostgres])
+	assert.Equal(t, versions[DriverMySQL], versions[DriverSQLite])
+}
+
+func TestMigrator(t *testing.T) {
+	m := newTestMigrator(t)
+
+	version, dirty, err := m.Version()
+	assert.Nil(t, err)
+	assert.Equal(t, uint(0), version)
+	assert.False(t, dirty)
+
+	list, err := m.Status()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, list)
+	assert.Equal(t, 0, appliedCount(t, m))
+
+	// Up
+	assert.Nil(t, m.Up())
+	assert.Equal(t, len(list), appliedCount(t, m))
+	version, _, _ = m.Version()
+	assert.Equal(t, list[len(list)-1].Version, version)
+
+	// Nothing to apply
+	assert.Nil(t, m.Up())
+
+	// Down
+	assert.Nil(t, m.Down(2))
+	assert.Equal(t, len(list)-2, appliedCount(t, m))
+	assert.NotNil(t, m.Down(0))
+
+	// Goto
+	assert.Nil(t, m.Goto(list[0].Version))
+	assert.Equal(t, 1, appliedCount(t, m))
+	assert.Nil(t, m.Goto(list[len(list)-1].Version))
+	assert.Equal(t, len(list), appliedCount(t, m))
+
+	// Force
+	assert.Nil(t, m.Force(int(list[1].Version)))
+	version, dirty, _ = m.Version()
+	assert.Equal(t, list[1].Version, version)
+	assert.False(t, dirty)
+}
+
+func TestCreateMigration(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"postgres", "sqlite"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Date(2025, 2, 10, 9, 30, 0, 0, time.UTC)
+
+	files, err := CreateMigration(root, "add_posts_table", now)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "20250210093000_add_posts_table.up.sql"),
+		filepath.Join(root, "20250210093000_add_posts_table.down.sql"),
+		filepath.Join(root, "postgres", "20250210093000_add_posts_table.up.sql"),
+		filepath.Join(root, "postgres", "20250210093000_add_posts_table.down.sql"),
+		filepath.Join(root, "sqlite", "20250210093000_add_posts_table.up.sql"),
+		filepath.Join(root, "sqlite", "20250210093000_add_posts_table.down.sql"),
+	}, files)
+	for _, file := range files {
+		assert.FileExists(t, file)
+	}
+
+	// Existing migration
+	_, err = CreateMigration(root, "add_posts_table", now)
+	assert.NotNil(t, err)
+
+	// Invalid name
+	_, err = CreateMigration(root, "Add posts", now)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// QueryLogger logs SQL queries with the application logger.
+// Failed queries are logged as errors, queries slower than the threshold as warnings and the others as debug.
+// Parameters are redacted unless logParams is set, as they may hold personal data or secrets.
+type QueryLogger struct {
+	logger        logger.CustomLogger
+	slowThreshold time.Duration
+	logParams     bool
+}
+
+// NewQueryLogger creates a new QueryLogger (Default slow threshold: 200ms).
+// A nil logger returns a nil QueryLogger which logs nothing.
+func NewQueryLogger(l logger.CustomLogger, slowThreshold time.Duration, logParams bool) *QueryLogger {
+	if l == nil {
+		return nil
+	}
+	if slowThreshold <= 0 {
+		slowThreshold = DefaultSlowThreshold
+	}
+
+	return &QueryLogger{logger: l, slowThreshold: slowThreshold, logParams: logParams}
+}
+
+// Log logs a query. rows is the number of affected rows or -1 if it is unknown.
+func (q *QueryLogger) Log(ctx context.Context, query string, params []any, duration time.Duration, rows int64, err error) {
+	if q == nil {
+		return
+	}
+
+	fields := logger.Fields{
+		logger.NewField("query", "string", compactQuery(query)),
+		logger.NewField("latency", "string", duration.String()),
+	}
+	if rows >= 0 {
+		fields = append(fields, logger.NewField("rows", "int", int(rows)))
+	}
+	if id := logger.RequestID(ctx); id != "" {
+		fields = append(fields, logger.NewField("request_id", "string", id))
+	}
+	if q.logParams && len(params) > 0 {
+		fields = append(fields, logger.NewField("params", "string", formatParams(params)))
+	}
+
+	switch {
+	case err != nil:
+		fields = append(fields, logger.NewField("error", "error", err))
+		q.logger.Error("SQL error", fields)
+	case duration > q.slowThreshold:
+		fields = append(fields, logger.NewField("threshold", "string", q.slowThreshold.String()))
+		q.logger.Warn("Slow SQL query", fields)
+	default:
+		q.logger.Debug("SQL query", fields)
+	}
+}
+
+// compactQuery replaces the indentation and new lines of a query by single spaces
+func compactQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// formatParams formats query parameters, []byte values being displayed as strings
+func formatParams(params []any) string {
+	values := make([]string, len(params))
+	for i, p := range params {
+		if b, ok := p.([]byte); ok {
+			p = string(b)
+		}
+		values[i] = fmt.Sprintf("%v", p)
+	}
+
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+// loggedExt is a sqlx.ExtContext logging its queries
+type loggedExt struct {
+	sqlx.ExtContext
+	logger *QueryLogger
+}
+
+// withQueryLogger returns ext logging its queries with l, or ext itself if l is nil
+func withQueryLogger(ext sqlx.ExtContext, l *QueryLogger) sqlx.ExtContext {
+	if l == nil {
+		return ext
+	}
+
+	return &loggedExt{ExtContext: ext, logger: l}
+}
+
+// ExecContext executes a query and logs it with the number of affected rows
+func (e *loggedExt) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := e.ExtContext.ExecContext(ctx, query, args...)
+
+	rows := int64(-1)
+	if err == nil {
+		if n, errRows := result.RowsAffected(); errRows == nil {
+			rows = n
+		}
+	}
+	e.logger.Log(ctx, query, args, time.Since(start), rows, err)
+
+	return result, err
+}
+
+// QueryContext executes a query returning rows and logs it
+func (e *loggedExt) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := e.ExtContext.QueryContext(ctx, query, args...)
+	e.logger.Log(ctx, query, args, time.Since(start), -1, err)
+
+	return rows, err
+}
+
+// QueryxContext executes a query returning rows and logs it
+func (e *loggedExt) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	start := time.Now()
+	rows, err := e.ExtContext.QueryxContext(ctx, query, args...)
+	e.logger.Log(ctx, query, args, time.Since(start), -1, err)
+
+	return rows, err
+}
+
+// QueryRowxContext executes a query returning at most one row and logs it
+func (e *loggedExt) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	start := time.Now()
+	row := e.ExtContext.QueryRowxContext(ctx, query, args...)
+	e.logger.Log(ctx, query, args, time.Since(start), -1, row.Err())
+
+	return row
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// logEntry is an entry logged by a testLogger
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]any
+}
+
+// testLogger is a logger.CustomLogger recording the logged entries
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) log(level, msg string, fields ...logger.Fields) {
+	entry := logEntry{level: level, msg: msg, fields: make(map[string]any)}
+	for _, list := range fields {
+		for _, f := range list {
+			entry.fields[f.Key] = f.Value
+		}
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func (l *testLogger) FromFields(fields logger.Fields) any       { return fields }
+func (l *testLogger) Debug(msg string, fields ...logger.Fields) { l.log("debug", msg, fields...) }
+func (l *testLogger) Info(msg string, fields ...logger.Fields)  { l.log("info", msg, fields...) }
+func (l *testLogger) Warn(msg string, fields ...logger.Fields)  { l.log("warn", msg, fields...) }
+func (l *testLogger) Error(msg string, fields ...logger.Fields) { l.log("error", msg, fields...) }
+func (l *testLogger) Fatal(msg string, fields ...logger.Fields) { l.log("fatal", msg, fields...) }
+func (l *testLogger) Panic(msg string, fields ...logger.Fields) { l.log("panic", msg, fields...) }
+
+func TestNewQueryLogger(t *testing.T) {
+	assert.Nil(t, NewQueryLogger(nil, 0, false))
+
+	q := NewQueryLogger(&testLogger{}, 0, false)
+	assert.Equal(t, DefaultSlowThreshold, q.slowThreshold)
+
+	// A nil logger logs nothing
+	var nilLogger *QueryLogger
+	nilLogger.Log(context.Background(), "SELECT 1", nil, time.Second, -1, nil)
+}
+
+func TestQueryLoggerLog(t *testing.T) {
+	l := &testLogger{}
+	q := NewQueryLogger(l, 100*time.Millisecond, false)
+	ctx := logger.WithRequestID(context.Background(), "request-id")
+
+	q.Log(ctx, "SELECT *\n\t\tFROM users\n\t\tWHERE id = ?", []any{"secret"}, time.Millisecond, -1, nil)
+	q.Log(ctx, "UPDATE users SET lastname = ?", []any{"Doe"}, time.Second, 3, nil)
+	q.Log(context.Background(), "DELETE FROM users", nil, time.Millisecond, -1, errors.New("error"))
+
+	assert.Len(t, l.entries, 3)
+
+	assert.Equal(t, "debug", l.entries[0].level)
+	assert.Equal(t, "SQL query", l.entries[0].msg)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", l.entries[0].fields["query"])
+	assert.Equal(t, "1ms", l.entries[0].fields["latency"])
+	assert.Equal(t, "request-id", l.entries[0].fields["request_id"])
+	assert.NotContains(t, l.entries[0].fields, "rows")
+	assert.NotContains(t, l.entries[0].fields, "params")
+
+	assert.Equal(t, "warn", l.entries[1].level)
+	assert.Equal(t, "Slow SQL query", l.entries[1].msg)
+	assert.Equal(t, 3, l.entries[1].fields["rows"])
+
+	assert.Equal(t, "error", l.entries[2].level)
+	assert.Equal(t, "SQL error", l.entries[2].msg)
+	assert.Equal(t, errors.New("error"), l.entries[2].fields["error"])
+	assert.NotContains(t, l.entries[2].fields, "request_id")
+}
+
+func TestQueryLoggerLogParams(t *testing.T) {
+	l := &testLogger{}
+	q := NewQueryLogger(l, 0, true)
+
+	q.Log(context.Background(), "SELECT * FROM users WHERE id = ? AND email = ?", []any{1, []byte("john@example.com")}, time.Millisecond, -1, nil)
+
+	assert.Len(t, l.entries, 1)
+	assert.Equal(t, "[1, john@example.com]", l.entries[0].fields["params"])
+}
+
+func TestResolverLogsQueries(t *testing.T) {
+	l := &testLogger{}
+	r := NewResolver(newTestSQLiteDB(t), nil, 0, NewQueryLogger(l, 0, false))
+	ctx := context.Background()
+
+	_, err := r.Primary(ctx).ExecContext(ctx, "CREATE TABLE t (id INTEGER)")
+	assert.Nil(t, err)
+	_, err = r.Primary(ctx).ExecContext(ctx, "INSERT INTO t (id) VALUES (?), (?)", 1, 2)
+	assert.Nil(t, err)
+
+	var count int
+	assert.Nil(t, r.Reader(ctx).QueryRowxContext(ctx, "SELECT COUNT(*) FROM t").Scan(&count))
+	assert.Equal(t, 2, count)
+
+	_, err = r.Reader(ctx).QueryxContext(ctx, "SELECT * FROM unknown")
+	assert.NotNil(t, err)
+
+	assert.Len(t, l.entries, 4)
+	assert.Equal(t, 2, l.entries[1].fields["rows"])
+	assert.Equal(t, "SELECT COUNT(*) FROM t", l.entries[2].fields["query"])
+	assert.Equal(t, "error", l.entries[3].level)
+}
+
+func TestResolverWithoutQueryLogger(t *testing.T) {
+	primary := newTestSQLiteDB(t)
+	r := NewResolver(primary, nil, 0, nil)
+
+	assert.Same(t, primary, r.Primary(context.Background()))
+}
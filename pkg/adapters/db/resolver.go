@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultReplicaHealthCheckInterval is the default interval between two health checks of the replicas
+const DefaultReplicaHealthCheckInterval = 5 * time.Second
+
+// readYourWritesKey is the context key of the read-your-writes tracker of a request
+type readYourWritesKey struct{}
+
+// readYourWrites records whether the request reads from the primary
+type readYourWrites struct {
+	written atomic.Bool
+	once    sync.Once
+	onWrite func()
+}
+
+// WithReadYourWrites returns a context whose reads go to the primary once a transaction has been started with it,
+// or from the start if written is true (the client has written recently), so that a request reads its own writes
+// whatever the replication lag. onWrite is called at the first transaction started with the context (nil: ignored),
+// ex: to route the next requests of the client to the primary as well.
+func WithReadYourWrites(ctx context.Context, written bool, onWrite func()) context.Context {
+	t := &readYourWrites{onWrite: onWrite}
+	t.written.Store(written)
+
+	return context.WithValue(ctx, readYourWritesKey{}, t)
+}
+
+// markWrite records a write for the read-your-writes tracker of the context if there is one
+func markWrite(ctx context.Context) {
+	if t, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites); ok {
+		t.written.Store(true)
+		if t.onWrite != nil {
+			t.once.Do(t.onWrite)
+		}
+	}
+}
+
+// hasWritten returns true if the read-your-writes tracker of the context has recorded a write
+func hasWritten(ctx context.Context) bool {
+	t, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites)
+	return ok && t.written.Load()
+}
+
+// replica is a read replica and its health
+type replica struct {
+	db      *sqlx.DB
+	healthy atomic.Bool
+}
+
+// Resolver routes read queries to the healthy read replicas (round-robin) and everything else to the primary.
+// Replicas are considered unhealthy until their first successful health check.
+// Queries are logged if the resolver has a query logger.
+type Resolver struct {
+	primary  *sqlx.DB
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+	logger   *QueryLogger
+}
+
+// NewResolver creates a new Resolver and starts the health checks of the replicas.
+// logger can be nil to not log queries.
+func NewResolver(primary *sqlx.DB, replicas []*sqlx.DB, checkInterval time.Duration, logger *QueryLogger) *Resolver {
+	r := &Resolver{
+		primary:  primary,
+		replicas: make([]*replica, 0, len(replicas)),
+		stop:     make(chan struct{}),
+		logger:   logger,
+	}
+	for _, db := range replicas {
+		r.replicas = append(r.replicas, &replica{db: db})
+	}
+
+	if len(r.replicas) > 0 {
+		if checkInterval <= 0 {
+			checkInterval = DefaultReplicaHealthCheckInterval
+		}
+		go r.healthChecks(checkInterval)
+	}
+
+	return r
+}
+
+// Primary returns the connection of a write query (or of a read query which must not be sent to a replica):
+// the transaction of the context or the primary.
+func (r *Resolver) Primary(ctx context.Context) sqlx.ExtContext {
+	return withQueryLogger(SqlxConn(ctx, r.primary), r.logger)
+}
+
+// Reader returns the connection of a read query: the transaction of the context,
+// the primary if the request has written (see WithReadYourWrites) or if no replica is healthy,
+// or else the next healthy replica.
+func (r *Resolver) Reader(ctx context.Context) sqlx.ExtContext {
+	return withQueryLogger(r.reader(ctx), r.logger)
+}
+
+// reader returns the connection of a read query without logging
+func (r *Resolver) reader(ctx context.Context) sqlx.ExtContext {
+	if tx, ok := ctx.Value(sqlxTxKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return r.readerDB(ctx)
+}
+
+// readerDB returns the database of a read query outside of a transaction:
+// the primary if the request has written or if no replica is healthy, or else the next healthy replica
+func (r *Resolver) readerDB(ctx context.Context) *sqlx.DB {
+	if len(r.replicas) == 0 || hasWritten(ctx) {
+		return r.primary
+	}
+
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := range n {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep.db
+		}
+	}
+
+	return r.primary
+}
+
+// CheckHealth pings the replicas and updates their health
+func (r *Resolver) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, rep := range r.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rep.healthy.Store(rep.db.PingContext(ctx) == nil)
+		}()
+	}
+	wg.Wait()
+}
+
+// healthChecks checks the replicas health at each interval until the resolver is closed
+func (r *Resolver) healthChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		r.CheckHealth(ctx)
+		cancel()
+
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the health checks and closes the replicas connections
+func (r *Resolver) Close() error {
+	var err error
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		for _, rep := range r.replicas {
+			if e := rep.db.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	})
+
+	return err
+}
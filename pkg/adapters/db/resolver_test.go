@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSQLiteDB returns a new in-memory SQLite database
+func newTestSQLiteDB(t *testing.T) *sqlx.DB {
+	conn, err := NewSqlxSQLite(&Config{Database: SQLiteMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.DB.Close() })
+
+	return conn.DB
+}
+
+// newTestResolver returns a resolver with healthy replicas
+func newTestResolver(t *testing.T, replicasCount int) (*Resolver, *sqlx.DB, []*sqlx.DB) {
+	primary := newTestSQLiteDB(t)
+	replicas := make([]*sqlx.DB, 0, replicasCount)
+	for range replicasCount {
+		replicas = append(replicas, newTestSQLiteDB(t))
+	}
+
+	r := NewResolver(primary, replicas, time.Hour, nil)
+	t.Cleanup(func() { r.Close() })
+	r.CheckHealth(context.Background())
+
+	return r, primary, replicas
+}
+
+func TestResolverWithoutReplicas(t *testing.T) {
+	r, primary, _ := newTestResolver(t, 0)
+
+	assert.Same(t, primary, r.Reader(context.Background()))
+}
+
+func TestResolverRoundRobin(t *testing.T) {
+	r, _, replicas := newTestResolver(t, 2)
+	ctx := context.Background()
+
+	// Pointers are compared, the replicas being identical
+	first := r.Reader(ctx)
+	second := r.Reader(ctx)
+	assert.True(t, first == replicas[0] || first == replicas[1])
+	assert.True(t, second == replicas[0] || second == replicas[1])
+	assert.NotSame(t, first, second)
+	assert.Same(t, first, r.Reader(ctx))
+}
+
+func TestResolverUnhealthyReplicas(t *testing.T) {
+	r, primary, replicas := newTestResolver(t, 2)
+	ctx := context.Background()
+
+	// Only the healthy replica is used
+	replicas[0].Close()
+	r.CheckHealth(ctx)
+	for range 3 {
+		assert.Same(t, replicas[1], r.Reader(ctx))
+	}
+
+	// No healthy replica: the primary is used
+	replicas[1].Close()
+	r.CheckHealth(ctx)
+	assert.Same(t, primary, r.Reader(ctx))
+}
+
+func TestResolverTransaction(t *testing.T) {
+	r, _, _ := newTestResolver(t, 1)
+
+	err := NewSqlxTxManager(r, 0).WithinTx(context.Background(), func(ctx context.Context) error {
+		_, ok := r.Reader(ctx).(*sqlx.Tx)
+		assert.True(t, ok)
+		return nil
+	})
+	assert.Nil(t, err)
+}
+
+func TestResolverReadTransaction(t *testing.T) {
+	r, primary, replicas := newTestResolver(t, 1)
+	for i, db := range []*sqlx.DB{primary, replicas[0]} {
+		_, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY)`)
+		assert.Nil(t, err)
+		for id := range i + 1 {
+			_, err = db.Exec(`INSERT INTO users (id) VALUES (?)`, id)
+			assert.Nil(t, err)
+		}
+	}
+	writes := 0
+	ctx := WithReadYourWrites(context.Background(), false, func() { writes++ })
+
+	// The list and its total are read in the same transaction on the replica
+	var total int
+	err := NewSqlxTxManager(r, 0).WithinReadTx(ctx, func(ctx context.Context) error {
+		var ids []int
+		if err := sqlx.SelectContext(ctx, r.Reader(ctx), &ids, `SELECT id FROM users ORDER BY id`); err != nil {
+			return err
+		}
+		assert.Equal(t, []int{0, 1}, ids)
+
+		return sqlx.GetContext(ctx, r.Reader(ctx), &total, `SELECT COUNT(*) FROM users`)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 0, writes, "a read-only transaction is not a write")
+	assert.Same(t, replicas[0], r.Reader(ctx))
+
+	// After a write, the request reads from the primary
+	assert.Nil(t, NewSqlxTxManager(r, 0).WithinTx(ctx, func(ctx context.Context) error { return nil }))
+	err = NewSqlxTxManager(r, 0).WithinReadTx(ctx, func(ctx context.Context) error {
+		return sqlx.GetContext(ctx, r.Reader(ctx), &total, `SELECT COUNT(*) FROM users`)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, total)
+}
+
+func TestResolverReadYourWrites(t *testing.T) {
+	r, primary, replicas := newTestResolver(t, 1)
+	writes := 0
+	ctx := WithReadYourWrites(context.Background(), false, func() { writes++ })
+
+	assert.Same(t, replicas[0], r.Reader(ctx))
+
+	for range 2 {
+		err := NewSqlxTxManager(r, 0).WithinTx(ctx, func(ctx context.Context) error {
+			return nil
+		})
+		assert.Nil(t, err)
+	}
+	assert.Same(t, primary, r.Reader(ctx))
+	assert.Equal(t, 1, writes, "onWrite is called once")
+
+	// Other requests still read from the replicas, unless their client has written recently
+	assert.Same(t, replicas[0], r.Reader(WithReadYourWrites(context.Background(), false, nil)))
+	assert.Same(t, replicas[0], r.Reader(context.Background()))
+	assert.Same(t, primary, r.Reader(WithReadYourWrites(context.Background(), true, nil)))
+}
+
+func TestNewSqlxMySQLWithReplicas(t *testing.T) {
+	config := Config{
+		Host:        "localhost",
+		Port:        3306,
+		Username:    "root",
+		Password:    "root",
+		Database:    "test",
+		Location:    "UTC",
+		ReplicaDSNs: []string{"root:root@tcp(replica:3306)/test"},
+	}
+
+	conn, err := NewSqlxMySQL(&config)
+	assert.Nil(t, err)
+	assert.Len(t, conn.Resolver().replicas, 1)
+	assert.Nil(t, conn.Close())
+
+	config.ReplicaDSNs = append(config.ReplicaDSNs, "invalid")
+	_, err = NewSqlxMySQL(&config)
+	assert.NotNil(t, err)
+}
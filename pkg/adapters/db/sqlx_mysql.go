@@ -1,14 +1,19 @@
 package db
 
 import (
-	_ "github.com/go-sql-driver/mysql"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 )
 
 // SqlxMySQL is a struct that contains the database connection
 type SqlxMySQL struct {
-	DB     *sqlx.DB
-	config *Config
+	DB       *sqlx.DB // Primary
+	config   *Config
+	resolver *Resolver
 }
 
 // NewSqlxMySQL creates a new MySQL database connection
@@ -17,6 +22,9 @@ func NewSqlxMySQL(config *Config) (*SqlxMySQL, error) {
 	if err != nil {
 		return nil, err
 	}
+	if _, err := IsolationLevel(config.TxIsolation); err != nil {
+		return nil, err
+	}
 
 	db, err := sqlx.Open("mysql", dsn)
 	if err != nil {
@@ -28,12 +36,57 @@ func NewSqlxMySQL(config *Config) (*SqlxMySQL, error) {
 	db.SetMaxOpenConns(config.MaxOpenConns)
 	db.SetMaxIdleConns(config.MaxIdleConns)
 
+	replicas := make([]*sqlx.DB, 0, len(config.ReplicaDSNs))
+	for _, replicaDSN := range config.ReplicaDSNs {
+		replica, err := openMySQLReplica(config, replicaDSN)
+		if err != nil {
+			for _, r := range replicas {
+				r.Close()
+			}
+			return nil, err
+		}
+		replicas = append(replicas, replica)
+	}
+
 	return &SqlxMySQL{
-		DB:     db,
-		config: config,
+		DB:       db,
+		config:   config,
+		resolver: NewResolver(db, replicas, config.ReplicaHealthCheckInterval, config.queryLogger()),
 	}, nil
 }
 
+// openMySQLReplica opens a read replica connection with the pool settings of the primary.
+// parseTime and the location of the configuration are forced on the replica DSN.
+func openMySQLReplica(config *Config, dsn string) (*sqlx.DB, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ParseTime = true
+	if config.Location != "" {
+		if cfg.Loc, err = time.LoadLocation(config.Location); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err := sqlx.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+
+	return db, nil
+}
+
+// DriverName returns the name of the database driver
+func (m *SqlxMySQL) DriverName() string {
+	return DriverMySQL
+}
+
 func (m *SqlxMySQL) DSN() (string, error) {
 	return m.config.dsn()
 }
@@ -41,3 +94,30 @@ func (m *SqlxMySQL) DSN() (string, error) {
 func (m *SqlxMySQL) Database(d string) {
 	m.config.Database = d
 }
+
+// Ping checks that the primary database is reachable
+func (m *SqlxMySQL) Ping(ctx context.Context) error {
+	return m.DB.PingContext(ctx)
+}
+
+// TxManager returns a transaction manager using the configured isolation level,
+// whose read-only transactions run on the replicas
+func (m *SqlxMySQL) TxManager() *SqlxTxManager {
+	isolation, _ := IsolationLevel(m.config.TxIsolation)
+	return NewSqlxTxManager(m.resolver, isolation)
+}
+
+// JobLocker returns the advisory locks electing the instance running a scheduled job
+func (m *SqlxMySQL) JobLocker() *MySQLLocker {
+	return NewMySQLLocker(m.DB.DB, m.config.Database)
+}
+
+// Resolver returns the resolver routing read queries to the replicas
+func (m *SqlxMySQL) Resolver() *Resolver {
+	return m.resolver
+}
+
+// Close closes the replicas and the primary connections
+func (m *SqlxMySQL) Close() error {
+	return errors.Join(m.resolver.Close(), m.DB.Close())
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// SqlxPostgres is a struct that contains the PostgreSQL database connection
+type SqlxPostgres struct {
+	DB       *sqlx.DB
+	config   *Config
+	resolver *Resolver
+}
+
+// NewSqlxPostgres creates a new PostgreSQL database connection
+func NewSqlxPostgres(config *Config) (*SqlxPostgres, error) {
+	dsn, err := config.postgresDSN()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := IsolationLevel(config.TxIsolation); err != nil {
+		return nil, err
+	}
+
+	db, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+
+	return &SqlxPostgres{
+		DB:       db,
+		config:   config,
+		resolver: NewResolver(db, nil, 0, config.queryLogger()),
+	}, nil
+}
+
+// DriverName returns the name of the database driver
+func (p *SqlxPostgres) DriverName() string {
+	return DriverPostgres
+}
+
+func (p *SqlxPostgres) DSN() (string, error) {
+	return p.config.postgresDSN()
+}
+
+func (p *SqlxPostgres) Database(d string) {
+	p.config.Database = d
+}
+
+// Ping checks that the database is reachable
+func (p *SqlxPostgres) Ping(ctx context.Context) error {
+	return p.DB.PingContext(ctx)
+}
+
+// TxManager returns a transaction manager using the configured isolation level
+func (p *SqlxPostgres) TxManager() *SqlxTxManager {
+	isolation, _ := IsolationLevel(p.config.TxIsolation)
+	return NewSqlxTxManager(p.resolver, isolation)
+}
+
+// JobLocker returns the advisory locks electing the instance running a scheduled job
+func (p *SqlxPostgres) JobLocker() *PostgresLocker {
+	return NewPostgresLocker(p.DB.DB)
+}
+
+// Resolver returns the resolver of the connection (no read replicas)
+func (p *SqlxPostgres) Resolver() *Resolver {
+	return p.resolver
+}
+
+// Close closes the connection
+func (p *SqlxPostgres) Close() error {
+	return errors.Join(p.resolver.Close(), p.DB.Close())
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+// SqlxSQLite is a struct that contains the SQLite database connection
+type SqlxSQLite struct {
+	DB       *sqlx.DB
+	config   *Config
+	resolver *Resolver
+}
+
+// NewSqlxSQLite creates a new SQLite database connection (pure Go driver, no CGO needed)
+func NewSqlxSQLite(config *Config) (*SqlxSQLite, error) {
+	dsn, err := config.sqliteDSN()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := IsolationLevel(config.TxIsolation); err != nil {
+		return nil, err
+	}
+
+	db, err := sqlx.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Database == SQLiteMemory {
+		// Each connection has its own in-memory database:
+		// the only connection must be kept open forever.
+		db.SetMaxOpenConns(1)
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxLifetime(0)
+		db.SetConnMaxIdleTime(0)
+	} else {
+		db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+		db.SetConnMaxLifetime(config.ConnMaxLifetime)
+		db.SetMaxOpenConns(config.MaxOpenConns)
+		db.SetMaxIdleConns(config.MaxIdleConns)
+	}
+
+	return &SqlxSQLite{
+		DB:       db,
+		config:   config,
+		resolver: NewResolver(db, nil, 0, config.queryLogger()),
+	}, nil
+}
+
+// DriverName returns the name of the database driver
+func (s *SqlxSQLite) DriverName() string {
+	return DriverSQLite
+}
+
+func (s *SqlxSQLite) DSN() (string, error) {
+	return s.config.sqliteDSN()
+}
+
+func (s *SqlxSQLite) Database(d string) {
+	s.config.Database = d
+}
+
+// Ping checks that the database is reachable
+func (s *SqlxSQLite) Ping(ctx context.Context) error {
+	return s.DB.PingContext(ctx)
+}
+
+// TxManager returns a transaction manager using the configured isolation level
+func (s *SqlxSQLite) TxManager() *SqlxTxManager {
+	isolation, _ := IsolationLevel(s.config.TxIsolation)
+	return NewSqlxTxManager(s.resolver, isolation)
+}
+
+// LockInstance prevents the other processes from taking the lock name on the database until unlock is called
+// or the process exits. The lock is held on the file <database>.<name>.lock.
+// It returns ErrLocked if another process holds it. An in-memory database is private to the process:
+// its lock is always acquired.
+func (s *SqlxSQLite) LockInstance(ctx context.Context, name string) (func(), error) {
+	if s.config.Database == SQLiteMemory {
+		return func() {}, nil
+	}
+
+	return lockFile(ctx, s.config.Database+"."+name+".lock")
+}
+
+// Resolver returns the resolver of the connection (no read replicas)
+func (s *SqlxSQLite) Resolver() *Resolver {
+	return s.resolver
+}
+
+// Close closes the connection
+func (s *SqlxSQLite) Close() error {
+	return errors.Join(s.resolver.Close(), s.DB.Close())
+}
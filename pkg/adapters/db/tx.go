@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"gorm.io/gorm"
+)
+
+// Transaction isolation levels (Default: isolation level of the database)
+const (
+	IsolationReadUncommitted = "read-uncommitted"
+	IsolationReadCommitted   = "read-committed"
+	IsolationRepeatableRead  = "repeatable-read"
+	IsolationSerializable    = "serializable"
+)
+
+// IsolationLevel returns the database/sql isolation level of a configured isolation level.
+// An empty value uses the default isolation level of the database.
+func IsolationLevel(isolation string) (sql.IsolationLevel, error) {
+	switch isolation {
+	case "":
+		return sql.LevelDefault, nil
+	case IsolationReadUncommitted:
+		return sql.LevelReadUncommitted, nil
+	case IsolationReadCommitted:
+		return sql.LevelReadCommitted, nil
+	case IsolationRepeatableRead:
+		return sql.LevelRepeatableRead, nil
+	case IsolationSerializable:
+		return sql.LevelSerializable, nil
+	default:
+		return sql.LevelDefault, fmt.Errorf("invalid transaction isolation level: %s", isolation)
+	}
+}
+
+// sqlxTxKey is the context key of the current sqlx transaction
+type sqlxTxKey struct{}
+
+// gormTxKey is the context key of the current GORM transaction
+type gormTxKey struct{}
+
+// SqlxTxManager runs functions in a sqlx transaction: on the primary, or on a replica if it is read-only
+type SqlxTxManager struct {
+	resolver  *Resolver
+	isolation sql.IsolationLevel
+}
+
+// NewSqlxTxManager creates a new SqlxTxManager on the databases of resolver
+func NewSqlxTxManager(resolver *Resolver, isolation sql.IsolationLevel) *SqlxTxManager {
+	return &SqlxTxManager{resolver: resolver, isolation: isolation}
+}
+
+// WithinTx runs fn in a transaction committed if fn succeeds and rolled back otherwise.
+// Repositories called with the context given to fn use the transaction (see SqlxConn).
+// If the context already holds a transaction, fn joins it.
+func (m *SqlxTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(sqlxTxKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.resolver.primary.BeginTxx(ctx, &sql.TxOptions{Isolation: m.isolation})
+	if err != nil {
+		return err
+	}
+	markWrite(ctx)
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, sqlxTxKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// WithinReadTx runs fn in a read-only transaction on the database of the read queries (see Resolver.Reader):
+// a replica unless the request has written. It is not a write for the read-your-writes tracker of the context.
+// If the context already holds a transaction, fn joins it.
+func (m *SqlxTxManager) WithinReadTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(sqlxTxKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.resolver.readerDB(ctx).BeginTxx(ctx, &sql.TxOptions{Isolation: m.isolation, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, sqlxTxKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SqlxConn returns the transaction of the context or db if there is none
+func SqlxConn(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(sqlxTxKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// GormTxManager runs functions in a GORM transaction
+type GormTxManager struct {
+	db        *gorm.DB
+	isolation sql.IsolationLevel
+}
+
+// NewGormTxManager creates a new GormTxManager
+func NewGormTxManager(db *gorm.DB, isolation sql.IsolationLevel) *GormTxManager {
+	return &GormTxManager{db: db, isolation: isolation}
+}
+
+// WithinTx runs fn in a transaction committed if fn succeeds and rolled back otherwise.
+// Repositories called with the context given to fn use the transaction (see GormConn).
+// If the context already holds a transaction, fn joins it.
+func (m *GormTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(gormTxKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, gormTxKey{}, tx))
+	}, &sql.TxOptions{Isolation: m.isolation})
+}
+
+// WithinReadTx runs fn in a read-only transaction.
+// If the context already holds a transaction, fn joins it.
+func (m *GormTxManager) WithinReadTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(gormTxKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, gormTxKey{}, tx))
+	}, &sql.TxOptions{Isolation: m.isolation, ReadOnly: true})
+}
+
+// GormConn returns the transaction of the context or db if there is none.
+// The returned session uses the context.
+func GormConn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(gormTxKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
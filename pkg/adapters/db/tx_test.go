@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSqlxTxManager returns a transaction manager on an in-memory SQLite database with an "items" table
+func newTestSqlxTxManager(t *testing.T) (*SqlxSQLite, *SqlxTxManager) {
+	conn, err := NewSqlxSQLite(&Config{Database: SQLiteMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.DB.Close() })
+
+	if _, err := conn.DB.Exec("CREATE TABLE items (name TEXT NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+
+	return conn, conn.TxManager()
+}
+
+// countItems returns the number of items with the transaction of the context if there is one
+func countItems(t *testing.T, ctx context.Context, conn *SqlxSQLite) int {
+	var count int
+	if err := SqlxConn(ctx, conn.DB).QueryRowxContext(ctx, "SELECT COUNT(*) FROM items").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+
+	return count
+}
+
+func TestIsolationLevel(t *testing.T) {
+	tests := []struct {
+		isolation string
+		wanted    sql.IsolationLevel
+		err       bool
+	}{
+		{isolation: "", wanted: sql.LevelDefault},
+		{isolation: IsolationReadUncommitted, wanted: sql.LevelReadUncommitted},
+		{isolation: IsolationReadCommitted, wanted: sql.LevelReadCommitted},
+		{isolation: IsolationRepeatableRead, wanted: sql.LevelRepeatableRead},
+		{isolation: IsolationSerializable, wanted: sql.LevelSerializable},
+		{isolation: "snapshot", wanted: sql.LevelDefault, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.isolation, func(t *testing.T) {
+			level, err := IsolationLevel(tt.isolation)
+			assert.Equal(t, tt.wanted, level)
+			assert.Equal(t, tt.err, err != nil)
+		})
+	}
+}
+
+func TestNewSqlxSQLiteWithInvalidTxIsolation(t *testing.T) {
+	_, err := NewSqlxSQLite(&Config{Database: SQLiteMemory, TxIsolation: "snapshot"})
+	assert.NotNil(t, err)
+}
+
+func TestSqlxTxManagerCommit(t *testing.T) {
+	conn, txManager := newTestSqlxTxManager(t)
+	ctx := context.Background()
+
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := SqlxConn(ctx, conn.DB).ExecContext(ctx, "INSERT INTO items (name) VALUES ('a'), ('b')")
+		if err != nil {
+			return err
+		}
+
+		// The transaction sees its own changes
+		assert.Equal(t, 2, countItems(t, ctx, conn))
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, countItems(t, ctx, conn))
+}
+
+func TestSqlxTxManagerRollback(t *testing.T) {
+	conn, txManager := newTestSqlxTxManager(t)
+	ctx := context.Background()
+	errTest := errors.New("test")
+
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := SqlxConn(ctx, conn.DB).ExecContext(ctx, "INSERT INTO items (name) VALUES ('a')"); err != nil {
+			return err
+		}
+		return errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, 0, countItems(t, ctx, conn))
+
+	assert.Panics(t, func() {
+		_ = txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if _, err := SqlxConn(ctx, conn.DB).ExecContext(ctx, "INSERT INTO items (name) VALUES ('a')"); err != nil {
+				return err
+			}
+			panic("test")
+		})
+	})
+	assert.Equal(t, 0, countItems(t, ctx, conn))
+}
+
+func TestSqlxTxManagerNested(t *testing.T) {
+	conn, txManager := newTestSqlxTxManager(t)
+	ctx := context.Background()
+	errTest := errors.New("test")
+
+	// The nested call joins the transaction: everything is rolled back
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			_, err := SqlxConn(ctx, conn.DB).ExecContext(ctx, "INSERT INTO items (name) VALUES ('a')")
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, 0, countItems(t, ctx, conn))
+}
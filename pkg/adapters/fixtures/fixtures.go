@@ -0,0 +1,73 @@
+package fixtures
+
+import (
+	"bytes"
+	"chi_boilerplate/pkg/domain/requests"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures formats
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// Fixtures are the data loaded in the database by the seed command
+type Fixtures struct {
+	Users []requests.UserSeed `json:"users" yaml:"users"`
+}
+
+// Load reads a fixtures file whose format is given by its extension (.json, .yaml or .yml)
+func Load(path string) (Fixtures, error) {
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = FormatJSON
+	case ".yaml", ".yml":
+		format = FormatYAML
+	default:
+		return Fixtures{}, fmt.Errorf("unsupported fixtures file extension: %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Fixtures{}, err
+	}
+
+	f, err := Parse(data, format)
+	if err != nil {
+		return Fixtures{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+// Parse decodes fixtures in JSON or YAML. Unknown fields are rejected to detect typos.
+func Parse(data []byte, format string) (Fixtures, error) {
+	var f Fixtures
+
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&f); err != nil {
+			return Fixtures{}, err
+		}
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&f); err != nil {
+			return Fixtures{}, err
+		}
+	default:
+		return Fixtures{}, fmt.Errorf("unsupported fixtures format: %s", format)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,56 @@
+package fixtures
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	yamlData := []byte(`
+users:
+  - email: john.doe@example.com
+    password: john1234
+    lastname: Doe
+    firstname: John
+    created_at: 2024-08-19T09:36:18Z
+`)
+	f, err := Parse(yamlData, FormatYAML)
+	assert.Nil(t, err)
+	assert.Len(t, f.Users, 1)
+	assert.Equal(t, "john.doe@example.com", f.Users[0].Email)
+	assert.Equal(t, "2024-08-19T09:36:18Z", f.Users[0].CreatedAt)
+
+	jsonData := []byte(`{"users": [{"email": "jane.doe@example.com", "password": "jane1234", "lastname": "Doe", "firstname": "Jane"}]}`)
+	f, err = Parse(jsonData, FormatJSON)
+	assert.Nil(t, err)
+	assert.Len(t, f.Users, 1)
+	assert.Equal(t, "Jane", f.Users[0].Firstname)
+
+	// Unknown fields
+	_, err = Parse([]byte("users:\n  - mail: john.doe@example.com\n"), FormatYAML)
+	assert.NotNil(t, err)
+	_, err = Parse([]byte(`{"users": [{"mail": "jane.doe@example.com"}]}`), FormatJSON)
+	assert.NotNil(t, err)
+
+	_, err = Parse(jsonData, "xml")
+	assert.NotNil(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	f, err := Load("../../../fixtures/users.yaml")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, f.Users)
+
+	p := filepath.Join(t.TempDir(), "users.txt")
+	if err := os.WriteFile(p, []byte("users: []"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(p)
+	assert.NotNil(t, err)
+
+	_, err = Load(filepath.Join(t.TempDir(), "unknown.json"))
+	assert.NotNil(t, err)
+}
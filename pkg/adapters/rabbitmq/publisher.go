@@ -0,0 +1,153 @@
+package rabbitmq
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"context"
+	"errors"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DefaultExchange is the default exchange of the domain events
+const DefaultExchange = "events"
+
+// Dial connects to a RabbitMQ server and opens a channel
+func Dial(url string) (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, ch, nil
+}
+
+// Publisher publishes domain events to a durable topic exchange, the event type being the routing key.
+// Messages are persistent and published with confirms: Publish returns once the broker has acknowledged the event.
+// The connection is opened on the first publication and opened again after an error.
+// It is safe for concurrent use, publications being serialized.
+type Publisher struct {
+	url      string
+	exchange string
+
+	mu   sync.Mutex
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+// NewPublisher creates a new Publisher (Default exchange: events)
+func NewPublisher(url, exchange string) *Publisher {
+	if exchange == "" {
+		exchange = DefaultExchange
+	}
+
+	return &Publisher{url: url, exchange: exchange}
+}
+
+// Publish publishes an event and waits for its confirmation.
+// The message ID is the event ID so that consumers can deduplicate events.
+func (p *Publisher) Publish(ctx context.Context, event entities.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch, err := p.channel()
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx,
+		p.exchange, // exchange
+		event.Type, // routing key
+		false,      // mandatory
+		false,      // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    event.ID,
+			Type:         event.Type,
+			Timestamp:    event.CreatedAt,
+			Headers: amqp.Table{
+				"aggregate_type": event.AggregateType,
+				"aggregate_id":   event.AggregateID,
+				"request_id":     event.RequestID,
+			},
+			Body: event.Payload,
+		},
+	)
+	if err != nil {
+		p.close()
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		p.close()
+		return err
+	}
+	if !acked {
+		return errors.New("event not acknowledged by the broker")
+	}
+
+	return nil
+}
+
+// Close closes the connection to the server
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.close()
+}
+
+// channel returns the confirm channel, opening the connection and declaring the exchange if needed
+func (p *Publisher) channel() (*amqp.Channel, error) {
+	if p.ch != nil && !p.ch.IsClosed() {
+		return p.ch, nil
+	}
+	p.close()
+
+	conn, ch, err := Dial(p.url)
+	if err != nil {
+		return nil, err
+	}
+	p.conn, p.ch = conn, ch
+
+	err = ch.ExchangeDeclare(
+		p.exchange, // name
+		"topic",    // type
+		true,       // durable
+		false,      // auto-deleted
+		false,      // internal
+		false,      // no-wait
+		nil,        // arguments
+	)
+	if err == nil {
+		err = ch.Confirm(false)
+	}
+	if err != nil {
+		p.close()
+		return nil, err
+	}
+
+	return ch, nil
+}
+
+// close closes the channel and the connection if they are open
+func (p *Publisher) close() error {
+	var err error
+	if p.conn != nil {
+		err = p.conn.Close()
+		if errors.Is(err, amqp.ErrClosed) {
+			err = nil
+		}
+	}
+	p.conn, p.ch = nil, nil
+
+	return err
+}
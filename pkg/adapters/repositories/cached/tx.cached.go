@@ -0,0 +1,69 @@
+package cached
+
+import (
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"context"
+	"sync"
+)
+
+// txKey is the context key of the invalidations of the current transaction
+type txKey struct{}
+
+// txInvalidations are the keys written in a transaction
+type txInvalidations struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+// TxManager is a TxManager decorator which keeps the cache consistent with transactions:
+// the cached repositories read the database in a read-write transaction, and the keys written in a transaction
+// are deleted again once it ends, so that a value read by a concurrent request before the commit is not kept.
+type TxManager struct {
+	tx    domain.TxManager
+	cache domain.Cache
+}
+
+// NewTxManager creates a new TxManager
+func NewTxManager(tx domain.TxManager, cache domain.Cache) *TxManager {
+	return &TxManager{tx: tx, cache: cache}
+}
+
+// WithinTx runs fn in a transaction of the decorated TxManager
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTx(ctx) {
+		return m.tx.WithinTx(ctx, fn)
+	}
+
+	invalidations := &txInvalidations{}
+	err := m.tx.WithinTx(context.WithValue(ctx, txKey{}, invalidations), fn)
+
+	if len(invalidations.keys) > 0 {
+		_ = m.cache.Delete(context.WithoutCancel(ctx), invalidations.keys...)
+	}
+
+	return err
+}
+
+// WithinReadTx runs fn in a read-only transaction of the decorated TxManager.
+// Nothing being written, no key is deleted when it ends.
+func (m *TxManager) WithinReadTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.tx.WithinReadTx(ctx, fn)
+}
+
+// inTx checks if the context is in a read-write transaction of a TxManager
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txInvalidations)
+	return ok
+}
+
+// invalidate deletes keys from the cache now and, in a transaction, when it ends.
+// Errors are ignored: stale values expire with their TTL.
+func invalidate(ctx context.Context, cache domain.Cache, keys ...string) {
+	_ = cache.Delete(context.WithoutCancel(ctx), keys...)
+
+	if invalidations, ok := ctx.Value(txKey{}).(*txInvalidations); ok {
+		invalidations.mu.Lock()
+		invalidations.keys = append(invalidations.keys, keys...)
+		invalidations.mu.Unlock()
+	}
+}
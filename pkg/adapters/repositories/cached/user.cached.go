@@ -0,0 +1,164 @@
+// Package cached provides repository decorators reading through a cache.
+package cached
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// userCountStatuses are the status filters of the cached numbers of users
+var userCountStatuses = []string{"", string(entities.UserStatusActive), string(entities.UserStatusSuspended), string(entities.UserStatusDisabled)}
+
+// UserRepository is a UserRepository decorator caching GetByID and CountAll.
+//
+// Cached values are scoped to the organization of the context and deleted by the writes of the decorator.
+// Concurrent misses of a key are loaded once from the decorated repository (singleflight).
+// Reads in a read-write transaction of a cached TxManager are not cached (see NewTxManager),
+// reads in a read-only transaction are: the lists count the users through the cache.
+// Errors of the cache are ignored: the decorated repository is used.
+type UserRepository struct {
+	domain.UserRepository
+
+	cache domain.Cache
+	ttl   time.Duration
+	group singleflight.Group
+}
+
+// NewUserRepository creates a new UserRepository caching the values for ttl
+func NewUserRepository(repository domain.UserRepository, cache domain.Cache, ttl time.Duration) *UserRepository {
+	return &UserRepository{UserRepository: repository, cache: cache, ttl: ttl}
+}
+
+// GetByID returns a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, req requests.UserByID) (responses.UserByIdRepository, error) {
+	key, ok := userKey(ctx, req.ID)
+	if !ok || inTx(ctx) {
+		return r.UserRepository.GetByID(ctx, req)
+	}
+
+	return load(ctx, r, key, func() (responses.UserByIdRepository, error) {
+		return r.UserRepository.GetByID(ctx, req)
+	})
+}
+
+// CountAll returns the number of users matching the filters
+func (r *UserRepository) CountAll(ctx context.Context, req requests.UsersList) (int64, error) {
+	key, ok := usersCountKey(ctx, req.Status)
+	if !ok || inTx(ctx) {
+		return r.UserRepository.CountAll(ctx, req)
+	}
+
+	return load(ctx, r, key, func() (int64, error) {
+		return r.UserRepository.CountAll(ctx, req)
+	})
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, req requests.UserCreationRepository) error {
+	defer r.invalidate(ctx, "", true)
+	return r.UserRepository.Create(ctx, req)
+}
+
+// Update updates a user
+func (r *UserRepository) Update(ctx context.Context, req requests.UserUpdateRepository) error {
+	defer r.invalidate(ctx, req.ID, false)
+	return r.UserRepository.Update(ctx, req)
+}
+
+// UpdateStatus changes the status of a user
+func (r *UserRepository) UpdateStatus(ctx context.Context, req requests.UserStatusUpdateRepository) error {
+	defer r.invalidate(ctx, req.ID, true)
+	return r.UserRepository.UpdateStatus(ctx, req)
+}
+
+// UpdateAvatar changes the avatar of a user
+func (r *UserRepository) UpdateAvatar(ctx context.Context, req requests.UserAvatarUpdateRepository) error {
+	defer r.invalidate(ctx, req.ID, false)
+	return r.UserRepository.UpdateAvatar(ctx, req)
+}
+
+// Delete deletes a user
+func (r *UserRepository) Delete(ctx context.Context, req requests.UserDelete) error {
+	defer r.invalidate(ctx, req.ID, true)
+	return r.UserRepository.Delete(ctx, req)
+}
+
+// Erase erases the personal data of a deleted user
+func (r *UserRepository) Erase(ctx context.Context, req requests.UserErasureRepository) error {
+	defer r.invalidate(ctx, req.ID, false)
+	return r.UserRepository.Erase(ctx, req)
+}
+
+// invalidate deletes the cached user (if id is not empty) and the cached numbers of users
+func (r *UserRepository) invalidate(ctx context.Context, id string, counts bool) {
+	var keys []string
+	if id != "" {
+		if key, ok := userKey(ctx, id); ok {
+			keys = append(keys, key)
+		}
+	}
+	if counts {
+		for _, status := range userCountStatuses {
+			if key, ok := usersCountKey(ctx, status); ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	if len(keys) > 0 {
+		invalidate(ctx, r.cache, keys...)
+	}
+}
+
+// load returns the cached value of key or loads it with fn once for concurrent callers and caches it
+func load[T any](ctx context.Context, r *UserRepository, key string, fn func() (T, error)) (T, error) {
+	var value T
+	if b, err := r.cache.Get(ctx, key); err == nil && json.Unmarshal(b, &value) == nil {
+		return value, nil
+	}
+
+	v, err, _ := r.group.Do(key, func() (any, error) {
+		value, err := fn()
+		if err != nil {
+			return value, err
+		}
+
+		if b, err := json.Marshal(value); err == nil {
+			_ = r.cache.Set(context.WithoutCancel(ctx), key, b, r.ttl)
+		}
+
+		return value, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	return v.(T), nil
+}
+
+// userKey returns the cache key of a user of the organization of the context
+func userKey(ctx context.Context, id string) (string, bool) {
+	organizationID, err := domain.Tenant(ctx)
+	if err != nil {
+		return "", false
+	}
+
+	return "users:" + organizationID + ":" + id, true
+}
+
+// usersCountKey returns the cache key of the number of users of the organization of the context with a status
+func usersCountKey(ctx context.Context, status string) (string, bool) {
+	organizationID, err := domain.Tenant(ctx)
+	if err != nil {
+		return "", false
+	}
+
+	return "users:" + organizationID + ":count:" + status, true
+}
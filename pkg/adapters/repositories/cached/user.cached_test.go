@@ -0,0 +1,214 @@
+package cached
+
+import (
+	"chi_boilerplate/pkg/adapters/cache"
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/domain/entities"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/pkg/domain/usecases"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	userID      = "8f2b6f4e-8f53-4a4c-9d1b-6a1f0f6c9e01"
+	otherTenant = "8f2b6f4e-8f53-4a4c-9d1b-6a1f0f6c9eff"
+)
+
+// countingRepository counts the reads of the decorated repository
+type countingRepository struct {
+	domain.UserRepository
+	gets, counts atomic.Int32
+	delay        time.Duration
+}
+
+func (r *countingRepository) GetByID(ctx context.Context, req requests.UserByID) (responses.UserByIdRepository, error) {
+	r.gets.Add(1)
+	time.Sleep(r.delay)
+	return r.UserRepository.GetByID(ctx, req)
+}
+
+func (r *countingRepository) CountAll(ctx context.Context, req requests.UsersList) (int64, error) {
+	r.counts.Add(1)
+	return r.UserRepository.CountAll(ctx, req)
+}
+
+// newTestRepository returns a cached repository of the default organization with one user
+func newTestRepository(t *testing.T) (*UserRepository, *countingRepository, *memory.Database, context.Context) {
+	ctx := domain.WithTenant(context.Background(), entities.DefaultOrganizationID)
+	database := memory.NewDatabase()
+	counting := &countingRepository{UserRepository: database.Users}
+
+	now := time.Now().Format(utils.SqlDateTimeFormat)
+	err := database.Users.Create(ctx, requests.UserCreationRepository{
+		ID: userID, Email: "john@example.com", Password: "hash", Lastname: "Doe", Firstname: "John", CreatedAt: now, UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewUserRepository(counting, cache.NewMemoryCache(100), time.Minute), counting, database, ctx
+}
+
+func TestUserRepositoryGetByID(t *testing.T) {
+	r, counting, _, ctx := newTestRepository(t)
+
+	for range 3 {
+		user, err := r.GetByID(ctx, requests.UserByID{ID: userID})
+		assert.Nil(t, err)
+		assert.Equal(t, "Doe", user.Lastname)
+	}
+	assert.Equal(t, int32(1), counting.gets.Load())
+
+	// Errors are not cached
+	for range 2 {
+		_, err := r.GetByID(ctx, requests.UserByID{ID: "f47ac10b-58cc-0372-8562-0b8e853961c1"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	}
+	assert.Equal(t, int32(3), counting.gets.Load())
+
+	// Users are cached by organization
+	_, err := r.GetByID(domain.WithTenant(context.Background(), otherTenant), requests.UserByID{ID: userID})
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestUserRepositoryInvalidation(t *testing.T) {
+	r, counting, _, ctx := newTestRepository(t)
+
+	_, err := r.GetByID(ctx, requests.UserByID{ID: userID})
+	assert.Nil(t, err)
+
+	err = r.Update(ctx, requests.UserUpdateRepository{
+		ID: userID, Email: "john@example.com", Password: "hash", Lastname: "Smith", Firstname: "John",
+		UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+	})
+	assert.Nil(t, err)
+
+	user, err := r.GetByID(ctx, requests.UserByID{ID: userID})
+	assert.Nil(t, err)
+	assert.Equal(t, "Smith", user.Lastname)
+	assert.Equal(t, int32(2), counting.gets.Load())
+
+	// Counts
+	total, err := r.CountAll(ctx, requests.UsersList{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	total, err = r.CountAll(ctx, requests.UsersList{Status: string(entities.UserStatusSuspended)})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+
+	_, _ = r.CountAll(ctx, requests.UsersList{})
+	assert.Equal(t, int32(2), counting.counts.Load())
+
+	err = r.UpdateStatus(ctx, requests.UserStatusUpdateRepository{
+		ID: userID, Status: string(entities.UserStatusSuspended), UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+	})
+	assert.Nil(t, err)
+
+	total, err = r.CountAll(ctx, requests.UsersList{Status: string(entities.UserStatusSuspended)})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	err = r.Delete(ctx, requests.UserDelete{ID: userID})
+	assert.Nil(t, err)
+
+	_, err = r.GetByID(ctx, requests.UserByID{ID: userID})
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	total, err = r.CountAll(ctx, requests.UsersList{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestUserRepositorySingleflight(t *testing.T) {
+	r, counting, _, ctx := newTestRepository(t)
+	counting.delay = 50 * time.Millisecond
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			user, err := r.GetByID(ctx, requests.UserByID{ID: userID})
+			assert.Nil(t, err)
+			assert.Equal(t, userID, user.ID)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), counting.gets.Load())
+}
+
+func TestUserRepositoryTransaction(t *testing.T) {
+	r, counting, database, ctx := newTestRepository(t)
+	tx := NewTxManager(database, r.cache)
+
+	_, err := r.GetByID(ctx, requests.UserByID{ID: userID})
+	assert.Nil(t, err)
+
+	errRollback := errors.New("rollback")
+	err = tx.WithinTx(ctx, func(txCtx context.Context) error {
+		// Reads in a transaction are not cached
+		_, err := r.GetByID(txCtx, requests.UserByID{ID: userID})
+		assert.Nil(t, err)
+		assert.Equal(t, int32(2), counting.gets.Load())
+
+		err = r.UpdateAvatar(txCtx, requests.UserAvatarUpdateRepository{
+			ID: userID, Avatar: "avatar.png", UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+		})
+		assert.Nil(t, err)
+
+		// A concurrent request caches the user before the end of the transaction
+		_, err = r.GetByID(ctx, requests.UserByID{ID: userID})
+		assert.Nil(t, err)
+		assert.Equal(t, int32(3), counting.gets.Load())
+
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	// The keys written in the transaction are deleted when it ends
+	_, err = r.GetByID(ctx, requests.UserByID{ID: userID})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(4), counting.gets.Load())
+}
+
+func TestUserRepositoryList(t *testing.T) {
+	r, counting, database, ctx := newTestRepository(t)
+	tx := NewTxManager(database, r.cache)
+	uc := usecases.NewUser(r, services.NewAudit(database.Audit), services.NewOutbox(database.Outbox), nil, tx)
+
+	// The lists read the number of users from the cache
+	for range 3 {
+		list, e := uc.GetAll(ctx, requests.UsersList{})
+		assert.Nil(t, e)
+		assert.Len(t, list.Data, 1)
+		assert.Equal(t, int64(1), list.Total)
+	}
+	assert.Equal(t, int32(1), counting.counts.Load())
+
+	// A user created in a transaction deletes the cached numbers of users
+	err := tx.WithinTx(ctx, func(txCtx context.Context) error {
+		now := time.Now().Format(utils.SqlDateTimeFormat)
+		return r.Create(txCtx, requests.UserCreationRepository{
+			ID: "8f2b6f4e-8f53-4a4c-9d1b-6a1f0f6c9e02", Email: "jane@example.com", Password: "hash", Lastname: "Doe", Firstname: "Jane", CreatedAt: now, UpdatedAt: now,
+		})
+	})
+	assert.Nil(t, err)
+
+	list, e := uc.GetAll(ctx, requests.UsersList{})
+	assert.Nil(t, e)
+	assert.Len(t, list.Data, 2)
+	assert.Equal(t, int64(2), list.Total)
+	assert.Equal(t, int32(2), counting.counts.Load())
+}
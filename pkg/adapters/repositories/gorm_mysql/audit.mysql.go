@@ -0,0 +1,210 @@
+package gorm_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditEvent is the GORM model of the audit_events table
+type AuditEvent struct {
+	ID             string `gorm:"primaryKey"`
+	OrganizationID string
+	ActorID        string
+	Action         string
+	TargetType     string
+	TargetID       string
+	RequestID      string
+	IP             string
+	Changes        string
+	CreatedAt      string
+}
+
+// auditSortColumns are the columns which can be used to sort audit events, like in the sqlx repositories
+var auditSortColumns = []string{"id", "actor_id", "action", "target_type", "target_id", "created_at"}
+
+// AuditMysqlRepository is an implementation of the AuditRepository interface
+type AuditMysqlRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditMysqlRepository creates a new AuditMysqlRepository
+func NewAuditMysqlRepository(db *db.GormMySQL) *AuditMysqlRepository {
+	return &AuditMysqlRepository{db: db.DB}
+}
+
+// Create creates a new audit event
+func (a *AuditMysqlRepository) Create(ctx context.Context, event requests.AuditEventCreationRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.GormConn(ctx, a.db).Create(&AuditEvent{
+		ID:             event.ID,
+		OrganizationID: organizationID,
+		ActorID:        event.ActorID,
+		Action:         event.Action,
+		TargetType:     event.TargetType,
+		TargetID:       event.TargetID,
+		RequestID:      event.RequestID,
+		IP:             event.IP,
+		Changes:        event.Changes,
+		CreatedAt:      event.CreatedAt,
+	}).Error
+}
+
+// CountAll returns the number of audit events matching the filters
+func (a *AuditMysqlRepository) CountAll(ctx context.Context, req requests.AuditEventsList) (int64, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	filters, err := auditFilters(req)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = db.GormConn(ctx, a.db).Model(&AuditEvent{}).Scopes(tenant(organizationID), filters).Count(&count).Error
+
+	return count, err
+}
+
+// GetAll returns the audit events matching the filters with pagination
+func (a *AuditMysqlRepository) GetAll(ctx context.Context, req requests.AuditEventsList) ([]responses.AuditEventsListRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := auditFilters(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range db.ParseSorts(req.Sorts) {
+		if !slices.Contains(auditSortColumns, s.Field) {
+			return nil, fmt.Errorf("%w: %s", repositories.ErrInvalidSort, s.Field)
+		}
+	}
+	sorts := req.Sorts
+	if len(db.OrderValues(sorts)) == 0 {
+		sorts = "-created_at"
+	}
+
+	return a.list(db.GormConn(ctx, a.db).Scopes(tenant(organizationID), filters, db.GormOrder(sorts), db.GormPaginate(req.Page, req.Limit)))
+}
+
+// GetByUser returns all the audit events performed by or on a user
+func (a *AuditMysqlRepository) GetByUser(ctx context.Context, userID string) ([]responses.AuditEventsListRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.list(db.GormConn(ctx, a.db).
+		Scopes(tenant(organizationID)).
+		Where("(actor_id = ? OR (target_type = 'user' AND target_id = ?))", userID, userID).
+		Order("created_at"))
+}
+
+// Erase replaces the IP and the changes of an audit event
+func (a *AuditMysqlRepository) Erase(ctx context.Context, req requests.AuditEventErasureRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return db.GormConn(ctx, a.db).Model(&AuditEvent{}).
+		Scopes(tenant(organizationID)).
+		Where("id = ?", req.ID).
+		Updates(map[string]any{
+			"ip":      req.IP,
+			"changes": req.Changes,
+		}).Error
+}
+
+// DeleteBefore deletes the audit events created before a date and returns their number
+func (a *AuditMysqlRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.GormConn(ctx, a.db).Scopes(tenant(organizationID)).Where("created_at < ?", before).Delete(&AuditEvent{})
+
+	return result.RowsAffected, result.Error
+}
+
+// list returns the audit events of a query
+func (a *AuditMysqlRepository) list(query *gorm.DB) ([]responses.AuditEventsListRepository, error) {
+	var events []AuditEvent
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]responses.AuditEventsListRepository, 0, len(events))
+	for _, event := range events {
+		list = append(list, responses.AuditEventsListRepository{
+			ID:         event.ID,
+			ActorID:    event.ActorID,
+			Action:     event.Action,
+			TargetType: event.TargetType,
+			TargetID:   event.TargetID,
+			RequestID:  event.RequestID,
+			IP:         event.IP,
+			Changes:    json.RawMessage(event.Changes),
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+
+	return list, nil
+}
+
+// auditFilters creates a GORM scope with the list filters
+func auditFilters(req requests.AuditEventsList) (func(db *gorm.DB) *gorm.DB, error) {
+	var from, to time.Time
+	var err error
+	if req.From != "" {
+		if from, err = time.Parse(time.RFC3339, req.From); err != nil {
+			return nil, err
+		}
+	}
+	if req.To != "" {
+		if to, err = time.Parse(time.RFC3339, req.To); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		if req.ActorID != "" {
+			db = db.Where("actor_id = ?", req.ActorID)
+		}
+		if req.Action != "" {
+			db = db.Where("action = ?", req.Action)
+		}
+		if req.TargetType != "" {
+			db = db.Where("target_type = ?", req.TargetType)
+		}
+		if req.TargetID != "" {
+			db = db.Where("target_id = ?", req.TargetID)
+		}
+		if !from.IsZero() {
+			db = db.Where("created_at >= ?", from.UTC())
+		}
+		if !to.IsZero() {
+			db = db.Where("created_at <= ?", to.UTC())
+		}
+
+		return db
+	}, nil
+}
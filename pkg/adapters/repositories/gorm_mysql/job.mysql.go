@@ -0,0 +1,93 @@
+package gorm_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// JobRun is the GORM model of the job_runs table
+type JobRun struct {
+	ID          string `gorm:"primaryKey"`
+	Job         string
+	Instance    string
+	Status      string
+	Result      string
+	Error       string
+	ScheduledAt string
+	StartedAt   string
+	FinishedAt  *string
+}
+
+// JobRunMysqlRepository is an implementation of the JobRunRepository interface
+type JobRunMysqlRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRunMysqlRepository creates a new JobRunMysqlRepository
+func NewJobRunMysqlRepository(db *db.GormMySQL) *JobRunMysqlRepository {
+	return &JobRunMysqlRepository{db: db.DB}
+}
+
+// Create records the start of a job run
+func (j *JobRunMysqlRepository) Create(ctx context.Context, run requests.JobRunCreationRepository) error {
+	return db.GormConn(ctx, j.db).Create(&JobRun{
+		ID:          run.ID,
+		Job:         run.Job,
+		Instance:    run.Instance,
+		Status:      string(entities.JobRunStatusRunning),
+		ScheduledAt: run.ScheduledAt,
+		StartedAt:   run.StartedAt,
+	}).Error
+}
+
+// Finish records the end of a job run
+func (j *JobRunMysqlRepository) Finish(ctx context.Context, req requests.JobRunFinishRepository) error {
+	return db.GormConn(ctx, j.db).Model(&JobRun{}).Where("id = ?", req.ID).Updates(map[string]any{
+		"status":      req.Status,
+		"result":      req.Result,
+		"error":       req.Error,
+		"finished_at": req.FinishedAt,
+	}).Error
+}
+
+// GetAll returns the latest runs first, of a job if req.Job is not empty
+func (j *JobRunMysqlRepository) GetAll(ctx context.Context, req requests.JobRunsList) ([]responses.JobRunRepository, error) {
+	query := db.GormConn(ctx, j.db).Order("scheduled_at DESC, started_at DESC").Limit(req.Limit)
+	if req.Job != "" {
+		query = query.Where("job = ?", req.Job)
+	}
+
+	var runs []JobRun
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]responses.JobRunRepository, 0, len(runs))
+	for _, run := range runs {
+		list = append(list, responses.JobRunRepository{
+			ID:          run.ID,
+			Job:         run.Job,
+			Instance:    run.Instance,
+			Status:      run.Status,
+			Result:      run.Result,
+			Error:       run.Error,
+			ScheduledAt: run.ScheduledAt,
+			StartedAt:   run.StartedAt,
+			FinishedAt:  run.FinishedAt,
+		})
+	}
+
+	return list, nil
+}
+
+// DeleteBefore deletes the runs started before a date and returns their number
+func (j *JobRunMysqlRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	result := db.GormConn(ctx, j.db).Where("started_at < ?", before).Delete(&JobRun{})
+
+	return result.RowsAffected, result.Error
+}
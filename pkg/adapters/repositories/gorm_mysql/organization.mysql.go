@@ -0,0 +1,96 @@
+package gorm_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Organization is the GORM model of the organizations table
+type Organization struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string
+	Slug      string
+	CreatedAt string
+	UpdatedAt string
+}
+
+// OrganizationMysqlRepository is an implementation of the OrganizationRepository interface
+type OrganizationMysqlRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationMysqlRepository creates a new OrganizationMysqlRepository
+func NewOrganizationMysqlRepository(db *db.GormMySQL) *OrganizationMysqlRepository {
+	return &OrganizationMysqlRepository{db: db.DB}
+}
+
+// Create creates a new organization
+func (o *OrganizationMysqlRepository) Create(ctx context.Context, req requests.OrganizationCreationRepository) error {
+	err := db.GormConn(ctx, o.db).Create(&Organization{
+		ID:        req.ID,
+		Name:      req.Name,
+		Slug:      req.Slug,
+		CreatedAt: req.CreatedAt,
+		UpdatedAt: req.UpdatedAt,
+	}).Error
+	if db.IsDuplicateKeyError(err, "slug") {
+		return repositories.ErrSlugAlreadyExists
+	}
+
+	return err
+}
+
+// GetByID returns an organization by ID
+func (o *OrganizationMysqlRepository) GetByID(ctx context.Context, req requests.OrganizationByID) (responses.OrganizationRepository, error) {
+	return o.get(db.GormConn(ctx, o.db).Where("id = ?", req.ID))
+}
+
+// GetBySlug returns an organization by slug
+func (o *OrganizationMysqlRepository) GetBySlug(ctx context.Context, req requests.OrganizationBySlug) (responses.OrganizationRepository, error) {
+	return o.get(db.GormConn(ctx, o.db).Where("slug = ?", req.Slug))
+}
+
+// GetAll returns all organizations sorted by slug
+func (o *OrganizationMysqlRepository) GetAll(ctx context.Context) ([]responses.OrganizationRepository, error) {
+	var organizations []Organization
+	if err := db.GormConn(ctx, o.db).Order("slug").Find(&organizations).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]responses.OrganizationRepository, 0, len(organizations))
+	for _, organization := range organizations {
+		list = append(list, organization.response())
+	}
+
+	return list, nil
+}
+
+// get returns the organization of a query
+func (o *OrganizationMysqlRepository) get(query *gorm.DB) (responses.OrganizationRepository, error) {
+	var organization Organization
+	if err := query.Take(&organization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return responses.OrganizationRepository{}, repositories.ErrOrganizationNotFound
+		}
+		return responses.OrganizationRepository{}, err
+	}
+
+	return organization.response(), nil
+}
+
+// response converts the model to a repository response
+func (o Organization) response() responses.OrganizationRepository {
+	return responses.OrganizationRepository{
+		ID:        o.ID,
+		Name:      o.Name,
+		Slug:      o.Slug,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
@@ -0,0 +1,122 @@
+package gorm_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is the GORM model of the outbox_events table
+type OutboxEvent struct {
+	Seq           int64 `gorm:"primaryKey;autoIncrement"`
+	ID            string
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       string
+	RequestID     string
+	Attempts      int
+	LastError     *string
+	CreatedAt     string
+	NextAttemptAt string
+	PublishedAt   *string
+}
+
+// OutboxMysqlRepository is an implementation of the OutboxRepository interface
+type OutboxMysqlRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxMysqlRepository creates a new OutboxMysqlRepository
+func NewOutboxMysqlRepository(db *db.GormMySQL) *OutboxMysqlRepository {
+	return &OutboxMysqlRepository{db: db.DB}
+}
+
+// Create saves a new event in the outbox
+func (o *OutboxMysqlRepository) Create(ctx context.Context, event requests.OutboxEventCreationRepository) error {
+	return db.GormConn(ctx, o.db).Create(&OutboxEvent{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       event.Payload,
+		RequestID:     event.RequestID,
+		CreatedAt:     event.CreatedAt,
+		NextAttemptAt: event.CreatedAt,
+	}).Error
+}
+
+// GetPending returns the events ready to be published in the order they were saved: events whose next attempt is due
+// and whose aggregate has no older event waiting for its next attempt.
+func (o *OutboxMysqlRepository) GetPending(ctx context.Context, req requests.OutboxEventsPendingRepository) ([]responses.OutboxEventRepository, error) {
+	var events []OutboxEvent
+	blocked := db.GormConn(ctx, o.db).Select("1").
+		Table("outbox_events b").
+		Where("b.aggregate_type = outbox_events.aggregate_type AND b.aggregate_id = outbox_events.aggregate_id").
+		Where("b.published_at IS NULL AND b.seq < outbox_events.seq AND b.next_attempt_at > ?", req.Now)
+	if err := db.GormConn(ctx, o.db).
+		Where("published_at IS NULL AND next_attempt_at <= ?", req.Now).
+		Where("NOT EXISTS (?)", blocked).
+		Order("seq").
+		Limit(req.Limit).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]responses.OutboxEventRepository, 0, len(events))
+	for _, event := range events {
+		list = append(list, responses.OutboxEventRepository{
+			Seq:           event.Seq,
+			ID:            event.ID,
+			AggregateType: event.AggregateType,
+			AggregateID:   event.AggregateID,
+			EventType:     event.EventType,
+			Payload:       json.RawMessage(event.Payload),
+			RequestID:     event.RequestID,
+			Attempts:      event.Attempts,
+			CreatedAt:     event.CreatedAt,
+			NextAttemptAt: event.NextAttemptAt,
+		})
+	}
+
+	return list, nil
+}
+
+// MarkPublished marks an event as published
+func (o *OutboxMysqlRepository) MarkPublished(ctx context.Context, req requests.OutboxEventPublishedRepository) error {
+	return db.GormConn(ctx, o.db).Model(&OutboxEvent{}).
+		Where("id = ?", req.ID).
+		Updates(map[string]any{
+			"published_at": req.PublishedAt,
+			"last_error":   nil,
+		}).Error
+}
+
+// MarkFailed records a failed publication of an event and the date of its next attempt
+func (o *OutboxMysqlRepository) MarkFailed(ctx context.Context, req requests.OutboxEventFailureRepository) error {
+	return db.GormConn(ctx, o.db).Model(&OutboxEvent{}).
+		Where("id = ?", req.ID).
+		Updates(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      req.LastError,
+			"next_attempt_at": req.NextAttemptAt,
+		}).Error
+}
+
+// Erase replaces the payloads of all the events of an aggregate
+func (o *OutboxMysqlRepository) Erase(ctx context.Context, req requests.OutboxEventsErasureRepository) error {
+	return db.GormConn(ctx, o.db).Model(&OutboxEvent{}).
+		Where("aggregate_type = ? AND aggregate_id = ?", req.AggregateType, req.AggregateID).
+		Update("payload", req.Payload).Error
+}
+
+// DeletePublishedBefore deletes the events published before a date and returns their number
+func (o *OutboxMysqlRepository) DeletePublishedBefore(ctx context.Context, before string) (int64, error) {
+	result := db.GormConn(ctx, o.db).Where("published_at < ?", before).Delete(&OutboxEvent{})
+
+	return result.RowsAffected, result.Error
+}
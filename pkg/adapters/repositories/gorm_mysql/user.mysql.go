@@ -2,11 +2,44 @@ package gorm_mysql
 
 import (
 	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
 
 	"gorm.io/gorm"
 )
 
-// UserMysqlRepository is an implementation of the UserRepository interface
+// User is the GORM model of the users table.
+// Dates are strings like with sqlx: they are written with the utils.SqlDateTimeFormat
+// format and read in RFC3339.
+// DeletedAt enables the GORM soft delete: queries and updates ignore deleted users
+// unless they are unscoped.
+type User struct {
+	ID             string `gorm:"primaryKey"`
+	OrganizationID string
+	Email          string
+	Password       string
+	Lastname       string
+	Firstname      string
+	Avatar         string
+	Status         string
+	StatusReason   string
+	Version        uint64
+	CreatedAt      string
+	UpdatedAt      string
+	DeletedAt      gorm.DeletedAt
+	ErasedAt       *string
+}
+
+// userSortColumns are the columns which can be used to sort users, like the columns of the users list in the sqlx repositories
+var userSortColumns = []string{"id", "email", "lastname", "firstname", "status", "created_at", "updated_at"}
+
+// UserMysqlRepository is an implementation of the UserRepository interface.
+// Users are scoped to the organization of the context (see repositories.WithTenant).
 type UserMysqlRepository struct {
 	db *gorm.DB
 }
@@ -15,3 +48,333 @@ type UserMysqlRepository struct {
 func NewUserMysqlRepository(db *db.GormMySQL) *UserMysqlRepository {
 	return &UserMysqlRepository{db: db.DB}
 }
+
+// Create creates a new user
+func (u *UserMysqlRepository) Create(ctx context.Context, user requests.UserCreationRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = db.GormConn(ctx, u.db).
+		Select("id", "organization_id", "email", "password", "lastname", "firstname", "created_at", "updated_at").
+		Create(&User{
+			ID:             user.ID,
+			OrganizationID: organizationID,
+			Email:          user.Email,
+			Password:       user.Password,
+			Lastname:       user.Lastname,
+			Firstname:      user.Firstname,
+			CreatedAt:      user.CreatedAt,
+			UpdatedAt:      user.UpdatedAt,
+		}).Error
+
+	if err != nil {
+		if db.IsDuplicateKeyError(err, "email") {
+			return repositories.ErrEmailAlreadyExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByID returns a user by ID
+func (u *UserMysqlRepository) GetByID(ctx context.Context, req requests.UserByID) (responses.UserByIdRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return responses.UserByIdRepository{}, err
+	}
+
+	var user User
+	err = db.GormConn(ctx, u.db).
+		Select("id", "organization_id", "email", "lastname", "firstname", "avatar", "status", "status_reason", "version", "created_at", "updated_at").
+		Scopes(tenant(organizationID)).
+		Where("id = ?", req.ID).
+		Take(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return responses.UserByIdRepository{}, repositories.ErrUserNotFound
+		}
+		return responses.UserByIdRepository{}, err
+	}
+
+	return responses.UserByIdRepository{
+		ID:             user.ID,
+		OrganizationID: user.OrganizationID,
+		Email:          user.Email,
+		Lastname:       user.Lastname,
+		Firstname:      user.Firstname,
+		Status:         user.Status,
+		StatusReason:   user.StatusReason,
+		Avatar:         user.Avatar,
+		Version:        user.Version,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+	}, nil
+}
+
+// GetByEmail returns a user by Email
+func (u *UserMysqlRepository) GetByEmail(ctx context.Context, req requests.GetByEmail) (responses.GetByEmail, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return responses.GetByEmail{}, err
+	}
+
+	var user User
+	err = db.GormConn(ctx, u.db).
+		Select("id", "password", "status").
+		Scopes(tenant(organizationID)).
+		Where("email = ?", req.Email).
+		Take(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return responses.GetByEmail{}, repositories.ErrUserNotFound
+		}
+		return responses.GetByEmail{}, err
+	}
+
+	r := responses.GetByEmailRepository{
+		ID:       user.ID,
+		Password: user.Password,
+		Status:   user.Status,
+	}
+	response, err := r.ToGetByEmail()
+	if err != nil {
+		return responses.GetByEmail{}, repositories.ErrUserNotFound
+	}
+
+	return response, nil
+}
+
+// Delete deletes a user (soft delete)
+func (u *UserMysqlRepository) Delete(ctx context.Context, req requests.UserDelete) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.update(ctx, organizationID, req.ID, req.Version, map[string]any{
+		"deleted_at": gorm.Expr("NOW()"),
+	})
+}
+
+// CountAll returns the number of users matching the filters
+func (u *UserMysqlRepository) CountAll(ctx context.Context, req requests.UsersList) (int64, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = db.GormConn(ctx, u.db).
+		Model(&User{}).
+		Scopes(tenant(organizationID), usersFilters(req)).
+		Count(&count).Error
+
+	return count, err
+}
+
+// GetAll returns the users matching the filters with pagination
+func (u *UserMysqlRepository) GetAll(ctx context.Context, req requests.UsersList) ([]responses.UsersListRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range db.ParseSorts(req.Sorts) {
+		if !slices.Contains(userSortColumns, s.Field) {
+			return nil, fmt.Errorf("%w: %s", repositories.ErrInvalidSort, s.Field)
+		}
+	}
+
+	var users []User
+	err = db.GormConn(ctx, u.db).
+		Select("id", "email", "lastname", "firstname", "status", "created_at", "updated_at").
+		Scopes(tenant(organizationID), usersFilters(req), db.GormOrder(req.Sorts), db.GormPaginate(req.Page, req.Limit)).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var list []responses.UsersListRepository
+	for _, user := range users {
+		list = append(list, responses.UsersListRepository{
+			ID:        user.ID,
+			Email:     user.Email,
+			Lastname:  user.Lastname,
+			Firstname: user.Firstname,
+			Status:    user.Status,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		})
+	}
+
+	return list, nil
+}
+
+// Update updates a user
+func (u *UserMysqlRepository) Update(ctx context.Context, req requests.UserUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = u.update(ctx, organizationID, req.ID, req.Version, map[string]any{
+		"lastname":   req.Lastname,
+		"firstname":  req.Firstname,
+		"email":      req.Email,
+		"password":   req.Password,
+		"updated_at": req.UpdatedAt,
+	})
+	if db.IsDuplicateKeyError(err, "email") {
+		return repositories.ErrEmailAlreadyExists
+	}
+
+	return err
+}
+
+// UpdateStatus changes the status of a user
+func (u *UserMysqlRepository) UpdateStatus(ctx context.Context, req requests.UserStatusUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.update(ctx, organizationID, req.ID, req.Version, map[string]any{
+		"status":        req.Status,
+		"status_reason": req.Reason,
+		"updated_at":    req.UpdatedAt,
+	})
+}
+
+// UpdateAvatar changes the avatar of a user
+func (u *UserMysqlRepository) UpdateAvatar(ctx context.Context, req requests.UserAvatarUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	return u.update(ctx, organizationID, req.ID, req.Version, map[string]any{
+		"avatar":     req.Avatar,
+		"updated_at": req.UpdatedAt,
+	})
+}
+
+// GetErasable returns the deleted users whose personal data have not been erased yet
+func (u *UserMysqlRepository) GetErasable(ctx context.Context, req requests.UsersErasableRepository) ([]responses.UserErasableRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []User
+	err = db.GormConn(ctx, u.db).
+		Select("id", "avatar").
+		Scopes(erasable, tenant(organizationID)).
+		Where("deleted_at <= ?", req.DeletedBefore).
+		Order("deleted_at").
+		Limit(req.Limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]responses.UserErasableRepository, 0, len(users))
+	for _, user := range users {
+		list = append(list, responses.UserErasableRepository{ID: user.ID, Avatar: user.Avatar})
+	}
+
+	return list, nil
+}
+
+// Erase anonymises the personal data of a deleted user.
+// The row is kept so that audit events and other references remain valid.
+func (u *UserMysqlRepository) Erase(ctx context.Context, req requests.UserErasureRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := db.GormConn(ctx, u.db).
+		Model(&User{}).
+		Scopes(erasable, tenant(organizationID)).
+		Where("id = ?", req.ID).
+		Updates(map[string]any{
+			"email":         req.Email,
+			"password":      "",
+			"lastname":      "",
+			"firstname":     "",
+			"avatar":        "",
+			"status_reason": "",
+			"erased_at":     req.ErasedAt,
+			"updated_at":    req.ErasedAt,
+			"version":       gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// update updates the values of a user not deleted and increments its version.
+// If version is not 0, the user is only updated if its version is still the same.
+func (u *UserMysqlRepository) update(ctx context.Context, organizationID, id string, version uint64, values map[string]any) error {
+	values["version"] = gorm.Expr("version + 1")
+
+	query := db.GormConn(ctx, u.db).Model(&User{}).Scopes(tenant(organizationID)).Where("id = ?", id)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return u.notAffectedError(ctx, organizationID, id)
+	}
+
+	return nil
+}
+
+// notAffectedError returns the error explaining why a mutation did not affect the user:
+// either the user does not exist anymore or its version has changed.
+func (u *UserMysqlRepository) notAffectedError(ctx context.Context, organizationID, id string) error {
+	var count int64
+	err := db.GormConn(ctx, u.db).Model(&User{}).Scopes(tenant(organizationID)).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repositories.ErrUserNotFound
+	}
+
+	return repositories.ErrUserVersionMismatch
+}
+
+// usersFilters creates a GORM scope with the list filters
+func usersFilters(req requests.UsersList) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if req.Status != "" {
+			db = db.Where("status = ?", req.Status)
+		}
+
+		return db
+	}
+}
+
+// erasable is a GORM scope selecting the deleted users whose personal data have not been erased yet
+func erasable(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("deleted_at IS NOT NULL AND erased_at IS NULL")
+}
+
+// tenant is a GORM scope selecting the rows of an organization
+func tenant(organizationID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("organization_id = ?", organizationID)
+	}
+}
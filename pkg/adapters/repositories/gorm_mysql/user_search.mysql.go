@@ -0,0 +1,70 @@
+package gorm_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// UserSearchMysqlRepository is an implementation of the UserSearchRepository interface
+// using the FULLTEXT index of the users table (see the idx_users_search migration).
+type UserSearchMysqlRepository struct {
+	db *gorm.DB
+}
+
+// NewUserSearchMysqlRepository creates a new UserSearchMysqlRepository
+func NewUserSearchMysqlRepository(db *db.GormMySQL) *UserSearchMysqlRepository {
+	return &UserSearchMysqlRepository{db: db.DB}
+}
+
+// Search returns the users matching all the words of the query, the most relevant first
+func (u *UserSearchMysqlRepository) Search(ctx context.Context, req requests.UserSearchRepository) ([]responses.UserSearchHit, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.MySQLBooleanQuery(req.Terms())
+	if query == "" {
+		return nil, nil
+	}
+
+	var users []struct {
+		User
+		Score float64
+	}
+	err = db.GormConn(ctx, u.db).
+		Model(&User{}).
+		Select("id, email, lastname, firstname, status, created_at, updated_at, "+
+			"MATCH (lastname, firstname, email) AGAINST (? IN BOOLEAN MODE) AS score", query).
+		Scopes(tenant(organizationID)).
+		Where("MATCH (lastname, firstname, email) AGAINST (? IN BOOLEAN MODE)", query).
+		Order("score DESC, lastname, firstname, id").
+		Limit(req.Limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]responses.UserSearchHit, 0, len(users))
+	for _, user := range users {
+		hits = append(hits, responses.UserSearchHit{
+			UsersListRepository: responses.UsersListRepository{
+				ID:        user.ID,
+				Email:     user.Email,
+				Lastname:  user.Lastname,
+				Firstname: user.Firstname,
+				Status:    user.Status,
+				CreatedAt: user.CreatedAt,
+				UpdatedAt: user.UpdatedAt,
+			},
+			Score: user.Score,
+		})
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,68 @@
+package indexed
+
+import (
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"context"
+	"sync"
+)
+
+// txKey is the context key of the changes of the current transaction
+type txKey struct{}
+
+// txChange is a user written in a transaction
+type txChange struct {
+	organizationID string
+	id             string
+}
+
+// txChanges are the users written in a transaction
+type txChanges struct {
+	mu      sync.Mutex
+	changes []txChange
+}
+
+// add records a user written in the transaction
+func (c *txChanges) add(organizationID, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.changes = append(c.changes, txChange{organizationID: organizationID, id: id})
+}
+
+// TxManager is a TxManager decorator which indexes the users written in a transaction once it ends:
+// they are read again outside of the transaction, so that the index contains the committed data
+// and nothing of a rolled back transaction.
+type TxManager struct {
+	tx    domain.TxManager
+	users *UserRepository
+}
+
+// NewTxManager creates a new TxManager indexing the users written with users
+func NewTxManager(tx domain.TxManager, users *UserRepository) *TxManager {
+	return &TxManager{tx: tx, users: users}
+}
+
+// WithinTx runs fn in a transaction of the decorated TxManager
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txChanges); ok {
+		return m.tx.WithinTx(ctx, fn)
+	}
+
+	changes := &txChanges{}
+	err := m.tx.WithinTx(context.WithValue(ctx, txKey{}, changes), fn)
+
+	indexed := make(map[txChange]bool, len(changes.changes))
+	for _, change := range changes.changes {
+		if !indexed[change] {
+			indexed[change] = true
+			m.users.reindex(context.WithoutCancel(ctx), change.organizationID, change.id)
+		}
+	}
+
+	return err
+}
+
+// WithinReadTx runs fn in a read-only transaction of the decorated TxManager: nothing is reindexed
+func (m *TxManager) WithinReadTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.tx.WithinReadTx(ctx, fn)
+}
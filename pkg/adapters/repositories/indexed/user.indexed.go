@@ -0,0 +1,136 @@
+// Package indexed provides repository decorators keeping the embedded search index in sync with the database.
+package indexed
+
+import (
+	"chi_boilerplate/pkg/adapters/search"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"errors"
+	"strconv"
+)
+
+// buildPageSize is the number of users read by query when the index is built
+const buildPageSize = 100
+
+// UserRepository is a UserRepository decorator updating a search index on the writes.
+//
+// After a successful write, the user is read again from the decorated repository and indexed,
+// or removed from the index if it is not found (deleted users are not searchable).
+// In a transaction of a TxManager of this package, the users are indexed once it ends (see NewTxManager).
+type UserRepository struct {
+	domain.UserRepository
+
+	index *search.UserIndex
+}
+
+// NewUserRepository creates a new UserRepository
+func NewUserRepository(repository domain.UserRepository, index *search.UserIndex) *UserRepository {
+	return &UserRepository{UserRepository: repository, index: index}
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, req requests.UserCreationRepository) error {
+	return r.reindexAfter(ctx, req.ID, r.UserRepository.Create(ctx, req))
+}
+
+// Update updates a user
+func (r *UserRepository) Update(ctx context.Context, req requests.UserUpdateRepository) error {
+	return r.reindexAfter(ctx, req.ID, r.UserRepository.Update(ctx, req))
+}
+
+// UpdateStatus changes the status of a user
+func (r *UserRepository) UpdateStatus(ctx context.Context, req requests.UserStatusUpdateRepository) error {
+	return r.reindexAfter(ctx, req.ID, r.UserRepository.UpdateStatus(ctx, req))
+}
+
+// UpdateAvatar changes the avatar of a user
+func (r *UserRepository) UpdateAvatar(ctx context.Context, req requests.UserAvatarUpdateRepository) error {
+	return r.reindexAfter(ctx, req.ID, r.UserRepository.UpdateAvatar(ctx, req))
+}
+
+// Delete deletes a user
+func (r *UserRepository) Delete(ctx context.Context, req requests.UserDelete) error {
+	return r.reindexAfter(ctx, req.ID, r.UserRepository.Delete(ctx, req))
+}
+
+// Erase erases the personal data of a deleted user
+func (r *UserRepository) Erase(ctx context.Context, req requests.UserErasureRepository) error {
+	return r.reindexAfter(ctx, req.ID, r.UserRepository.Erase(ctx, req))
+}
+
+// reindexAfter indexes a user after a successful write, or records it to be indexed at the end of the transaction
+func (r *UserRepository) reindexAfter(ctx context.Context, id string, err error) error {
+	if err != nil {
+		return err
+	}
+
+	organizationID, err := domain.Tenant(ctx)
+	if err != nil {
+		return nil
+	}
+
+	if changes, ok := ctx.Value(txKey{}).(*txChanges); ok {
+		changes.add(organizationID, id)
+		return nil
+	}
+
+	r.reindex(context.WithoutCancel(ctx), organizationID, id)
+	return nil
+}
+
+// reindex reads a user of an organization and indexes it or removes it from the index.
+// On errors of the database the indexed user is kept: it is fixed by its next write or a restart.
+func (r *UserRepository) reindex(ctx context.Context, organizationID, id string) {
+	user, err := r.UserRepository.GetByID(domain.WithTenant(ctx, organizationID), requests.UserByID{ID: id})
+	if errors.Is(err, domain.ErrUserNotFound) {
+		r.index.Remove(id)
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	r.index.Add(organizationID, responses.UsersListRepository{
+		ID:        user.ID,
+		Email:     user.Email,
+		Lastname:  user.Lastname,
+		Firstname: user.Firstname,
+		Status:    user.Status,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+}
+
+// Build indexes the users of all the organizations
+func Build(ctx context.Context, organizations domain.OrganizationRepository, users domain.UserRepository, index *search.UserIndex) error {
+	list, err := organizations.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, organization := range list {
+		tenantCtx := domain.WithTenant(ctx, organization.ID)
+
+		for page := 1; ; page++ {
+			pageUsers, err := users.GetAll(tenantCtx, requests.UsersList{
+				Page:  strconv.Itoa(page),
+				Limit: strconv.Itoa(buildPageSize),
+				Sorts: "+id",
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, user := range pageUsers {
+				index.Add(organization.ID, user)
+			}
+			if len(pageUsers) < buildPageSize {
+				break
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package indexed
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/adapters/search"
+	"chi_boilerplate/pkg/domain/entities"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/utils"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const userID = "8f2b6f4e-8f53-4a4c-9d1b-6a1f0f6c9e01"
+
+// newUser returns a user to create
+func newUser(id, lastname, firstname string) requests.UserCreationRepository {
+	now := time.Now().Format(utils.SqlDateTimeFormat)
+	return requests.UserCreationRepository{
+		ID: id, Email: id + "@example.com", Password: "hash", Lastname: lastname, Firstname: firstname, CreatedAt: now, UpdatedAt: now,
+	}
+}
+
+// searchIDs returns the IDs of the users found by a query
+func searchIDs(t *testing.T, index *search.UserIndex, ctx context.Context, query string) []string {
+	hits, err := index.Search(ctx, requests.UserSearchRepository{Query: query})
+	assert.Nil(t, err)
+
+	var ids []string
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestBuild(t *testing.T) {
+	ctx := domain.WithTenant(context.Background(), entities.DefaultOrganizationID)
+	database := memory.NewDatabase()
+
+	// More users than a page
+	for i := range buildPageSize + 1 {
+		if err := database.Users.Create(ctx, newUser(fmt.Sprintf("00000000-0000-0000-0000-%012d", i), "Doe", "John")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.Users.Delete(ctx, requests.UserDelete{ID: "00000000-0000-0000-0000-000000000000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	index := search.NewUserIndex()
+	assert.Nil(t, Build(ctx, database.Organizations, database.Users, index))
+	assert.Equal(t, buildPageSize, index.Len())
+}
+
+func TestUserRepository(t *testing.T) {
+	ctx := domain.WithTenant(context.Background(), entities.DefaultOrganizationID)
+	database := memory.NewDatabase()
+	index := search.NewUserIndex()
+	r := NewUserRepository(database.Users, index)
+
+	assert.Nil(t, r.Create(ctx, newUser(userID, "Doe", "John")))
+	assert.Equal(t, []string{userID}, searchIDs(t, index, ctx, "doe"))
+
+	// Failed writes do not change the index
+	assert.NotNil(t, r.Create(ctx, newUser(userID, "Smith", "John")))
+	assert.Equal(t, []string{userID}, searchIDs(t, index, ctx, "doe"))
+
+	err := r.Update(ctx, requests.UserUpdateRepository{
+		ID: userID, Email: "john@example.com", Password: "hash", Lastname: "Smith", Firstname: "John",
+		UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, searchIDs(t, index, ctx, "doe"))
+	assert.Equal(t, []string{userID}, searchIDs(t, index, ctx, "smith"))
+
+	err = r.UpdateStatus(ctx, requests.UserStatusUpdateRepository{
+		ID: userID, Status: string(entities.UserStatusSuspended), UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+	})
+	assert.Nil(t, err)
+	hits, err := index.Search(ctx, requests.UserSearchRepository{Query: "smith"})
+	assert.Nil(t, err)
+	assert.Equal(t, string(entities.UserStatusSuspended), hits[0].Status)
+
+	assert.Nil(t, r.Delete(ctx, requests.UserDelete{ID: userID}))
+	assert.Nil(t, searchIDs(t, index, ctx, "smith"))
+	assert.Equal(t, 0, index.Len())
+}
+
+func TestTxManager(t *testing.T) {
+	ctx := domain.WithTenant(context.Background(), entities.DefaultOrganizationID)
+	database := memory.NewDatabase()
+	index := search.NewUserIndex()
+	r := NewUserRepository(database.Users, index)
+	tx := NewTxManager(database, r)
+
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		assert.Nil(t, r.Create(ctx, newUser(userID, "Doe", "John")))
+
+		// Nested transactions are indexed at the end of the outermost one
+		err := tx.WithinTx(ctx, func(ctx context.Context) error {
+			return r.UpdateAvatar(ctx, requests.UserAvatarUpdateRepository{
+				ID: userID, Avatar: "avatar.png", UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+			})
+		})
+		assert.Nil(t, err)
+
+		// Users written in a transaction are indexed when it ends
+		assert.Nil(t, searchIDs(t, index, ctx, "doe"))
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{userID}, searchIDs(t, index, ctx, "doe"))
+}
@@ -0,0 +1,258 @@
+package memory
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// auditEvent is a row of the audit_events table
+type auditEvent struct {
+	id             string
+	organizationID string
+	actorID        string
+	action         string
+	targetType     string
+	targetID       string
+	requestID      string
+	ip             string
+	changes        string
+	createdAt      time.Time
+}
+
+// auditEventSortFields are the fields which can be used to sort audit events
+var auditEventSortFields = map[string]func(*auditEvent) string{
+	"id":          func(e *auditEvent) string { return e.id },
+	"actor_id":    func(e *auditEvent) string { return e.actorID },
+	"action":      func(e *auditEvent) string { return e.action },
+	"target_type": func(e *auditEvent) string { return e.targetType },
+	"target_id":   func(e *auditEvent) string { return e.targetID },
+	"created_at":  func(e *auditEvent) string { return sortableDateTime(e.createdAt) },
+}
+
+// AuditMemoryRepository is an in-memory implementation of the AuditRepository interface.
+// It is safe for concurrent use. Events are scoped to the organization of the context.
+type AuditMemoryRepository struct {
+	mu     sync.RWMutex
+	events []*auditEvent // In creation order
+}
+
+// NewAuditMemoryRepository creates a new AuditMemoryRepository
+func NewAuditMemoryRepository() *AuditMemoryRepository {
+	return &AuditMemoryRepository{}
+}
+
+// Create creates a new audit event
+func (a *AuditMemoryRepository) Create(ctx context.Context, event requests.AuditEventCreationRepository) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	createdAt, err := parseDateTime(event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.events = append(a.events, &auditEvent{
+		id:             event.ID,
+		organizationID: organizationID,
+		actorID:        event.ActorID,
+		action:         event.Action,
+		targetType:     event.TargetType,
+		targetID:       event.TargetID,
+		requestID:      event.RequestID,
+		ip:             event.IP,
+		changes:        event.Changes,
+		createdAt:      createdAt,
+	})
+
+	return nil
+}
+
+// CountAll returns the number of audit events matching the filters
+func (a *AuditMemoryRepository) CountAll(ctx context.Context, req requests.AuditEventsList) (int64, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	events, err := a.filter(organizationID, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(events)), nil
+}
+
+// GetAll returns the audit events matching the filters with sort and pagination
+func (a *AuditMemoryRepository) GetAll(ctx context.Context, req requests.AuditEventsList) ([]responses.AuditEventsListRepository, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	events, err := a.filter(organizationID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sorts := req.Sorts
+	if len(db.ParseSorts(sorts)) == 0 {
+		sorts = "-created_at"
+	}
+	if err := sortBy(events, sorts, auditEventSortFields); err != nil {
+		return nil, err
+	}
+
+	return auditEventsList(paginate(events, req.Page, req.Limit)), nil
+}
+
+// GetByUser returns all the audit events performed by or on a user
+func (a *AuditMemoryRepository) GetByUser(ctx context.Context, userID string) ([]responses.AuditEventsListRepository, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var events []*auditEvent
+	for _, event := range a.events {
+		if event.organizationID != organizationID {
+			continue
+		}
+		if event.actorID == userID || (event.targetType == "user" && event.targetID == userID) {
+			events = append(events, event)
+		}
+	}
+	_ = sortBy(events, "+created_at", auditEventSortFields)
+
+	return auditEventsList(events), nil
+}
+
+// Erase replaces the IP and the changes of an audit event
+func (a *AuditMemoryRepository) Erase(ctx context.Context, req requests.AuditEventErasureRepository) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, event := range a.events {
+		if event.id == req.ID && event.organizationID == organizationID {
+			event.ip = req.IP
+			event.changes = req.Changes
+		}
+	}
+
+	return nil
+}
+
+// DeleteBefore deletes the audit events created before a date and returns their number
+func (a *AuditMemoryRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	date, err := parseDateTime(before)
+	if err != nil {
+		return 0, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	kept := a.events[:0]
+	for _, event := range a.events {
+		if event.organizationID != organizationID || !event.createdAt.Before(date) {
+			kept = append(kept, event)
+		}
+	}
+	deleted := int64(len(a.events) - len(kept))
+	clear(a.events[len(kept):])
+	a.events = kept
+
+	return deleted, nil
+}
+
+// filter returns the audit events of the organization matching the list filters
+func (a *AuditMemoryRepository) filter(organizationID string, req requests.AuditEventsList) ([]*auditEvent, error) {
+	var from, to time.Time
+	var err error
+	if req.From != "" {
+		if from, err = time.Parse(time.RFC3339, req.From); err != nil {
+			return nil, err
+		}
+	}
+	if req.To != "" {
+		if to, err = time.Parse(time.RFC3339, req.To); err != nil {
+			return nil, err
+		}
+	}
+
+	events := make([]*auditEvent, 0, len(a.events))
+	for _, event := range a.events {
+		if event.organizationID != organizationID {
+			continue
+		}
+		if req.ActorID != "" && event.actorID != req.ActorID {
+			continue
+		}
+		if req.Action != "" && event.action != req.Action {
+			continue
+		}
+		if req.TargetType != "" && event.targetType != req.TargetType {
+			continue
+		}
+		if req.TargetID != "" && event.targetID != req.TargetID {
+			continue
+		}
+		if !from.IsZero() && event.createdAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && event.createdAt.After(to) {
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// auditEventsList converts audit events to repository responses
+func auditEventsList(events []*auditEvent) []responses.AuditEventsListRepository {
+	list := make([]responses.AuditEventsListRepository, 0, len(events))
+	for _, event := range events {
+		list = append(list, responses.AuditEventsListRepository{
+			ID:         event.id,
+			ActorID:    event.actorID,
+			Action:     event.action,
+			TargetType: event.targetType,
+			TargetID:   event.targetID,
+			RequestID:  event.requestID,
+			IP:         event.ip,
+			Changes:    json.RawMessage(event.changes),
+			CreatedAt:  formatDateTime(event.createdAt),
+		})
+	}
+
+	return list
+}
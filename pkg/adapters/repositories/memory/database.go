@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Database is an in-memory database.
+// It implements db.Connection so that it can be used like any other database,
+// its data are lost when the process stops.
+// Operations never block, so repositories only check the context before each of them.
+type Database struct {
+	Organizations *OrganizationMemoryRepository
+	Users         *UserMemoryRepository
+	Audit         *AuditMemoryRepository
+	Outbox        *OutboxMemoryRepository
+	JobRuns       *JobRunMemoryRepository
+}
+
+// NewDatabase creates a new in-memory database, only containing the default organization
+func NewDatabase() *Database {
+	return &Database{
+		Organizations: NewOrganizationMemoryRepository(),
+		Users:         NewUserMemoryRepository(),
+		Audit:         NewAuditMemoryRepository(),
+		Outbox:        NewOutboxMemoryRepository(),
+		JobRuns:       NewJobRunMemoryRepository(),
+	}
+}
+
+// WithinTx runs fn without transaction: in-memory operations are neither isolated nor rolled back
+func (d *Database) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return fn(ctx)
+}
+
+// WithinReadTx runs fn without transaction, as WithinTx
+func (d *Database) WithinReadTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return d.WithinTx(ctx, fn)
+}
+
+// DriverName returns the name of the database driver
+func (d *Database) DriverName() string {
+	return db.DriverMemory
+}
+
+// DSN returns an error: an in-memory database has no DSN
+func (d *Database) DSN() (string, error) {
+	return "", errors.New("no DSN for an in-memory database")
+}
+
+// Database does nothing: an in-memory database has no name
+func (d *Database) Database(string) {}
+
+// tenant checks the context and returns the organization the tenant-scoped repositories are limited to
+func tenant(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return repositories.Tenant(ctx)
+}
+
+// parseDateTime parses a date written like in SQL databases (utils.SqlDateTimeFormat)
+func parseDateTime(value string) (time.Time, error) {
+	return time.Parse(utils.SqlDateTimeFormat, value)
+}
+
+// formatDateTime formats a date like SQL databases when it is read (RFC3339)
+func formatDateTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// now returns the current date with the precision of utils.SqlDateTimeFormat
+func now() time.Time {
+	t, _ := parseDateTime(time.Now().Format(utils.SqlDateTimeFormat))
+	return t
+}
+
+// sortBy sorts items with the same sort syntax as db.OrderValues ("+created_at,-id").
+// fields returns the value to compare for each sortable field.
+func sortBy[T any](items []T, list string, fields map[string]func(T) string) error {
+	sorts := db.ParseSorts(list)
+	for _, s := range sorts {
+		if _, ok := fields[s.Field]; !ok {
+			return fmt.Errorf("%w: %s", repositories.ErrInvalidSort, s.Field)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, s := range sorts {
+			a, b := fields[s.Field](items[i]), fields[s.Field](items[j])
+			if a == b {
+				continue
+			}
+			if s.Desc {
+				return a > b
+			}
+			return a < b
+		}
+		return false
+	})
+
+	return nil
+}
+
+// paginate returns the items of a page like db.PaginateValues
+func paginate[T any](items []T, page, limit string) []T {
+	offset, l := db.PaginateValues(page, limit)
+	if offset >= len(items) {
+		return items[:0]
+	}
+
+	return items[offset:min(offset+l, len(items))]
+}
+
+// sortableDateTime returns a date which can be compared as a string
+func sortableDateTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000")
+}
+
+// sortableText returns a text which can be compared as a string without case sensitivity
+func sortableText(s string) string {
+	return strings.ToLower(s)
+}
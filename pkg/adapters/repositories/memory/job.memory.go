@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// jobRun is a row of the job_runs table
+type jobRun struct {
+	id          string
+	job         string
+	instance    string
+	status      string
+	result      string
+	error       string
+	scheduledAt time.Time
+	startedAt   time.Time
+	finishedAt  *time.Time
+}
+
+// JobRunMemoryRepository is an in-memory implementation of the JobRunRepository interface.
+// It is safe for concurrent use.
+type JobRunMemoryRepository struct {
+	mu   sync.RWMutex
+	runs []*jobRun // In creation order
+}
+
+// NewJobRunMemoryRepository creates a new JobRunMemoryRepository
+func NewJobRunMemoryRepository() *JobRunMemoryRepository {
+	return &JobRunMemoryRepository{}
+}
+
+// Create records the start of a job run
+func (j *JobRunMemoryRepository) Create(ctx context.Context, run requests.JobRunCreationRepository) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	scheduledAt, err := parseDateTime(run.ScheduledAt)
+	if err != nil {
+		return err
+	}
+	startedAt, err := parseDateTime(run.StartedAt)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.runs = append(j.runs, &jobRun{
+		id:          run.ID,
+		job:         run.Job,
+		instance:    run.Instance,
+		status:      string(entities.JobRunStatusRunning),
+		scheduledAt: scheduledAt,
+		startedAt:   startedAt,
+	})
+
+	return nil
+}
+
+// Finish records the end of a job run
+func (j *JobRunMemoryRepository) Finish(ctx context.Context, req requests.JobRunFinishRepository) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	finishedAt, err := parseDateTime(req.FinishedAt)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, run := range j.runs {
+		if run.id == req.ID {
+			run.status = req.Status
+			run.result = req.Result
+			run.error = req.Error
+			run.finishedAt = &finishedAt
+		}
+	}
+
+	return nil
+}
+
+// GetAll returns the latest runs first, of a job if req.Job is not empty
+func (j *JobRunMemoryRepository) GetAll(ctx context.Context, req requests.JobRunsList) ([]responses.JobRunRepository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	runs := make([]*jobRun, 0, len(j.runs))
+	for _, run := range j.runs {
+		if req.Job == "" || run.job == req.Job {
+			runs = append(runs, run)
+		}
+	}
+	sort.SliceStable(runs, func(a, b int) bool {
+		if !runs[a].scheduledAt.Equal(runs[b].scheduledAt) {
+			return runs[a].scheduledAt.After(runs[b].scheduledAt)
+		}
+		return runs[a].startedAt.After(runs[b].startedAt)
+	})
+	if len(runs) > req.Limit {
+		runs = runs[:req.Limit]
+	}
+
+	list := make([]responses.JobRunRepository, 0, len(runs))
+	for _, run := range runs {
+		var finishedAt *string
+		if run.finishedAt != nil {
+			f := formatDateTime(*run.finishedAt)
+			finishedAt = &f
+		}
+
+		list = append(list, responses.JobRunRepository{
+			ID:          run.id,
+			Job:         run.job,
+			Instance:    run.instance,
+			Status:      run.status,
+			Result:      run.result,
+			Error:       run.error,
+			ScheduledAt: formatDateTime(run.scheduledAt),
+			StartedAt:   formatDateTime(run.startedAt),
+			FinishedAt:  finishedAt,
+		})
+	}
+
+	return list, nil
+}
+
+// DeleteBefore deletes the runs started before a date and returns their number
+func (j *JobRunMemoryRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	date, err := parseDateTime(before)
+	if err != nil {
+		return 0, err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	kept := j.runs[:0]
+	for _, run := range j.runs {
+		if !run.startedAt.Before(date) {
+			kept = append(kept, run)
+		}
+	}
+	deleted := int64(len(j.runs) - len(kept))
+	clear(j.runs[len(kept):])
+	j.runs = kept
+
+	return deleted, nil
+}
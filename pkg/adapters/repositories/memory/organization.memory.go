@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// organization is a row of the organizations table
+type organization struct {
+	id        string
+	name      string
+	slug      string
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// OrganizationMemoryRepository is an in-memory implementation of the OrganizationRepository interface.
+// It is safe for concurrent use and contains the default organization like a migrated SQL database.
+type OrganizationMemoryRepository struct {
+	mu            sync.RWMutex
+	organizations []*organization // In creation order
+}
+
+// NewOrganizationMemoryRepository creates a new OrganizationMemoryRepository with the default organization
+func NewOrganizationMemoryRepository() *OrganizationMemoryRepository {
+	createdAt := now()
+
+	return &OrganizationMemoryRepository{
+		organizations: []*organization{{
+			id:        entities.DefaultOrganizationID,
+			name:      "Default",
+			slug:      "default",
+			createdAt: createdAt,
+			updatedAt: createdAt,
+		}},
+	}
+}
+
+// Create creates a new organization
+func (o *OrganizationMemoryRepository) Create(ctx context.Context, req requests.OrganizationCreationRepository) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	createdAt, err := parseDateTime(req.CreatedAt)
+	if err != nil {
+		return err
+	}
+	updatedAt, err := parseDateTime(req.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, organization := range o.organizations {
+		if organization.id == req.ID {
+			return fmt.Errorf("duplicate organization ID: %s", req.ID)
+		}
+		if organization.slug == req.Slug {
+			return repositories.ErrSlugAlreadyExists
+		}
+	}
+
+	o.organizations = append(o.organizations, &organization{
+		id:        req.ID,
+		name:      req.Name,
+		slug:      req.Slug,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	})
+
+	return nil
+}
+
+// GetByID returns an organization by ID
+func (o *OrganizationMemoryRepository) GetByID(ctx context.Context, req requests.OrganizationByID) (responses.OrganizationRepository, error) {
+	return o.find(ctx, func(organization *organization) bool { return organization.id == req.ID })
+}
+
+// GetBySlug returns an organization by slug
+func (o *OrganizationMemoryRepository) GetBySlug(ctx context.Context, req requests.OrganizationBySlug) (responses.OrganizationRepository, error) {
+	return o.find(ctx, func(organization *organization) bool { return organization.slug == req.Slug })
+}
+
+// GetAll returns all organizations sorted by slug
+func (o *OrganizationMemoryRepository) GetAll(ctx context.Context) ([]responses.OrganizationRepository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	organizations := make([]*organization, len(o.organizations))
+	copy(organizations, o.organizations)
+	_ = sortBy(organizations, "+slug", map[string]func(*organization) string{
+		"slug": func(o *organization) string { return o.slug },
+	})
+
+	list := make([]responses.OrganizationRepository, 0, len(organizations))
+	for _, organization := range organizations {
+		list = append(list, organization.response())
+	}
+
+	return list, nil
+}
+
+// find returns the first organization matching the predicate
+func (o *OrganizationMemoryRepository) find(ctx context.Context, match func(*organization) bool) (responses.OrganizationRepository, error) {
+	if err := ctx.Err(); err != nil {
+		return responses.OrganizationRepository{}, err
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	for _, organization := range o.organizations {
+		if match(organization) {
+			return organization.response(), nil
+		}
+	}
+
+	return responses.OrganizationRepository{}, repositories.ErrOrganizationNotFound
+}
+
+// response converts an organization to a repository response
+func (o *organization) response() responses.OrganizationRepository {
+	return responses.OrganizationRepository{
+		ID:        o.id,
+		Name:      o.name,
+		Slug:      o.slug,
+		CreatedAt: formatDateTime(o.createdAt),
+		UpdatedAt: formatDateTime(o.updatedAt),
+	}
+}
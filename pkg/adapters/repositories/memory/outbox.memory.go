@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// outboxEvent is a row of the outbox_events table
+type outboxEvent struct {
+	seq           int64
+	id            string
+	aggregateType string
+	aggregateID   string
+	eventType     string
+	payload       string
+	requestID     string
+	attempts      int
+	lastError     string
+	createdAt     time.Time
+	nextAttemptAt time.Time
+	publishedAt   *time.Time
+}
+
+// OutboxMemoryRepository is an in-memory implementation of the OutboxRepository interface.
+// It is safe for concurrent use.
+type OutboxMemoryRepository struct {
+	mu     sync.RWMutex
+	events []*outboxEvent // In creation order
+}
+
+// NewOutboxMemoryRepository creates a new OutboxMemoryRepository
+func NewOutboxMemoryRepository() *OutboxMemoryRepository {
+	return &OutboxMemoryRepository{}
+}
+
+// Create saves a new event in the outbox
+func (o *OutboxMemoryRepository) Create(ctx context.Context, event requests.OutboxEventCreationRepository) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	createdAt, err := parseDateTime(event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.events = append(o.events, &outboxEvent{
+		seq:           int64(len(o.events) + 1),
+		id:            event.ID,
+		aggregateType: event.AggregateType,
+		aggregateID:   event.AggregateID,
+		eventType:     event.EventType,
+		payload:       event.Payload,
+		requestID:     event.RequestID,
+		createdAt:     createdAt,
+		nextAttemptAt: createdAt,
+	})
+
+	return nil
+}
+
+// GetPending returns the events ready to be published in the order they were saved: events whose next attempt is due
+// and whose aggregate has no older event waiting for its next attempt.
+func (o *OutboxMemoryRepository) GetPending(ctx context.Context, req requests.OutboxEventsPendingRepository) ([]responses.OutboxEventRepository, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	now, err := parseDateTime(req.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	list := make([]responses.OutboxEventRepository, 0)
+	blocked := make(map[string]bool) // Aggregates with an event waiting for its next attempt
+	for _, event := range o.events {
+		if len(list) == req.Limit {
+			break
+		}
+		if event.publishedAt != nil {
+			continue
+		}
+
+		aggregate := event.aggregateType + "/" + event.aggregateID
+		if event.nextAttemptAt.After(now) {
+			blocked[aggregate] = true
+			continue
+		}
+		if blocked[aggregate] {
+			continue
+		}
+
+		list = append(list, responses.OutboxEventRepository{
+			Seq:           event.seq,
+			ID:            event.id,
+			AggregateType: event.aggregateType,
+			AggregateID:   event.aggregateID,
+			EventType:     event.eventType,
+			Payload:       json.RawMessage(event.payload),
+			RequestID:     event.requestID,
+			Attempts:      event.attempts,
+			CreatedAt:     formatDateTime(event.createdAt),
+			NextAttemptAt: formatDateTime(event.nextAttemptAt),
+		})
+	}
+
+	return list, nil
+}
+
+// MarkPublished marks an event as published
+func (o *OutboxMemoryRepository) MarkPublished(ctx context.Context, req requests.OutboxEventPublishedRepository) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	publishedAt, err := parseDateTime(req.PublishedAt)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if event := o.find(req.ID); event != nil {
+		event.publishedAt = &publishedAt
+		event.lastError = ""
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed publication of an event and the date of its next attempt
+func (o *OutboxMemoryRepository) MarkFailed(ctx context.Context, req requests.OutboxEventFailureRepository) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	nextAttemptAt, err := parseDateTime(req.NextAttemptAt)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if event := o.find(req.ID); event != nil {
+		event.attempts++
+		event.lastError = req.LastError
+		event.nextAttemptAt = nextAttemptAt
+	}
+
+	return nil
+}
+
+// Erase replaces the payloads of all the events of an aggregate
+func (o *OutboxMemoryRepository) Erase(ctx context.Context, req requests.OutboxEventsErasureRepository) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, event := range o.events {
+		if event.aggregateType == req.AggregateType && event.aggregateID == req.AggregateID {
+			event.payload = req.Payload
+		}
+	}
+
+	return nil
+}
+
+// DeletePublishedBefore deletes the events published before a date and returns their number
+func (o *OutboxMemoryRepository) DeletePublishedBefore(ctx context.Context, before string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	date, err := parseDateTime(before)
+	if err != nil {
+		return 0, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	kept := o.events[:0]
+	for _, event := range o.events {
+		if event.publishedAt == nil || !event.publishedAt.Before(date) {
+			kept = append(kept, event)
+		}
+	}
+	deleted := int64(len(o.events) - len(kept))
+	clear(o.events[len(kept):])
+	o.events = kept
+
+	return deleted, nil
+}
+
+// find returns the event with the ID or nil if it does not exist
+func (o *OutboxMemoryRepository) find(id string) *outboxEvent {
+	for _, event := range o.events {
+		if event.id == id {
+			return event
+		}
+	}
+
+	return nil
+}
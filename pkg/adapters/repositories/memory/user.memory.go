@@ -0,0 +1,426 @@
+package memory
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// user is a row of the users table
+type user struct {
+	id             string
+	organizationID string
+	email          string
+	password       string
+	lastname       string
+	firstname      string
+	avatar         string
+	status         string
+	statusReason   string
+	version        uint64
+	createdAt      time.Time
+	updatedAt      time.Time
+	deletedAt      *time.Time
+	erasedAt       *time.Time
+}
+
+// userSortFields are the fields which can be used to sort users
+var userSortFields = map[string]func(*user) string{
+	"id":         func(u *user) string { return u.id },
+	"email":      func(u *user) string { return sortableText(u.email) },
+	"lastname":   func(u *user) string { return sortableText(u.lastname) },
+	"firstname":  func(u *user) string { return sortableText(u.firstname) },
+	"status":     func(u *user) string { return u.status },
+	"created_at": func(u *user) string { return sortableDateTime(u.createdAt) },
+	"updated_at": func(u *user) string { return sortableDateTime(u.updatedAt) },
+}
+
+// UserMemoryRepository is an in-memory implementation of the UserRepository interface.
+// It is safe for concurrent use and behaves like the SQL implementations:
+// deleted users are kept but ignored (soft delete) and emails are unique per organization without case sensitivity.
+// Users are scoped to the organization of the context (see repositories.WithTenant).
+type UserMemoryRepository struct {
+	mu    sync.RWMutex
+	users []*user // In creation order
+}
+
+// NewUserMemoryRepository creates a new UserMemoryRepository
+func NewUserMemoryRepository() *UserMemoryRepository {
+	return &UserMemoryRepository{}
+}
+
+// Create creates a new user
+func (u *UserMemoryRepository) Create(ctx context.Context, req requests.UserCreationRepository) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	createdAt, err := parseDateTime(req.CreatedAt)
+	if err != nil {
+		return err
+	}
+	updatedAt, err := parseDateTime(req.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, user := range u.users {
+		if user.id == req.ID {
+			return fmt.Errorf("duplicate user ID: %s", req.ID)
+		}
+		if user.organizationID == organizationID && strings.EqualFold(user.email, req.Email) {
+			return repositories.ErrEmailAlreadyExists
+		}
+	}
+
+	u.users = append(u.users, &user{
+		id:             req.ID,
+		organizationID: organizationID,
+		email:          req.Email,
+		password:       req.Password,
+		lastname:       req.Lastname,
+		firstname:      req.Firstname,
+		status:         "active",
+		version:        1,
+		createdAt:      createdAt,
+		updatedAt:      updatedAt,
+	})
+
+	return nil
+}
+
+// GetByID returns a user by ID
+func (u *UserMemoryRepository) GetByID(ctx context.Context, req requests.UserByID) (responses.UserByIdRepository, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return responses.UserByIdRepository{}, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	user := u.find(organizationID, req.ID)
+	if user == nil {
+		return responses.UserByIdRepository{}, repositories.ErrUserNotFound
+	}
+
+	return responses.UserByIdRepository{
+		ID:             user.id,
+		OrganizationID: user.organizationID,
+		Email:          user.email,
+		Lastname:       user.lastname,
+		Firstname:      user.firstname,
+		Status:         user.status,
+		StatusReason:   user.statusReason,
+		Avatar:         user.avatar,
+		Version:        user.version,
+		CreatedAt:      formatDateTime(user.createdAt),
+		UpdatedAt:      formatDateTime(user.updatedAt),
+	}, nil
+}
+
+// GetByEmail returns a user by Email
+func (u *UserMemoryRepository) GetByEmail(ctx context.Context, req requests.GetByEmail) (responses.GetByEmail, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return responses.GetByEmail{}, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	for _, user := range u.users {
+		if user.organizationID == organizationID && user.deletedAt == nil && strings.EqualFold(user.email, req.Email) {
+			r := responses.GetByEmailRepository{
+				ID:       user.id,
+				Password: user.password,
+				Status:   user.status,
+			}
+			response, err := r.ToGetByEmail()
+			if err != nil {
+				return responses.GetByEmail{}, repositories.ErrUserNotFound
+			}
+
+			return response, nil
+		}
+	}
+
+	return responses.GetByEmail{}, repositories.ErrUserNotFound
+}
+
+// Delete deletes a user (soft delete)
+func (u *UserMemoryRepository) Delete(ctx context.Context, req requests.UserDelete) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user, err := u.findVersion(organizationID, req.ID, req.Version)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := now()
+	user.deletedAt = &deletedAt
+	user.version++
+
+	return nil
+}
+
+// CountAll returns the number of users matching the filters
+func (u *UserMemoryRepository) CountAll(ctx context.Context, req requests.UsersList) (int64, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return int64(len(u.filter(organizationID, req))), nil
+}
+
+// GetAll returns the users matching the filters with sort and pagination
+func (u *UserMemoryRepository) GetAll(ctx context.Context, req requests.UsersList) ([]responses.UsersListRepository, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := u.filter(organizationID, req)
+	if err := sortBy(users, req.Sorts, userSortFields); err != nil {
+		return nil, err
+	}
+
+	var list []responses.UsersListRepository
+	for _, user := range paginate(users, req.Page, req.Limit) {
+		list = append(list, responses.UsersListRepository{
+			ID:        user.id,
+			Email:     user.email,
+			Lastname:  user.lastname,
+			Firstname: user.firstname,
+			Status:    user.status,
+			CreatedAt: formatDateTime(user.createdAt),
+			UpdatedAt: formatDateTime(user.updatedAt),
+		})
+	}
+
+	return list, nil
+}
+
+// Update updates a user
+func (u *UserMemoryRepository) Update(ctx context.Context, req requests.UserUpdateRepository) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	updatedAt, err := parseDateTime(req.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user, err := u.findVersion(organizationID, req.ID, req.Version)
+	if err != nil {
+		return err
+	}
+	for _, other := range u.users {
+		if other.id != user.id && other.organizationID == organizationID && strings.EqualFold(other.email, req.Email) {
+			return repositories.ErrEmailAlreadyExists
+		}
+	}
+
+	user.lastname = req.Lastname
+	user.firstname = req.Firstname
+	user.email = req.Email
+	user.password = req.Password
+	user.updatedAt = updatedAt
+	user.version++
+
+	return nil
+}
+
+// UpdateStatus changes the status of a user
+func (u *UserMemoryRepository) UpdateStatus(ctx context.Context, req requests.UserStatusUpdateRepository) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	updatedAt, err := parseDateTime(req.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user, err := u.findVersion(organizationID, req.ID, req.Version)
+	if err != nil {
+		return err
+	}
+
+	user.status = req.Status
+	user.statusReason = req.Reason
+	user.updatedAt = updatedAt
+	user.version++
+
+	return nil
+}
+
+// UpdateAvatar changes the avatar of a user
+func (u *UserMemoryRepository) UpdateAvatar(ctx context.Context, req requests.UserAvatarUpdateRepository) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	updatedAt, err := parseDateTime(req.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user, err := u.findVersion(organizationID, req.ID, req.Version)
+	if err != nil {
+		return err
+	}
+
+	user.avatar = req.Avatar
+	user.updatedAt = updatedAt
+	user.version++
+
+	return nil
+}
+
+// GetErasable returns the deleted users whose personal data have not been erased yet
+func (u *UserMemoryRepository) GetErasable(ctx context.Context, req requests.UsersErasableRepository) ([]responses.UserErasableRepository, error) {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedBefore, err := parseDateTime(req.DeletedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	var users []*user
+	for _, user := range u.users {
+		if user.organizationID == organizationID && user.deletedAt != nil && !user.deletedAt.After(deletedBefore) && user.erasedAt == nil {
+			users = append(users, user)
+		}
+	}
+	_ = sortBy(users, "+deleted_at", map[string]func(*user) string{
+		"deleted_at": func(u *user) string { return sortableDateTime(*u.deletedAt) },
+	})
+	if len(users) > req.Limit {
+		users = users[:req.Limit]
+	}
+
+	list := make([]responses.UserErasableRepository, 0, len(users))
+	for _, user := range users {
+		list = append(list, responses.UserErasableRepository{ID: user.id, Avatar: user.avatar})
+	}
+
+	return list, nil
+}
+
+// Erase anonymises the personal data of a deleted user.
+// The user is kept so that audit events and other references remain valid.
+func (u *UserMemoryRepository) Erase(ctx context.Context, req requests.UserErasureRepository) error {
+	organizationID, err := tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	erasedAt, err := parseDateTime(req.ErasedAt)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, user := range u.users {
+		if user.id == req.ID && user.organizationID == organizationID && user.deletedAt != nil && user.erasedAt == nil {
+			user.email = req.Email
+			user.password = ""
+			user.lastname = ""
+			user.firstname = ""
+			user.avatar = ""
+			user.statusReason = ""
+			user.erasedAt = &erasedAt
+			user.updatedAt = erasedAt
+			user.version++
+
+			return nil
+		}
+	}
+
+	return repositories.ErrUserNotFound
+}
+
+// find returns a user of the organization not deleted or nil if it does not exist
+func (u *UserMemoryRepository) find(organizationID, id string) *user {
+	for _, user := range u.users {
+		if user.id == id && user.organizationID == organizationID && user.deletedAt == nil {
+			return user
+		}
+	}
+
+	return nil
+}
+
+// findVersion returns a user of the organization not deleted.
+// If version is not 0, the user must still have the same version.
+func (u *UserMemoryRepository) findVersion(organizationID, id string, version uint64) (*user, error) {
+	user := u.find(organizationID, id)
+	if user == nil {
+		return nil, repositories.ErrUserNotFound
+	}
+	if version > 0 && user.version != version {
+		return nil, repositories.ErrUserVersionMismatch
+	}
+
+	return user, nil
+}
+
+// filter returns the users of the organization not deleted matching the list filters
+func (u *UserMemoryRepository) filter(organizationID string, req requests.UsersList) []*user {
+	users := make([]*user, 0, len(u.users))
+	for _, user := range u.users {
+		if user.organizationID != organizationID || user.deletedAt != nil {
+			continue
+		}
+		if req.Status != "" && user.status != req.Status {
+			continue
+		}
+		users = append(users, user)
+	}
+
+	return users
+}
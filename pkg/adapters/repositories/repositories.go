@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/repositories/cached"
+	"chi_boilerplate/pkg/adapters/repositories/gorm_mysql"
+	"chi_boilerplate/pkg/adapters/repositories/indexed"
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/adapters/repositories/sqlx_mysql"
+	"chi_boilerplate/pkg/adapters/repositories/sqlx_postgres"
+	"chi_boilerplate/pkg/adapters/repositories/sqlx_sqlite"
+	"chi_boilerplate/pkg/adapters/search"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Repositories groups the repositories of a database connection
+type Repositories struct {
+	Organization domain.OrganizationRepository
+	User         domain.UserRepository
+	Audit        domain.AuditRepository
+	Outbox       domain.OutboxRepository
+	UserSearch   domain.UserSearchRepository // nil: the database has no full-text index (see WithSearchIndex)
+	JobRuns      domain.JobRunRepository
+	JobLocker    domain.JobLocker // Advisory locks with MySQL and PostgreSQL, local locks (single instance) with the other drivers
+	Tx           domain.TxManager
+}
+
+// New returns the repositories matching the driver and the backend (sqlx or GORM) of the database connection
+func New(conn db.Connection) (Repositories, error) {
+	switch c := conn.(type) {
+	case *db.SqlxMySQL:
+		return Repositories{
+			Organization: sqlx_mysql.NewOrganizationMysqlRepository(c),
+			User:         sqlx_mysql.NewUserMysqlRepository(c),
+			Audit:        sqlx_mysql.NewAuditMysqlRepository(c),
+			Outbox:       sqlx_mysql.NewOutboxMysqlRepository(c),
+			UserSearch:   sqlx_mysql.NewUserSearchMysqlRepository(c),
+			JobRuns:      sqlx_mysql.NewJobRunMysqlRepository(c),
+			JobLocker:    c.JobLocker(),
+			Tx:           c.TxManager(),
+		}, nil
+	case *db.GormMySQL:
+		locker, err := c.JobLocker()
+		if err != nil {
+			return Repositories{}, err
+		}
+
+		return Repositories{
+			Organization: gorm_mysql.NewOrganizationMysqlRepository(c),
+			User:         gorm_mysql.NewUserMysqlRepository(c),
+			Audit:        gorm_mysql.NewAuditMysqlRepository(c),
+			Outbox:       gorm_mysql.NewOutboxMysqlRepository(c),
+			UserSearch:   gorm_mysql.NewUserSearchMysqlRepository(c),
+			JobRuns:      gorm_mysql.NewJobRunMysqlRepository(c),
+			JobLocker:    locker,
+			Tx:           c.TxManager(),
+		}, nil
+	case *db.SqlxPostgres:
+		return Repositories{
+			Organization: sqlx_postgres.NewOrganizationPostgresRepository(c),
+			User:         sqlx_postgres.NewUserPostgresRepository(c),
+			Audit:        sqlx_postgres.NewAuditPostgresRepository(c),
+			Outbox:       sqlx_postgres.NewOutboxPostgresRepository(c),
+			JobRuns:      sqlx_postgres.NewJobRunPostgresRepository(c),
+			JobLocker:    c.JobLocker(),
+			Tx:           c.TxManager(),
+		}, nil
+	case *db.SqlxSQLite:
+		return Repositories{
+			Organization: sqlx_sqlite.NewOrganizationSQLiteRepository(c),
+			User:         sqlx_sqlite.NewUserSQLiteRepository(c),
+			Audit:        sqlx_sqlite.NewAuditSQLiteRepository(c),
+			Outbox:       sqlx_sqlite.NewOutboxSQLiteRepository(c),
+			JobRuns:      sqlx_sqlite.NewJobRunSQLiteRepository(c),
+			JobLocker:    db.NewLocalLocker(),
+			Tx:           c.TxManager(),
+		}, nil
+	case *memory.Database:
+		return Repositories{
+			Organization: c.Organizations,
+			User:         c.Users,
+			Audit:        c.Audit,
+			Outbox:       c.Outbox,
+			JobRuns:      c.JobRuns,
+			JobLocker:    db.NewLocalLocker(),
+			Tx:           c,
+		}, nil
+	default:
+		return Repositories{}, fmt.Errorf("unsupported database connection: %s", conn.DriverName())
+	}
+}
+
+// WithCache returns the repositories reading the users through a cache for ttl (see cached.UserRepository)
+func (r Repositories) WithCache(cache domain.Cache, ttl time.Duration) Repositories {
+	r.User = cached.NewUserRepository(r.User, cache, ttl)
+	r.Tx = cached.NewTxManager(r.Tx, cache)
+
+	return r
+}
+
+// WithSearchIndex returns the repositories searching the users with an embedded index built from the database.
+// The writes of the returned repositories keep the index in sync (see indexed.UserRepository),
+// the writes of other processes are only indexed when the index is built again.
+func (r Repositories) WithSearchIndex(ctx context.Context) (Repositories, error) {
+	index := search.NewUserIndex()
+	if err := indexed.Build(ctx, r.Organization, r.User, index); err != nil {
+		return r, fmt.Errorf("error when building the users search index: %w", err)
+	}
+
+	users := indexed.NewUserRepository(r.User, index)
+	r.User = users
+	r.Tx = indexed.NewTxManager(r.Tx, users)
+	r.UserSearch = index
+
+	return r, nil
+}
@@ -0,0 +1,401 @@
+package sqlx_crud
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const (
+	idColumn           = "id"
+	organizationColumn = "organization_id"
+	versionColumn      = "version"
+	createdAtColumn    = "created_at"
+	deletedAtColumn    = "deleted_at"
+)
+
+// Table describes how the rows of a resource are stored
+type Table struct {
+	// Name is the name of the table
+	Name string
+
+	// Tenant scopes the rows to the organization of the context (organization_id column, see repositories.WithTenant)
+	Tenant bool
+
+	// SoftDelete hides the rows with a deleted_at date and makes Delete set it instead of removing the row
+	SoftDelete bool
+
+	// Versioned increments the version column at each update and deletion,
+	// which are rejected with repositories.ErrVersionMismatch if the row has not the expected version
+	Versioned bool
+
+	// Filters are the columns which can be filtered by equality in GetAll and CountAll
+	Filters []string
+
+	// Unique maps the unique indexes to the error returned when a value is already used.
+	// repositories.ErrAlreadyExists is returned for the other unique indexes.
+	Unique map[string]error
+
+	// ErrNotFound is the error returned when a row does not exist (repositories.ErrNotFound by default)
+	ErrNotFound error
+}
+
+// Repository is a generic sqlx implementation of the repositories.Repository interface.
+// The columns are the db tags of the fields of T and the primary key is the id column.
+// Queries are written with "?" placeholders and rebound for the driver of the connection,
+// so the same repository works with MySQL, PostgreSQL and SQLite.
+type Repository[T any, ID comparable] struct {
+	db      *db.Resolver
+	table   Table
+	columns []string
+}
+
+// NewRepository creates a new Repository of the table. It panics if T is not a struct.
+func NewRepository[T any, ID comparable](resolver *db.Resolver, table Table) *Repository[T, ID] {
+	if table.ErrNotFound == nil {
+		table.ErrNotFound = repositories.ErrNotFound
+	}
+
+	return &Repository[T, ID]{
+		db:      resolver,
+		table:   table,
+		columns: columns(reflect.TypeFor[T]()),
+	}
+}
+
+// Create inserts a new row. The organization and the version are set by the repository.
+func (r *Repository[T, ID]) Create(ctx context.Context, entity T) error {
+	var columns []string
+	var args []any
+	for column, value := range r.values(entity) {
+		if column == organizationColumn || (r.table.Versioned && column == versionColumn) {
+			continue
+		}
+		columns = append(columns, column)
+		args = append(args, value)
+	}
+
+	if r.table.Tenant {
+		organizationID, err := repositories.Tenant(ctx)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, organizationColumn)
+		args = append(args, organizationID)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		r.table.Name,
+		strings.Join(columns, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "),
+	)
+
+	ext := r.db.Primary(ctx)
+	if _, err := ext.ExecContext(ctx, ext.Rebind(query), args...); err != nil {
+		return r.writeError(err)
+	}
+
+	return nil
+}
+
+// GetByID returns a row by ID
+func (r *Repository[T, ID]) GetByID(ctx context.Context, id ID) (T, error) {
+	var entity T
+
+	where, args, err := r.where(ctx, nil)
+	if err != nil {
+		return entity, err
+	}
+	where = append([]string{idColumn + " = ?"}, where...)
+	args = append([]any{id}, args...)
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s LIMIT 1",
+		strings.Join(r.columns, ", "),
+		r.table.Name,
+		strings.Join(where, " AND "),
+	)
+
+	ext := r.db.Reader(ctx)
+	if err := ext.QueryRowxContext(ctx, ext.Rebind(query), args...).StructScan(&entity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity, r.table.ErrNotFound
+		}
+		return entity, err
+	}
+
+	return entity, nil
+}
+
+// GetAll returns a page of rows, sorted by columns of T
+func (r *Repository[T, ID]) GetAll(ctx context.Context, req requests.List) ([]T, error) {
+	where, args, err := r.where(ctx, req.Filters)
+	if err != nil {
+		return nil, err
+	}
+	order, err := r.order(req.Sorts)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit := db.PaginateValues(req.Page, req.Limit)
+
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(r.columns, ", "), r.table.Name)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += order + " LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	ext := r.db.Reader(ctx)
+	rows, err := ext.QueryxContext(ctx, ext.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []T
+	for rows.Next() {
+		var entity T
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		list = append(list, entity)
+	}
+
+	return list, rows.Err()
+}
+
+// CountAll returns the number of rows matching the filters
+func (r *Repository[T, ID]) CountAll(ctx context.Context, req requests.List) (int64, error) {
+	where, args, err := r.where(ctx, req.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(%s) FROM %s", idColumn, r.table.Name)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var count int64
+	ext := r.db.Reader(ctx)
+	if err := ext.QueryRowxContext(ctx, ext.Rebind(query), args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Update updates all the columns of a row except its ID, organization, version and creation date.
+// version is the expected version of a versioned row (0: any version).
+func (r *Repository[T, ID]) Update(ctx context.Context, id ID, version uint64, entity T) error {
+	var set []string
+	var args []any
+	for column, value := range r.values(entity) {
+		switch column {
+		case idColumn, organizationColumn, versionColumn, createdAtColumn:
+			continue
+		}
+		set = append(set, column+" = ?")
+		args = append(args, value)
+	}
+	if r.table.Versioned {
+		set = append(set, versionColumn+" = "+versionColumn+" + 1")
+	}
+
+	return r.mutate(ctx, id, version, "SET "+strings.Join(set, ", "), args)
+}
+
+// Delete deletes a row, or sets its deletion date if the table uses soft deletion.
+// version is the expected version of a versioned row (0: any version).
+func (r *Repository[T, ID]) Delete(ctx context.Context, id ID, version uint64) error {
+	if !r.table.SoftDelete {
+		return r.mutate(ctx, id, version, "", nil)
+	}
+
+	set := "SET " + deletedAtColumn + " = CURRENT_TIMESTAMP"
+	if r.table.Versioned {
+		set += ", " + versionColumn + " = " + versionColumn + " + 1"
+	}
+
+	return r.mutate(ctx, id, version, set, nil)
+}
+
+// mutate executes an UPDATE of the row with the SET clause, or a DELETE if set is empty.
+// The version of a versioned row is checked if it is not 0.
+func (r *Repository[T, ID]) mutate(ctx context.Context, id ID, version uint64, set string, args []any) error {
+	where, whereArgs, err := r.where(ctx, nil)
+	if err != nil {
+		return err
+	}
+	where = append([]string{idColumn + " = ?"}, where...)
+	args = append(append(args, id), whereArgs...)
+
+	checkVersion := r.table.Versioned && version > 0
+	if checkVersion {
+		where = append(where, versionColumn+" = ?")
+		args = append(args, version)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s", r.table.Name, strings.Join(where, " AND "))
+	if set != "" {
+		query = fmt.Sprintf("UPDATE %s %s WHERE %s", r.table.Name, set, strings.Join(where, " AND "))
+	}
+
+	ext := r.db.Primary(ctx)
+	result, err := ext.ExecContext(ctx, ext.Rebind(query), args...)
+	if err != nil {
+		return r.writeError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		// MySQL does not count the rows updated with their current values (versioned rows always change)
+		if err := r.exists(ctx, id); err != nil {
+			return err
+		}
+		if checkVersion {
+			return repositories.ErrVersionMismatch
+		}
+	}
+
+	return nil
+}
+
+// exists returns ErrNotFound if the row does not exist
+func (r *Repository[T, ID]) exists(ctx context.Context, id ID) error {
+	where, args, err := r.where(ctx, nil)
+	if err != nil {
+		return err
+	}
+	where = append([]string{idColumn + " = ?"}, where...)
+	args = append([]any{id}, args...)
+
+	query := fmt.Sprintf("SELECT COUNT(%s) FROM %s WHERE %s", idColumn, r.table.Name, strings.Join(where, " AND "))
+
+	var count int64
+	ext := r.db.Primary(ctx)
+	if err := ext.QueryRowxContext(ctx, ext.Rebind(query), args...).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return r.table.ErrNotFound
+	}
+
+	return nil
+}
+
+// where returns the conditions of the visible rows matching the filters, and their arguments
+func (r *Repository[T, ID]) where(ctx context.Context, filters map[string]string) ([]string, []any, error) {
+	var where []string
+	var args []any
+
+	if r.table.Tenant {
+		organizationID, err := repositories.Tenant(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		where = append(where, organizationColumn+" = ?")
+		args = append(args, organizationID)
+	}
+
+	if r.table.SoftDelete {
+		where = append(where, deletedAtColumn+" IS NULL")
+	}
+
+	for column := range filters {
+		if !slices.Contains(r.table.Filters, column) {
+			return nil, nil, fmt.Errorf("%w: %s", repositories.ErrInvalidFilter, column)
+		}
+	}
+	// Table.Filters order keeps the queries stable
+	for _, column := range r.table.Filters {
+		if value := filters[column]; value != "" {
+			where = append(where, column+" = ?")
+			args = append(args, value)
+		}
+	}
+
+	return where, args, nil
+}
+
+// order returns the ORDER BY clause of the sorts.
+// Only the columns of T can be sorted, so that the sorts never inject SQL.
+func (r *Repository[T, ID]) order(list string) (string, error) {
+	for _, s := range db.ParseSorts(list) {
+		if !slices.Contains(r.columns, s.Field) {
+			return "", fmt.Errorf("%w: %s", repositories.ErrInvalidSort, s.Field)
+		}
+	}
+
+	return db.OrderValues(list), nil
+}
+
+// writeError converts the unique index violations of a write
+func (r *Repository[T, ID]) writeError(err error) error {
+	for index, e := range r.table.Unique {
+		if db.IsDuplicateKeyError(err, index) {
+			return e
+		}
+	}
+	if db.IsDuplicateKeyError(err, "") {
+		return repositories.ErrAlreadyExists
+	}
+
+	return err
+}
+
+// values returns the value of each column of an entity
+func (r *Repository[T, ID]) values(entity T) func(yield func(string, any) bool) {
+	return func(yield func(string, any) bool) {
+		v := reflect.ValueOf(entity)
+		for i := range v.NumField() {
+			column := column(v.Type().Field(i))
+			if column == "" {
+				continue
+			}
+			if !yield(column, v.Field(i).Interface()) {
+				return
+			}
+		}
+	}
+}
+
+// columns returns the columns of a struct type
+func columns(t reflect.Type) []string {
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("sqlx_crud: %s is not a struct", t))
+	}
+
+	var list []string
+	for i := range t.NumField() {
+		if column := column(t.Field(i)); column != "" {
+			list = append(list, column)
+		}
+	}
+
+	return list
+}
+
+// column returns the column of an exported field from its db tag, or an empty string
+func column(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	column, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+	if column == "-" {
+		return ""
+	}
+
+	return column
+}
@@ -0,0 +1,255 @@
+package sqlx_crud
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/usecases"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	projectID1 = "8f2b6f4e-8f53-4a4c-9d1b-6a1f0f6c9e01"
+	projectID2 = "8f2b6f4e-8f53-4a4c-9d1b-6a1f0f6c9e02"
+	projectID3 = "8f2b6f4e-8f53-4a4c-9d1b-6a1f0f6c9e03"
+)
+
+var errCodeAlreadyExists = errors.New("code already exists")
+
+type project struct {
+	ID             string `db:"id"`
+	OrganizationID string `db:"organization_id"`
+	Code           string `db:"code"`
+	Name           string `db:"name"`
+	Status         string `db:"status"`
+	Version        uint64 `db:"version"`
+	CreatedAt      string `db:"created_at"`
+	internal       string
+}
+
+// newTestRepository returns a repository of projects on an in-memory SQLite database
+func newTestRepository(t *testing.T, table Table) *Repository[project, string] {
+	table.Name = "projects"
+
+	return NewRepository[project, string](newTestDatabase(t).Resolver(), table)
+}
+
+// newTestDatabase returns an in-memory SQLite database with a projects table
+func newTestDatabase(t *testing.T) *db.SqlxSQLite {
+	conn, err := db.NewSqlxSQLite(&db.Config{Database: db.SQLiteMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.DB.Close() })
+
+	_, err = conn.DB.Exec(`
+		CREATE TABLE projects (
+			id TEXT PRIMARY KEY,
+			organization_id TEXT NOT NULL DEFAULT '',
+			code TEXT NOT NULL UNIQUE,
+			name TEXT NOT NULL,
+			status TEXT NOT NULL,
+			version INTEGER NOT NULL DEFAULT 1,
+			created_at TEXT NOT NULL,
+			deleted_at TEXT NULL
+		)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+func newProject(id, code, name, status string) project {
+	return project{ID: id, Code: code, Name: name, Status: status, CreatedAt: "2024-01-01 10:00:00", internal: "ignored"}
+}
+
+func TestColumns(t *testing.T) {
+	r := newTestRepository(t, Table{})
+
+	assert.Equal(t, []string{"id", "organization_id", "code", "name", "status", "version", "created_at"}, r.columns)
+	assert.Panics(t, func() { NewRepository[string, string](nil, Table{}) })
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, Table{
+		Filters: []string{"status"},
+		Unique:  map[string]error{"code": errCodeAlreadyExists},
+	})
+
+	assert.Nil(t, r.Create(ctx, newProject(projectID1, "b", "Project B", "open")))
+	assert.Nil(t, r.Create(ctx, newProject(projectID2, "a", "Project A", "closed")))
+	assert.Nil(t, r.Create(ctx, newProject(projectID3, "c", "Project C", "open")))
+	assert.ErrorIs(t, r.Create(ctx, newProject("other", "a", "Project A", "open")), errCodeAlreadyExists)
+	assert.ErrorIs(t, r.Create(ctx, newProject(projectID1, "d", "Project D", "open")), repositories.ErrAlreadyExists)
+
+	t.Run("GetByID", func(t *testing.T) {
+		p, err := r.GetByID(ctx, projectID1)
+		assert.Nil(t, err)
+		assert.Equal(t, "Project B", p.Name)
+		assert.Equal(t, "", p.internal)
+
+		_, err = r.GetByID(ctx, "unknown")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+		list, err := r.GetAll(ctx, requests.List{Pagination: requests.Pagination{Sorts: "+code"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, codes(list))
+
+		list, err = r.GetAll(ctx, requests.List{Pagination: requests.Pagination{Sorts: "-code", Page: "2", Limit: "2"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a"}, codes(list))
+
+		list, err = r.GetAll(ctx, requests.List{
+			Pagination: requests.Pagination{Sorts: "+code"},
+			Filters:    map[string]string{"status": "open"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"b", "c"}, codes(list))
+
+		list, err = r.GetAll(ctx, requests.List{Filters: map[string]string{"status": "unknown"}})
+		assert.Nil(t, err)
+		assert.Nil(t, list)
+
+		_, err = r.GetAll(ctx, requests.List{Pagination: requests.Pagination{Sorts: "+deleted_at"}})
+		assert.ErrorIs(t, err, repositories.ErrInvalidSort)
+		assert.EqualError(t, err, "invalid sort field: deleted_at")
+
+		_, err = r.GetAll(ctx, requests.List{Filters: map[string]string{"name": "Project A"}})
+		assert.ErrorIs(t, err, repositories.ErrInvalidFilter)
+		assert.EqualError(t, err, "invalid filter: name")
+	})
+
+	t.Run("CountAll", func(t *testing.T) {
+		count, err := r.CountAll(ctx, requests.List{})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+
+		count, err = r.CountAll(ctx, requests.List{Filters: map[string]string{"status": "closed"}})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		p := newProject(projectID1, "b", "Project B2", "closed")
+		p.CreatedAt = "2030-01-01 10:00:00"
+		assert.Nil(t, r.Update(ctx, projectID1, 0, p))
+
+		// Without change
+		assert.Nil(t, r.Update(ctx, projectID1, 0, p))
+
+		p, err := r.GetByID(ctx, projectID1)
+		assert.Nil(t, err)
+		assert.Equal(t, "Project B2", p.Name)
+		assert.Equal(t, "closed", p.Status)
+		assert.Equal(t, "2024-01-01 10:00:00", p.CreatedAt)
+		assert.Equal(t, uint64(0), p.Version) // Not versioned
+
+		assert.ErrorIs(t, r.Update(ctx, projectID1, 0, newProject(projectID1, "a", "Project B", "open")), errCodeAlreadyExists)
+		assert.ErrorIs(t, r.Update(ctx, "unknown", 0, p), repositories.ErrNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Nil(t, r.Delete(ctx, projectID1, 0))
+		assert.ErrorIs(t, r.Delete(ctx, projectID1, 0), repositories.ErrNotFound)
+
+		_, err := r.GetByID(ctx, projectID1)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestRepositoryWithTenantSoftDeleteAndVersion(t *testing.T) {
+	ctx := repositories.WithTenant(context.Background(), "org1")
+	other := repositories.WithTenant(context.Background(), "org2")
+	r := newTestRepository(t, Table{Tenant: true, SoftDelete: true, Versioned: true})
+
+	err := r.Create(context.Background(), newProject(projectID1, "a", "Project A", "open"))
+	assert.ErrorIs(t, err, repositories.ErrNoTenant)
+
+	p := newProject(projectID1, "a", "Project A", "open")
+	p.OrganizationID = "org2"
+	p.Version = 10
+	assert.Nil(t, r.Create(ctx, p))
+
+	p, err = r.GetByID(ctx, projectID1)
+	assert.Nil(t, err)
+	assert.Equal(t, "org1", p.OrganizationID)
+	assert.Equal(t, uint64(1), p.Version)
+
+	_, err = r.GetByID(other, projectID1)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+	count, err := r.CountAll(other, requests.List{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	assert.ErrorIs(t, r.Update(other, projectID1, 0, p), repositories.ErrNotFound)
+	assert.ErrorIs(t, r.Delete(other, projectID1, 0), repositories.ErrNotFound)
+
+	assert.Nil(t, r.Update(ctx, projectID1, 1, p))
+	p, err = r.GetByID(ctx, projectID1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), p.Version)
+
+	// Outdated version
+	assert.ErrorIs(t, r.Update(ctx, projectID1, 1, p), repositories.ErrVersionMismatch)
+	assert.ErrorIs(t, r.Delete(ctx, projectID1, 1), repositories.ErrVersionMismatch)
+	assert.ErrorIs(t, r.Update(ctx, projectID2, 1, p), repositories.ErrNotFound)
+
+	assert.Nil(t, r.Delete(ctx, projectID1, 2))
+	_, err = r.GetByID(ctx, projectID1)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+	count, err = r.CountAll(ctx, requests.List{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// The row is kept
+	var version uint64
+	err = r.db.Primary(ctx).QueryRowxContext(ctx, "SELECT version FROM projects WHERE id = ? AND deleted_at IS NOT NULL", projectID1).Scan(&version)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), version)
+}
+
+func TestRepositoryGetAllFromReplica(t *testing.T) {
+	primary := newTestDatabase(t)
+	replica := newTestDatabase(t)
+	resolver := db.NewResolver(primary.DB, []*sqlx.DB{replica.DB}, time.Hour, nil)
+	t.Cleanup(func() { resolver.Close() })
+	resolver.CheckHealth(context.Background())
+
+	table := Table{Name: "projects"}
+	err := NewRepository[project, string](replica.Resolver(), table).Create(context.Background(), newProject(projectID1, "a", "Project A", "open"))
+	assert.Nil(t, err)
+
+	// The list and its total are read from the replica, in the same read-only transaction
+	uc := usecases.NewCRUDUseCase("Project", NewRepository[project, string](resolver, table), db.NewSqlxTxManager(resolver, 0), usecases.CRUDHooks[project]{})
+	page, e := uc.GetAll(context.Background(), requests.List{})
+	assert.Nil(t, e)
+	assert.Equal(t, []string{"a"}, codes(page.Data))
+	assert.Equal(t, int64(1), page.Total)
+
+	// A client which has written reads from the primary
+	page, e = uc.GetAll(db.WithReadYourWrites(context.Background(), true, nil), requests.List{})
+	assert.Nil(t, e)
+	assert.Empty(t, page.Data)
+	assert.Equal(t, int64(0), page.Total)
+}
+
+func codes(list []project) []string {
+	r := make([]string, 0, len(list))
+	for _, p := range list {
+		r = append(r, p.Code)
+	}
+
+	return r
+}
@@ -0,0 +1,242 @@
+package sqlx_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AuditMysqlRepository is an implementation of the AuditRepository interface
+type AuditMysqlRepository struct {
+	db *db.Resolver
+}
+
+// NewAuditMysqlRepository creates a new AuditMysqlRepository
+func NewAuditMysqlRepository(db *db.SqlxMySQL) *AuditMysqlRepository {
+	return &AuditMysqlRepository{db: db.Resolver()}
+}
+
+// Create creates a new audit event
+func (a *AuditMysqlRepository) Create(ctx context.Context, event requests.AuditEventCreationRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO audit_events (id, organization_id, actor_id, action, target_type, target_id, request_id, ip, changes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.ID,
+		organizationID,
+		event.ActorID,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.RequestID,
+		event.IP,
+		event.Changes,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+// CountAll returns the number of audit events matching the filters
+func (a *AuditMysqlRepository) CountAll(ctx context.Context, req requests.AuditEventsList) (int64, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args, err := auditFilters(organizationID, req)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	row := a.db.Primary(ctx).QueryRowxContext(ctx, "SELECT COUNT(id) FROM audit_events"+where, args...)
+	if err := row.Scan(&count); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// GetAll returns the audit events matching the filters with pagination
+func (a *AuditMysqlRepository) GetAll(ctx context.Context, req requests.AuditEventsList) ([]responses.AuditEventsListRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args, err := auditFilters(organizationID, req)
+	if err != nil {
+		return nil, err
+	}
+	query_sort, err := auditOrder(req.Sorts)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit := db.PaginateValues(req.Page, req.Limit)
+
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, request_id, ip, changes, created_at
+		FROM audit_events` + where + query_sort + " LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := a.db.Primary(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]responses.AuditEventsListRepository, 0)
+	for rows.Next() {
+		var event responses.AuditEventsListRepository
+		if err := rows.StructScan(&event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// GetByUser returns all the audit events performed by or on a user
+func (a *AuditMysqlRepository) GetByUser(ctx context.Context, userID string) ([]responses.AuditEventsListRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := a.db.Primary(ctx).QueryxContext(ctx, `
+		SELECT id, actor_id, action, target_type, target_id, request_id, ip, changes, created_at
+		FROM audit_events
+		WHERE organization_id = ?
+			AND (actor_id = ? OR (target_type = 'user' AND target_id = ?))
+		ORDER BY created_at`,
+		organizationID,
+		userID,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]responses.AuditEventsListRepository, 0)
+	for rows.Next() {
+		var event responses.AuditEventsListRepository
+		if err := rows.StructScan(&event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// Erase replaces the IP and the changes of an audit event
+func (a *AuditMysqlRepository) Erase(ctx context.Context, req requests.AuditEventErasureRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE audit_events
+		SET ip = ?, changes = ?
+		WHERE id = ? AND organization_id = ?`,
+		req.IP,
+		req.Changes,
+		req.ID,
+		organizationID,
+	)
+
+	return err
+}
+
+// DeleteBefore deletes the audit events created before a date and returns their number
+func (a *AuditMysqlRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := a.db.Primary(ctx).ExecContext(ctx, `
+		DELETE FROM audit_events
+		WHERE organization_id = ? AND created_at < ?`,
+		organizationID,
+		before,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// auditFilters returns the WHERE clause and its arguments from the list filters of an organization
+func auditFilters(organizationID string, req requests.AuditEventsList) (string, []any, error) {
+	conditions := []string{"organization_id = ?"}
+	args := []any{organizationID}
+
+	if req.ActorID != "" {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, req.ActorID)
+	}
+	if req.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, req.Action)
+	}
+	if req.TargetType != "" {
+		conditions = append(conditions, "target_type = ?")
+		args = append(args, req.TargetType)
+	}
+	if req.TargetID != "" {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, req.TargetID)
+	}
+	if req.From != "" {
+		from, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, from.UTC())
+	}
+	if req.To != "" {
+		to, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, to.UTC())
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// auditSortColumns are the columns which can be used to sort audit events
+var auditSortColumns = []string{"id", "actor_id", "action", "target_type", "target_id", "created_at"}
+
+// auditOrder returns the ORDER BY clause of the sorts of an audit events list (latest events first by default)
+func auditOrder(list string) (string, error) {
+	for _, s := range db.ParseSorts(list) {
+		if !slices.Contains(auditSortColumns, s.Field) {
+			return "", fmt.Errorf("%w: %s", repositories.ErrInvalidSort, s.Field)
+		}
+	}
+
+	if order := db.OrderValues(list); order != "" {
+		return order, nil
+	}
+
+	return " ORDER BY created_at DESC", nil
+}
@@ -0,0 +1,93 @@
+package sqlx_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// OrganizationMysqlRepository is an implementation of the OrganizationRepository interface
+type OrganizationMysqlRepository struct {
+	db *db.Resolver
+}
+
+// NewOrganizationMysqlRepository creates a new OrganizationMysqlRepository
+func NewOrganizationMysqlRepository(db *db.SqlxMySQL) *OrganizationMysqlRepository {
+	return &OrganizationMysqlRepository{db: db.Resolver()}
+}
+
+// Create creates a new organization
+func (o *OrganizationMysqlRepository) Create(ctx context.Context, req requests.OrganizationCreationRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO organizations (id, name, slug, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		req.ID,
+		req.Name,
+		req.Slug,
+		req.CreatedAt,
+		req.UpdatedAt,
+	)
+	if db.IsDuplicateKeyError(err, "slug") {
+		return repositories.ErrSlugAlreadyExists
+	}
+
+	return err
+}
+
+// GetByID returns an organization by ID
+func (o *OrganizationMysqlRepository) GetByID(ctx context.Context, req requests.OrganizationByID) (responses.OrganizationRepository, error) {
+	return o.get(ctx, "id", req.ID)
+}
+
+// GetBySlug returns an organization by slug
+func (o *OrganizationMysqlRepository) GetBySlug(ctx context.Context, req requests.OrganizationBySlug) (responses.OrganizationRepository, error) {
+	return o.get(ctx, "slug", req.Slug)
+}
+
+// GetAll returns all organizations sorted by slug
+func (o *OrganizationMysqlRepository) GetAll(ctx context.Context) ([]responses.OrganizationRepository, error) {
+	rows, err := o.db.Primary(ctx).QueryxContext(ctx, `
+		SELECT id, name, slug, created_at, updated_at
+		FROM organizations
+		ORDER BY slug`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := make([]responses.OrganizationRepository, 0)
+	for rows.Next() {
+		var organization responses.OrganizationRepository
+		if err := rows.StructScan(&organization); err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, organization)
+	}
+
+	return organizations, rows.Err()
+}
+
+// get returns the organization whose column equals the value
+func (o *OrganizationMysqlRepository) get(ctx context.Context, column, value string) (responses.OrganizationRepository, error) {
+	var organization responses.OrganizationRepository
+	row := o.db.Primary(ctx).QueryRowxContext(ctx, `
+		SELECT id, name, slug, created_at, updated_at
+		FROM organizations
+		WHERE `+column+` = ?
+		LIMIT 1`,
+		value,
+	)
+	if err := row.StructScan(&organization); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return organization, repositories.ErrOrganizationNotFound
+		}
+		return organization, err
+	}
+
+	return organization, nil
+}
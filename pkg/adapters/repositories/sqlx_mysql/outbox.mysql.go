@@ -0,0 +1,127 @@
+package sqlx_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+)
+
+// OutboxMysqlRepository is an implementation of the OutboxRepository interface
+type OutboxMysqlRepository struct {
+	db *db.Resolver
+}
+
+// NewOutboxMysqlRepository creates a new OutboxMysqlRepository
+func NewOutboxMysqlRepository(db *db.SqlxMySQL) *OutboxMysqlRepository {
+	return &OutboxMysqlRepository{db: db.Resolver()}
+}
+
+// Create saves a new event in the outbox
+func (o *OutboxMysqlRepository) Create(ctx context.Context, event requests.OutboxEventCreationRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, request_id, created_at, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.ID,
+		event.AggregateType,
+		event.AggregateID,
+		event.EventType,
+		event.Payload,
+		event.RequestID,
+		event.CreatedAt,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+// GetPending returns the events ready to be published in the order they were saved: events whose next attempt is due
+// and whose aggregate has no older event waiting for its next attempt.
+func (o *OutboxMysqlRepository) GetPending(ctx context.Context, req requests.OutboxEventsPendingRepository) ([]responses.OutboxEventRepository, error) {
+	rows, err := o.db.Primary(ctx).QueryxContext(ctx, `
+		SELECT e.seq, e.id, e.aggregate_type, e.aggregate_id, e.event_type, e.payload, e.request_id, e.attempts, e.created_at, e.next_attempt_at
+		FROM outbox_events e
+		WHERE e.published_at IS NULL
+		  AND e.next_attempt_at <= ?
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM outbox_events b
+		      WHERE b.aggregate_type = e.aggregate_type
+		        AND b.aggregate_id = e.aggregate_id
+		        AND b.published_at IS NULL
+		        AND b.seq < e.seq
+		        AND b.next_attempt_at > ?
+		  )
+		ORDER BY e.seq
+		LIMIT ?`,
+		req.Now,
+		req.Now,
+		req.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]responses.OutboxEventRepository, 0)
+	for rows.Next() {
+		var event responses.OutboxEventRepository
+		if err := rows.StructScan(&event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// MarkPublished marks an event as published
+func (o *OutboxMysqlRepository) MarkPublished(ctx context.Context, req requests.OutboxEventPublishedRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE outbox_events
+		SET published_at = ?, last_error = NULL
+		WHERE id = ?`,
+		req.PublishedAt,
+		req.ID,
+	)
+
+	return err
+}
+
+// MarkFailed records a failed publication of an event and the date of its next attempt
+func (o *OutboxMysqlRepository) MarkFailed(ctx context.Context, req requests.OutboxEventFailureRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = ?, next_attempt_at = ?
+		WHERE id = ?`,
+		req.LastError,
+		req.NextAttemptAt,
+		req.ID,
+	)
+
+	return err
+}
+
+// Erase replaces the payloads of all the events of an aggregate
+func (o *OutboxMysqlRepository) Erase(ctx context.Context, req requests.OutboxEventsErasureRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE outbox_events
+		SET payload = ?
+		WHERE aggregate_type = ? AND aggregate_id = ?`,
+		req.Payload,
+		req.AggregateType,
+		req.AggregateID,
+	)
+
+	return err
+}
+
+// DeletePublishedBefore deletes the events published before a date and returns their number
+func (o *OutboxMysqlRepository) DeletePublishedBefore(ctx context.Context, before string) (int64, error) {
+	result, err := o.db.Primary(ctx).ExecContext(ctx, `DELETE FROM outbox_events WHERE published_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -2,30 +2,73 @@ package sqlx_mysql
 
 import (
 	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/repositories/sqlx_crud"
 	"chi_boilerplate/pkg/domain/repositories"
 	"chi_boilerplate/pkg/domain/requests"
 	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"database/sql"
+	"errors"
 
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/jmoiron/sqlx"
 )
 
-// UserMysqlRepository is an implementation of the UserRepository interface
+// usersTable is the users table for the generic repository reading the users
+var usersTable = sqlx_crud.Table{
+	Name:        "users",
+	Tenant:      true,
+	SoftDelete:  true,
+	Versioned:   true,
+	Filters:     []string{"status"},
+	Unique:      map[string]error{"email": repositories.ErrEmailAlreadyExists},
+	ErrNotFound: repositories.ErrUserNotFound,
+}
+
+// UserMysqlRepository is an implementation of the UserRepository interface.
+// GetByID, GetAll and CountAll are read from the replicas if there are some.
+// Users are scoped to the organization of the context (see repositories.WithTenant).
 type UserMysqlRepository struct {
-	db *sqlx.DB
+	db    *db.Resolver
+	users *sqlx_crud.Repository[responses.UserByIdRepository, string]
+	list  *sqlx_crud.Repository[responses.UsersListRepository, string]
 }
 
 // NewUserMysqlRepository creates a new UserMysqlRepository
 func NewUserMysqlRepository(db *db.SqlxMySQL) *UserMysqlRepository {
-	return &UserMysqlRepository{db: db.DB}
+	return &UserMysqlRepository{
+		db:    db.Resolver(),
+		users: sqlx_crud.NewRepository[responses.UserByIdRepository, string](db.Resolver(), usersTable),
+		list:  sqlx_crud.NewRepository[responses.UsersListRepository, string](db.Resolver(), usersTable),
+	}
+}
+
+// GetByID returns a user by ID
+func (u *UserMysqlRepository) GetByID(ctx context.Context, req requests.UserByID) (responses.UserByIdRepository, error) {
+	return u.users.GetByID(ctx, req.ID)
+}
+
+// GetAll returns a page of users
+func (u *UserMysqlRepository) GetAll(ctx context.Context, req requests.UsersList) ([]responses.UsersListRepository, error) {
+	return u.list.GetAll(ctx, req.List())
+}
+
+// CountAll returns the number of users matching the filters
+func (u *UserMysqlRepository) CountAll(ctx context.Context, req requests.UsersList) (int64, error) {
+	return u.list.CountAll(ctx, req.List())
 }
 
 // Create creates a new user
-func (u *UserMysqlRepository) Create(user requests.UserCreationRepository) error {
-	_, err := u.db.Exec(`
-		INSERT INTO users (id, email, password, lastname, firstname, created_at, updated_at) 
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+func (u *UserMysqlRepository) Create(ctx context.Context, user requests.UserCreationRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO users (id, organization_id, email, password, lastname, firstname, created_at, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 		user.ID,
+		organizationID,
 		user.Email,
 		user.Password,
 		user.Lastname,
@@ -35,43 +78,38 @@ func (u *UserMysqlRepository) Create(user requests.UserCreationRepository) error
 	)
 
 	if err != nil {
+		if db.IsDuplicateKeyError(err, "email") {
+			return repositories.ErrEmailAlreadyExists
+		}
 		return err
 	}
 
 	return nil
 }
 
-// GetByID returns a user by ID
-func (u *UserMysqlRepository) GetByID(req requests.UserByID) (responses.UserByIdRepository, error) {
-	var user responses.UserByIdRepository
-	row := u.db.QueryRowx(`
-		SELECT id, email, lastname, firstname, created_at, updated_at
-		FROM users 
-		WHERE id = ?
-			AND deleted_at IS NULL
-		LIMIT 1`,
-		req.ID,
-	)
-	if err := row.StructScan(&user); err != nil {
-		return user, repositories.ErrUserNotFound
+// GetByID returns a user by Email
+func (u *UserMysqlRepository) GetByEmail(ctx context.Context, req requests.GetByEmail) (responses.GetByEmail, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return responses.GetByEmail{}, err
 	}
 
-	return user, nil
-}
-
-// GetByID returns a user by Email
-func (u *UserMysqlRepository) GetByEmail(req requests.GetByEmail) (responses.GetByEmail, error) {
 	var user responses.GetByEmailRepository
-	row := u.db.QueryRowx(`
-		SELECT id, password
+	row := u.db.Primary(ctx).QueryRowxContext(ctx, `
+		SELECT id, password, status
 		FROM users 
 		WHERE email = ?
+			AND organization_id = ?
 			AND deleted_at IS NULL
 		LIMIT 1`,
 		req.Email,
+		organizationID,
 	)
 	if err := row.StructScan(&user); err != nil {
-		return responses.GetByEmail{}, repositories.ErrUserNotFound
+		if errors.Is(err, sql.ErrNoRows) {
+			return responses.GetByEmail{}, repositories.ErrUserNotFound
+		}
+		return responses.GetByEmail{}, err
 	}
 
 	response, err := user.ToGetByEmail()
@@ -83,15 +121,71 @@ func (u *UserMysqlRepository) GetByEmail(req requests.GetByEmail) (responses.Get
 }
 
 // Delete deletes a user
-func (u *UserMysqlRepository) Delete(req requests.UserDelete) error {
-	result, err := u.db.Exec(`
+func (u *UserMysqlRepository) Delete(ctx context.Context, req requests.UserDelete) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
 		UPDATE users
-		SET deleted_at = NOW()
+		SET deleted_at = NOW(), version = version + 1
 		WHERE id = ?
-			AND deleted_at IS NULL`,
+			AND organization_id = ?
+			AND deleted_at IS NULL`
+	args := []any{req.ID, organizationID}
+	if req.Version > 0 {
+		query += " AND version = ?"
+		args = append(args, req.Version)
+	}
+
+	result, err := u.db.Primary(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return u.notAffectedError(ctx, organizationID, req.ID)
+	}
+
+	return err
+}
+
+func (u *UserMysqlRepository) Update(ctx context.Context, req requests.UserUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE users
+		SET lastname = ?, firstname = ?, email = ?, password = ?, updated_at = ?, version = version + 1
+		WHERE id = ?
+			AND organization_id = ?
+			AND deleted_at IS NULL`
+	args := []any{
+		req.Lastname,
+		req.Firstname,
+		req.Email,
+		req.Password,
+		req.UpdatedAt,
 		req.ID,
-	)
+		organizationID,
+	}
+	if req.Version > 0 {
+		query += " AND version = ?"
+		args = append(args, req.Version)
+	}
+
+	result, err := u.db.Primary(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
+		if db.IsDuplicateKeyError(err, "email") {
+			return repositories.ErrEmailAlreadyExists
+		}
 		return err
 	}
 
@@ -100,70 +194,151 @@ func (u *UserMysqlRepository) Delete(req requests.UserDelete) error {
 		return err
 	}
 	if affected == 0 {
-		return repositories.ErrUserNotFound
+		return u.notAffectedError(ctx, organizationID, req.ID)
 	}
 
 	return err
 }
 
-func (u *UserMysqlRepository) CountAll() (int64, error) {
-	var count int64
-	row := u.db.QueryRowx(`
-		SELECT COUNT(id)
-		FROM users 
-		WHERE deleted_at IS NULL
-	`)
-	if err := row.Scan(&count); err != nil {
-		return count, err
+// UpdateStatus changes the status of a user
+func (u *UserMysqlRepository) UpdateStatus(ctx context.Context, req requests.UserStatusUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE users
+		SET status = ?, status_reason = ?, updated_at = ?, version = version + 1
+		WHERE id = ?
+			AND organization_id = ?
+			AND deleted_at IS NULL`
+	args := []any{
+		req.Status,
+		req.Reason,
+		req.UpdatedAt,
+		req.ID,
+		organizationID,
+	}
+	if req.Version > 0 {
+		query += " AND version = ?"
+		args = append(args, req.Version)
 	}
 
-	return count, nil
+	result, err := u.db.Primary(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return u.notAffectedError(ctx, organizationID, req.ID)
+	}
+
+	return nil
 }
 
-func (u *UserMysqlRepository) GetAll(req requests.UsersList) ([]responses.UsersListRepository, error) {
-	offset, limit := db.PaginateValues(req.Page, req.Limit)
-	query_sort := db.OrderValues(req.Sorts)
+// UpdateAvatar changes the avatar of a user
+func (u *UserMysqlRepository) UpdateAvatar(ctx context.Context, req requests.UserAvatarUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
 
 	query := `
-		SELECT id, email, lastname, firstname, created_at, updated_at
-		FROM users 
-		WHERE deleted_at IS NULL`
+		UPDATE users
+		SET avatar = ?, updated_at = ?, version = version + 1
+		WHERE id = ?
+			AND organization_id = ?
+			AND deleted_at IS NULL`
+	args := []any{
+		req.Avatar,
+		req.UpdatedAt,
+		req.ID,
+		organizationID,
+	}
+	if req.Version > 0 {
+		query += " AND version = ?"
+		args = append(args, req.Version)
+	}
 
-	if len(query_sort) > 0 {
-		query += query_sort
+	result, err := u.db.Primary(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
 	}
-	query += " LIMIT ? OFFSET ?"
 
-	rows, err := u.db.Queryx(query, limit, offset)
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return u.notAffectedError(ctx, organizationID, req.ID)
+	}
+
+	return nil
+}
+
+// GetErasable returns the deleted users whose personal data have not been erased yet
+func (u *UserMysqlRepository) GetErasable(ctx context.Context, req requests.UsersErasableRepository) ([]responses.UserErasableRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.db.Primary(ctx).QueryxContext(ctx, `
+		SELECT id, avatar
+		FROM users
+		WHERE organization_id = ?
+			AND deleted_at IS NOT NULL
+			AND deleted_at <= ?
+			AND erased_at IS NULL
+		ORDER BY deleted_at
+		LIMIT ?`,
+		organizationID,
+		req.DeletedBefore,
+		req.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []responses.UsersListRepository
+	users := make([]responses.UserErasableRepository, 0)
 	for rows.Next() {
-		var user responses.UsersListRepository
+		var user responses.UserErasableRepository
 		if err := rows.StructScan(&user); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
 	}
 
-	return users, nil
+	return users, rows.Err()
 }
 
-func (u *UserMysqlRepository) Update(req requests.UserUpdateRepository) error {
-	result, err := u.db.Exec(`
+// Erase anonymises the personal data of a deleted user.
+// The row is kept so that audit events and other references remain valid.
+func (u *UserMysqlRepository) Erase(ctx context.Context, req requests.UserErasureRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := u.db.Primary(ctx).ExecContext(ctx, `
 		UPDATE users
-		SET lastname = ?, firstname = ?, email = ?, password = ?, updated_at = ?
+		SET email = ?, password = '', lastname = '', firstname = '', avatar = '', status_reason = '',
+			erased_at = ?, updated_at = ?, version = version + 1
 		WHERE id = ?
-			AND deleted_at IS NULL`,
-		req.Lastname,
-		req.Firstname,
+			AND organization_id = ?
+			AND deleted_at IS NOT NULL
+			AND erased_at IS NULL`,
 		req.Email,
-		req.Password,
-		req.UpdatedAt,
+		req.ErasedAt,
+		req.ErasedAt,
 		req.ID,
+		organizationID,
 	)
 	if err != nil {
 		return err
@@ -177,5 +352,28 @@ func (u *UserMysqlRepository) Update(req requests.UserUpdateRepository) error {
 		return repositories.ErrUserNotFound
 	}
 
-	return err
+	return nil
+}
+
+// notAffectedError returns the error explaining why a mutation did not affect the user:
+// either the user does not exist anymore or its version has changed.
+func (u *UserMysqlRepository) notAffectedError(ctx context.Context, organizationID, id string) error {
+	var count int64
+	row := u.db.Primary(ctx).QueryRowxContext(ctx, `
+		SELECT COUNT(id)
+		FROM users
+		WHERE id = ?
+			AND organization_id = ?
+			AND deleted_at IS NULL`,
+		id,
+		organizationID,
+	)
+	if err := row.Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return repositories.ErrUserNotFound
+	}
+
+	return repositories.ErrUserVersionMismatch
 }
@@ -0,0 +1,64 @@
+package sqlx_mysql
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+)
+
+// UserSearchMysqlRepository is an implementation of the UserSearchRepository interface
+// using the FULLTEXT index of the users table (see the idx_users_search migration).
+// Searches are read from the replicas if there are some.
+type UserSearchMysqlRepository struct {
+	db *db.Resolver
+}
+
+// NewUserSearchMysqlRepository creates a new UserSearchMysqlRepository
+func NewUserSearchMysqlRepository(db *db.SqlxMySQL) *UserSearchMysqlRepository {
+	return &UserSearchMysqlRepository{db: db.Resolver()}
+}
+
+// Search returns the users matching all the words of the query, the most relevant first
+func (u *UserSearchMysqlRepository) Search(ctx context.Context, req requests.UserSearchRepository) ([]responses.UserSearchHit, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.MySQLBooleanQuery(req.Terms())
+	if query == "" {
+		return nil, nil
+	}
+
+	var hits []responses.UserSearchHit
+	rows, err := u.db.Reader(ctx).QueryxContext(ctx, `
+		SELECT id, email, lastname, firstname, status, created_at, updated_at,
+			MATCH (lastname, firstname, email) AGAINST (? IN BOOLEAN MODE) AS score
+		FROM users
+		WHERE organization_id = ?
+			AND deleted_at IS NULL
+			AND MATCH (lastname, firstname, email) AGAINST (? IN BOOLEAN MODE)
+		ORDER BY score DESC, lastname, firstname, id
+		LIMIT ?`,
+		query,
+		organizationID,
+		query,
+		req.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit responses.UserSearchHit
+		if err := rows.StructScan(&hit); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits, rows.Err()
+}
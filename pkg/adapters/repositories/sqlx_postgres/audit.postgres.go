@@ -0,0 +1,232 @@
+package sqlx_postgres
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AuditPostgresRepository is an implementation of the AuditRepository interface
+type AuditPostgresRepository struct {
+	db *db.Resolver
+}
+
+// NewAuditPostgresRepository creates a new AuditPostgresRepository
+func NewAuditPostgresRepository(db *db.SqlxPostgres) *AuditPostgresRepository {
+	return &AuditPostgresRepository{db: db.Resolver()}
+}
+
+// Create creates a new audit event
+func (a *AuditPostgresRepository) Create(ctx context.Context, event requests.AuditEventCreationRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO audit_events (id, organization_id, actor_id, action, target_type, target_id, request_id, ip, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		event.ID,
+		organizationID,
+		event.ActorID,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.RequestID,
+		event.IP,
+		event.Changes,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+// CountAll returns the number of audit events matching the filters
+func (a *AuditPostgresRepository) CountAll(ctx context.Context, req requests.AuditEventsList) (int64, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args, err := auditFilters(organizationID, req)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	row := a.db.Primary(ctx).QueryRowxContext(ctx, "SELECT COUNT(id) FROM audit_events"+where, args...)
+	if err := row.Scan(&count); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// GetAll returns the audit events matching the filters with pagination
+func (a *AuditPostgresRepository) GetAll(ctx context.Context, req requests.AuditEventsList) ([]responses.AuditEventsListRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args, err := auditFilters(organizationID, req)
+	if err != nil {
+		return nil, err
+	}
+	query_sort, err := auditOrder(req.Sorts)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit := db.PaginateValues(req.Page, req.Limit)
+
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, request_id, ip, changes, created_at
+		FROM audit_events` + where + query_sort + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	return a.list(ctx, query, args...)
+}
+
+// GetByUser returns all the audit events performed by or on a user
+func (a *AuditPostgresRepository) GetByUser(ctx context.Context, userID string) ([]responses.AuditEventsListRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.list(ctx, `
+		SELECT id, actor_id, action, target_type, target_id, request_id, ip, changes, created_at
+		FROM audit_events
+		WHERE organization_id = $1
+			AND (actor_id = $2 OR (target_type = 'user' AND target_id = $2))
+		ORDER BY created_at`,
+		organizationID,
+		userID,
+	)
+}
+
+// list returns the audit events of a query
+func (a *AuditPostgresRepository) list(ctx context.Context, query string, args ...any) ([]responses.AuditEventsListRepository, error) {
+	rows, err := a.db.Primary(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]responses.AuditEventsListRepository, 0)
+	for rows.Next() {
+		var event responses.AuditEventsListRepository
+		if err := rows.StructScan(&event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// Erase replaces the IP and the changes of an audit event
+func (a *AuditPostgresRepository) Erase(ctx context.Context, req requests.AuditEventErasureRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE audit_events
+		SET ip = $1, changes = $2
+		WHERE id = $3 AND organization_id = $4`,
+		req.IP,
+		req.Changes,
+		req.ID,
+		organizationID,
+	)
+
+	return err
+}
+
+// DeleteBefore deletes the audit events created before a date and returns their number
+func (a *AuditPostgresRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := a.db.Primary(ctx).ExecContext(ctx, `
+		DELETE FROM audit_events
+		WHERE organization_id = $1 AND created_at < $2`,
+		organizationID,
+		before,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// auditFilters returns the WHERE clause and its arguments from the list filters of an organization
+func auditFilters(organizationID string, req requests.AuditEventsList) (string, []any, error) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	add("organization_id = $%d", organizationID)
+
+	if req.ActorID != "" {
+		add("actor_id = $%d", req.ActorID)
+	}
+	if req.Action != "" {
+		add("action = $%d", req.Action)
+	}
+	if req.TargetType != "" {
+		add("target_type = $%d", req.TargetType)
+	}
+	if req.TargetID != "" {
+		add("target_id = $%d", req.TargetID)
+	}
+	if req.From != "" {
+		from, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return "", nil, err
+		}
+		add("created_at >= $%d", from.UTC())
+	}
+	if req.To != "" {
+		to, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return "", nil, err
+		}
+		add("created_at <= $%d", to.UTC())
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// auditSortColumns are the columns which can be used to sort audit events
+var auditSortColumns = []string{"id", "actor_id", "action", "target_type", "target_id", "created_at"}
+
+// auditOrder returns the ORDER BY clause of the sorts of an audit events list (latest events first by default)
+func auditOrder(list string) (string, error) {
+	for _, s := range db.ParseSorts(list) {
+		if !slices.Contains(auditSortColumns, s.Field) {
+			return "", fmt.Errorf("%w: %s", repositories.ErrInvalidSort, s.Field)
+		}
+	}
+
+	if order := db.OrderValues(list); order != "" {
+		return order, nil
+	}
+
+	return " ORDER BY created_at DESC", nil
+}
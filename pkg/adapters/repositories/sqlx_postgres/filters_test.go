@@ -0,0 +1,25 @@
+package sqlx_postgres
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditFilters(t *testing.T) {
+	where, args, err := auditFilters("org", requests.AuditEventsList{
+		ActorID:    "1",
+		Action:     "update",
+		TargetType: "user",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, " WHERE organization_id = $1 AND actor_id = $2 AND action = $3 AND target_type = $4", where)
+	assert.Equal(t, []any{"org", "1", "update", "user"}, args)
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `john\_doe\%@test.com`, escapeLike("john_doe%@test.com"))
+	assert.Equal(t, `a\\b`, escapeLike(`a\b`))
+}
@@ -0,0 +1,94 @@
+package sqlx_postgres
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"strconv"
+)
+
+// JobRunPostgresRepository is an implementation of the JobRunRepository interface
+type JobRunPostgresRepository struct {
+	db *db.Resolver
+}
+
+// NewJobRunPostgresRepository creates a new JobRunPostgresRepository
+func NewJobRunPostgresRepository(db *db.SqlxPostgres) *JobRunPostgresRepository {
+	return &JobRunPostgresRepository{db: db.Resolver()}
+}
+
+// Create records the start of a job run
+func (j *JobRunPostgresRepository) Create(ctx context.Context, run requests.JobRunCreationRepository) error {
+	_, err := j.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO job_runs (id, job, instance, status, scheduled_at, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		run.ID,
+		run.Job,
+		run.Instance,
+		string(entities.JobRunStatusRunning),
+		run.ScheduledAt,
+		run.StartedAt,
+	)
+
+	return err
+}
+
+// Finish records the end of a job run
+func (j *JobRunPostgresRepository) Finish(ctx context.Context, req requests.JobRunFinishRepository) error {
+	_, err := j.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE job_runs
+		SET status = $1, result = $2, error = $3, finished_at = $4
+		WHERE id = $5`,
+		req.Status,
+		req.Result,
+		req.Error,
+		req.FinishedAt,
+		req.ID,
+	)
+
+	return err
+}
+
+// GetAll returns the latest runs first, of a job if req.Job is not empty.
+// Runs are read from the primary: the scheduler checks the last run of a job before running it.
+func (j *JobRunPostgresRepository) GetAll(ctx context.Context, req requests.JobRunsList) ([]responses.JobRunRepository, error) {
+	query := `
+		SELECT id, job, instance, status, result, error, scheduled_at, started_at, finished_at
+		FROM job_runs`
+	args := make([]any, 0, 2)
+	if req.Job != "" {
+		query += ` WHERE job = $1`
+		args = append(args, req.Job)
+	}
+	query += ` ORDER BY scheduled_at DESC, started_at DESC LIMIT $` + strconv.Itoa(len(args)+1)
+	args = append(args, req.Limit)
+
+	rows, err := j.db.Primary(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := make([]responses.JobRunRepository, 0)
+	for rows.Next() {
+		var run responses.JobRunRepository
+		if err := rows.StructScan(&run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// DeleteBefore deletes the runs started before a date and returns their number
+func (j *JobRunPostgresRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	result, err := j.db.Primary(ctx).ExecContext(ctx, `DELETE FROM job_runs WHERE started_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
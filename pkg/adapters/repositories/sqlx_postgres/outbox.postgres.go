@@ -0,0 +1,126 @@
+package sqlx_postgres
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+)
+
+// OutboxPostgresRepository is an implementation of the OutboxRepository interface
+type OutboxPostgresRepository struct {
+	db *db.Resolver
+}
+
+// NewOutboxPostgresRepository creates a new OutboxPostgresRepository
+func NewOutboxPostgresRepository(db *db.SqlxPostgres) *OutboxPostgresRepository {
+	return &OutboxPostgresRepository{db: db.Resolver()}
+}
+
+// Create saves a new event in the outbox
+func (o *OutboxPostgresRepository) Create(ctx context.Context, event requests.OutboxEventCreationRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, request_id, created_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		event.ID,
+		event.AggregateType,
+		event.AggregateID,
+		event.EventType,
+		event.Payload,
+		event.RequestID,
+		event.CreatedAt,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+// GetPending returns the events ready to be published in the order they were saved: events whose next attempt is due
+// and whose aggregate has no older event waiting for its next attempt.
+func (o *OutboxPostgresRepository) GetPending(ctx context.Context, req requests.OutboxEventsPendingRepository) ([]responses.OutboxEventRepository, error) {
+	rows, err := o.db.Primary(ctx).QueryxContext(ctx, `
+		SELECT e.seq, e.id, e.aggregate_type, e.aggregate_id, e.event_type, e.payload, e.request_id, e.attempts, e.created_at, e.next_attempt_at
+		FROM outbox_events e
+		WHERE e.published_at IS NULL
+		  AND e.next_attempt_at <= $1
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM outbox_events b
+		      WHERE b.aggregate_type = e.aggregate_type
+		        AND b.aggregate_id = e.aggregate_id
+		        AND b.published_at IS NULL
+		        AND b.seq < e.seq
+		        AND b.next_attempt_at > $1
+		  )
+		ORDER BY e.seq
+		LIMIT $2`,
+		req.Now,
+		req.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]responses.OutboxEventRepository, 0)
+	for rows.Next() {
+		var event responses.OutboxEventRepository
+		if err := rows.StructScan(&event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// MarkPublished marks an event as published
+func (o *OutboxPostgresRepository) MarkPublished(ctx context.Context, req requests.OutboxEventPublishedRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE outbox_events
+		SET published_at = $1, last_error = NULL
+		WHERE id = $2`,
+		req.PublishedAt,
+		req.ID,
+	)
+
+	return err
+}
+
+// MarkFailed records a failed publication of an event and the date of its next attempt
+func (o *OutboxPostgresRepository) MarkFailed(ctx context.Context, req requests.OutboxEventFailureRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+		WHERE id = $3`,
+		req.LastError,
+		req.NextAttemptAt,
+		req.ID,
+	)
+
+	return err
+}
+
+// Erase replaces the payloads of all the events of an aggregate
+func (o *OutboxPostgresRepository) Erase(ctx context.Context, req requests.OutboxEventsErasureRepository) error {
+	_, err := o.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE outbox_events
+		SET payload = $1
+		WHERE aggregate_type = $2 AND aggregate_id = $3`,
+		req.Payload,
+		req.AggregateType,
+		req.AggregateID,
+	)
+
+	return err
+}
+
+// DeletePublishedBefore deletes the events published before a date and returns their number
+func (o *OutboxPostgresRepository) DeletePublishedBefore(ctx context.Context, before string) (int64, error) {
+	result, err := o.db.Primary(ctx).ExecContext(ctx, `DELETE FROM outbox_events WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,341 @@
+package sqlx_postgres
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/repositories/sqlx_crud"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// usersTable is the users table for the generic repository reading the users
+var usersTable = sqlx_crud.Table{
+	Name:        "users",
+	Tenant:      true,
+	SoftDelete:  true,
+	Versioned:   true,
+	Filters:     []string{"status"},
+	Unique:      map[string]error{"email": repositories.ErrEmailAlreadyExists},
+	ErrNotFound: repositories.ErrUserNotFound,
+}
+
+// UserPostgresRepository is an implementation of the UserRepository interface.
+// Users are scoped to the organization of the context (see repositories.WithTenant).
+type UserPostgresRepository struct {
+	db    *db.Resolver
+	users *sqlx_crud.Repository[responses.UserByIdRepository, string]
+	list  *sqlx_crud.Repository[responses.UsersListRepository, string]
+}
+
+// NewUserPostgresRepository creates a new UserPostgresRepository
+func NewUserPostgresRepository(db *db.SqlxPostgres) *UserPostgresRepository {
+	return &UserPostgresRepository{
+		db:    db.Resolver(),
+		users: sqlx_crud.NewRepository[responses.UserByIdRepository, string](db.Resolver(), usersTable),
+		list:  sqlx_crud.NewRepository[responses.UsersListRepository, string](db.Resolver(), usersTable),
+	}
+}
+
+// GetByID returns a user by ID
+func (u *UserPostgresRepository) GetByID(ctx context.Context, req requests.UserByID) (responses.UserByIdRepository, error) {
+	return u.users.GetByID(ctx, req.ID)
+}
+
+// GetAll returns a page of users
+func (u *UserPostgresRepository) GetAll(ctx context.Context, req requests.UsersList) ([]responses.UsersListRepository, error) {
+	return u.list.GetAll(ctx, req.List())
+}
+
+// CountAll returns the number of users matching the filters
+func (u *UserPostgresRepository) CountAll(ctx context.Context, req requests.UsersList) (int64, error) {
+	return u.list.CountAll(ctx, req.List())
+}
+
+// Create creates a new user
+func (u *UserPostgresRepository) Create(ctx context.Context, user requests.UserCreationRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO users (id, organization_id, email, password, lastname, firstname, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		user.ID,
+		organizationID,
+		user.Email,
+		user.Password,
+		user.Lastname,
+		user.Firstname,
+		user.CreatedAt,
+		user.UpdatedAt,
+	)
+
+	if err != nil {
+		if db.IsDuplicateKeyError(err, "email") {
+			return repositories.ErrEmailAlreadyExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetByEmail returns a user by Email.
+// The comparison is case-insensitive like with the MySQL collation.
+func (u *UserPostgresRepository) GetByEmail(ctx context.Context, req requests.GetByEmail) (responses.GetByEmail, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return responses.GetByEmail{}, err
+	}
+
+	var user responses.GetByEmailRepository
+	row := u.db.Primary(ctx).QueryRowxContext(ctx, `
+		SELECT id, password, status
+		FROM users
+		WHERE email ILIKE $1
+			AND organization_id = $2
+			AND deleted_at IS NULL
+		LIMIT 1`,
+		escapeLike(req.Email),
+		organizationID,
+	)
+	if err := row.StructScan(&user); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return responses.GetByEmail{}, repositories.ErrUserNotFound
+		}
+		return responses.GetByEmail{}, err
+	}
+
+	response, err := user.ToGetByEmail()
+	if err != nil {
+		return responses.GetByEmail{}, repositories.ErrUserNotFound
+	}
+
+	return response, nil
+}
+
+// Delete deletes a user
+func (u *UserPostgresRepository) Delete(ctx context.Context, req requests.UserDelete) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE users
+		SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1
+			AND organization_id = $2
+			AND deleted_at IS NULL`
+	args := []any{req.ID, organizationID}
+	if req.Version > 0 {
+		args = append(args, req.Version)
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+
+	return u.execReturning(ctx, organizationID, req.ID, query, args...)
+}
+
+func (u *UserPostgresRepository) Update(ctx context.Context, req requests.UserUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE users
+		SET lastname = $1, firstname = $2, email = $3, password = $4, updated_at = $5, version = version + 1
+		WHERE id = $6
+			AND organization_id = $7
+			AND deleted_at IS NULL`
+	args := []any{
+		req.Lastname,
+		req.Firstname,
+		req.Email,
+		req.Password,
+		req.UpdatedAt,
+		req.ID,
+		organizationID,
+	}
+	if req.Version > 0 {
+		args = append(args, req.Version)
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+
+	err = u.execReturning(ctx, organizationID, req.ID, query, args...)
+	if db.IsDuplicateKeyError(err, "email") {
+		return repositories.ErrEmailAlreadyExists
+	}
+
+	return err
+}
+
+// UpdateStatus changes the status of a user
+func (u *UserPostgresRepository) UpdateStatus(ctx context.Context, req requests.UserStatusUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE users
+		SET status = $1, status_reason = $2, updated_at = $3, version = version + 1
+		WHERE id = $4
+			AND organization_id = $5
+			AND deleted_at IS NULL`
+	args := []any{
+		req.Status,
+		req.Reason,
+		req.UpdatedAt,
+		req.ID,
+		organizationID,
+	}
+	if req.Version > 0 {
+		args = append(args, req.Version)
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+
+	return u.execReturning(ctx, organizationID, req.ID, query, args...)
+}
+
+// UpdateAvatar changes the avatar of a user
+func (u *UserPostgresRepository) UpdateAvatar(ctx context.Context, req requests.UserAvatarUpdateRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE users
+		SET avatar = $1, updated_at = $2, version = version + 1
+		WHERE id = $3
+			AND organization_id = $4
+			AND deleted_at IS NULL`
+	args := []any{
+		req.Avatar,
+		req.UpdatedAt,
+		req.ID,
+		organizationID,
+	}
+	if req.Version > 0 {
+		args = append(args, req.Version)
+		query += fmt.Sprintf(" AND version = $%d", len(args))
+	}
+
+	return u.execReturning(ctx, organizationID, req.ID, query, args...)
+}
+
+// GetErasable returns the deleted users whose personal data have not been erased yet
+func (u *UserPostgresRepository) GetErasable(ctx context.Context, req requests.UsersErasableRepository) ([]responses.UserErasableRepository, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := u.db.Primary(ctx).QueryxContext(ctx, `
+		SELECT id, avatar
+		FROM users
+		WHERE organization_id = $1
+			AND deleted_at IS NOT NULL
+			AND deleted_at <= $2
+			AND erased_at IS NULL
+		ORDER BY deleted_at
+		LIMIT $3`,
+		organizationID,
+		req.DeletedBefore,
+		req.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]responses.UserErasableRepository, 0)
+	for rows.Next() {
+		var user responses.UserErasableRepository
+		if err := rows.StructScan(&user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// Erase anonymises the personal data of a deleted user.
+// The row is kept so that audit events and other references remain valid.
+func (u *UserPostgresRepository) Erase(ctx context.Context, req requests.UserErasureRepository) error {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	var id string
+	err = u.db.Primary(ctx).QueryRowxContext(ctx, `
+		UPDATE users
+		SET email = $1, password = '', lastname = '', firstname = '', avatar = '', status_reason = '',
+			erased_at = $2, updated_at = $2, version = version + 1
+		WHERE id = $3
+			AND organization_id = $4
+			AND deleted_at IS NOT NULL
+			AND erased_at IS NULL
+		RETURNING id`,
+		req.Email,
+		req.ErasedAt,
+		req.ID,
+		organizationID,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repositories.ErrUserNotFound
+	}
+
+	return err
+}
+
+// execReturning executes a mutation ending with "RETURNING id" on a user.
+// If no row is returned, the error explains why the user has not been affected.
+func (u *UserPostgresRepository) execReturning(ctx context.Context, organizationID, id, query string, args ...any) error {
+	var returned string
+	err := u.db.Primary(ctx).QueryRowxContext(ctx, query+" RETURNING id", args...).Scan(&returned)
+	if errors.Is(err, sql.ErrNoRows) {
+		return u.notAffectedError(ctx, organizationID, id)
+	}
+
+	return err
+}
+
+// notAffectedError returns the error explaining why a mutation did not affect the user:
+// either the user does not exist anymore or its version has changed.
+func (u *UserPostgresRepository) notAffectedError(ctx context.Context, organizationID, id string) error {
+	var count int64
+	row := u.db.Primary(ctx).QueryRowxContext(ctx, `
+		SELECT COUNT(id)
+		FROM users
+		WHERE id = $1
+			AND organization_id = $2
+			AND deleted_at IS NULL`,
+		id,
+		organizationID,
+	)
+	if err := row.Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return repositories.ErrUserNotFound
+	}
+
+	return repositories.ErrUserVersionMismatch
+}
+
+// escapeLike escapes the LIKE / ILIKE wildcards of a value
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,93 @@
+package sqlx_sqlite
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+)
+
+// JobRunSQLiteRepository is an implementation of the JobRunRepository interface
+type JobRunSQLiteRepository struct {
+	db *db.Resolver
+}
+
+// NewJobRunSQLiteRepository creates a new JobRunSQLiteRepository
+func NewJobRunSQLiteRepository(db *db.SqlxSQLite) *JobRunSQLiteRepository {
+	return &JobRunSQLiteRepository{db: db.Resolver()}
+}
+
+// Create records the start of a job run
+func (j *JobRunSQLiteRepository) Create(ctx context.Context, run requests.JobRunCreationRepository) error {
+	_, err := j.db.Primary(ctx).ExecContext(ctx, `
+		INSERT INTO job_runs (id, job, instance, status, scheduled_at, started_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		run.ID,
+		run.Job,
+		run.Instance,
+		string(entities.JobRunStatusRunning),
+		run.ScheduledAt,
+		run.StartedAt,
+	)
+
+	return err
+}
+
+// Finish records the end of a job run
+func (j *JobRunSQLiteRepository) Finish(ctx context.Context, req requests.JobRunFinishRepository) error {
+	_, err := j.db.Primary(ctx).ExecContext(ctx, `
+		UPDATE job_runs
+		SET status = ?, result = ?, error = ?, finished_at = ?
+		WHERE id = ?`,
+		req.Status,
+		req.Result,
+		req.Error,
+		req.FinishedAt,
+		req.ID,
+	)
+
+	return err
+}
+
+// GetAll returns the latest runs first, of a job if req.Job is not empty.
+// Runs are read from the primary: the scheduler checks the last run of a job before running it.
+func (j *JobRunSQLiteRepository) GetAll(ctx context.Context, req requests.JobRunsList) ([]responses.JobRunRepository, error) {
+	query := `
+		SELECT id, job, instance, status, result, error, scheduled_at, started_at, finished_at
+		FROM job_runs`
+	args := make([]any, 0, 2)
+	if req.Job != "" {
+		query += ` WHERE job = ?`
+		args = append(args, req.Job)
+	}
+	query += ` ORDER BY scheduled_at DESC, started_at DESC LIMIT ?`
+	args = append(args, req.Limit)
+
+	rows, err := j.db.Primary(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := make([]responses.JobRunRepository, 0)
+	for rows.Next() {
+		var run responses.JobRunRepository
+		if err := rows.StructScan(&run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// DeleteBefore deletes the runs started before a date and returns their number
+func (j *JobRunSQLiteRepository) DeleteBefore(ctx context.Context, before string) (int64, error) {
+	result, err := j.db.Primary(ctx).ExecContext(ctx, `DELETE FROM job_runs WHERE started_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,217 @@
+// Package search provides an embedded full-text index of the users for the databases without one.
+package search
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Weights of the words of a user: a word of the names is more relevant than a word of the email
+const (
+	nameWeight  = 2
+	emailWeight = 1
+)
+
+// indexedUser is a user of the index with the weights of its words
+type indexedUser struct {
+	organizationID string
+	user           responses.UsersListRepository
+	words          map[string]float64
+}
+
+// UserIndex is an in-memory inverted index of the users implementing the UserSearchRepository interface.
+//
+// The words of the names and of the email are indexed, a word of the query matches the words starting with it.
+// A user must match all the words of the query, its score is the sum over the words of the query of
+// the weight of the matching word (2 for the names, 1 for the email) multiplied by its rarity in the
+// organization (inverse document frequency) and by the matched part of the word.
+// The index only contains the data it is given (see Add and Remove): it is lost when the process stops.
+type UserIndex struct {
+	mu       sync.RWMutex
+	users    map[string]indexedUser        // By ID
+	postings map[string]map[string]float64 // Word => user ID => weight
+	words    []string                      // Sorted words of the postings, for the prefix lookups
+	counts   map[string]int                // Number of users by organization
+}
+
+// NewUserIndex creates a new empty UserIndex
+func NewUserIndex() *UserIndex {
+	return &UserIndex{
+		users:    make(map[string]indexedUser),
+		postings: make(map[string]map[string]float64),
+		counts:   make(map[string]int),
+	}
+}
+
+// Add indexes a user of an organization, or replaces it if it is already indexed
+func (i *UserIndex) Add(organizationID string, user responses.UsersListRepository) {
+	words := make(map[string]float64)
+	for _, field := range []struct {
+		value  string
+		weight float64
+	}{
+		{user.Lastname, nameWeight},
+		{user.Firstname, nameWeight},
+		{user.Email, emailWeight},
+	} {
+		// The words are split like the queries so that they always match
+		for _, word := range (requests.UserSearchRepository{Query: field.value}).Terms() {
+			words[word] = max(words[word], field.weight)
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(user.ID)
+
+	i.users[user.ID] = indexedUser{organizationID: organizationID, user: user, words: words}
+	i.counts[organizationID]++
+	for word, weight := range words {
+		postings, ok := i.postings[word]
+		if !ok {
+			postings = make(map[string]float64)
+			i.postings[word] = postings
+
+			n := sort.SearchStrings(i.words, word)
+			i.words = append(i.words, "")
+			copy(i.words[n+1:], i.words[n:])
+			i.words[n] = word
+		}
+		postings[user.ID] = weight
+	}
+}
+
+// Remove removes a user from the index, unknown users are ignored
+func (i *UserIndex) Remove(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+}
+
+// remove removes a user, the lock must be held
+func (i *UserIndex) remove(id string) {
+	indexed, ok := i.users[id]
+	if !ok {
+		return
+	}
+
+	delete(i.users, id)
+	if i.counts[indexed.organizationID]--; i.counts[indexed.organizationID] == 0 {
+		delete(i.counts, indexed.organizationID)
+	}
+	for word := range indexed.words {
+		postings := i.postings[word]
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(i.postings, word)
+
+			n := sort.SearchStrings(i.words, word)
+			i.words = append(i.words[:n], i.words[n+1:]...)
+		}
+	}
+}
+
+// Len returns the number of indexed users
+func (i *UserIndex) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.users)
+}
+
+// Search returns the users of the organization of the context matching all the words of the query,
+// sorted by decreasing score
+func (i *UserIndex) Search(ctx context.Context, req requests.UserSearchRepository) ([]responses.UserSearchHit, error) {
+	organizationID, err := repositories.Tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	terms := req.Terms()
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var scores map[string]float64
+	for _, term := range terms {
+		termScores := i.match(organizationID, term)
+
+		// Users must match all the terms
+		if scores == nil {
+			scores = termScores
+		} else {
+			for id, score := range scores {
+				if termScore, ok := termScores[id]; ok {
+					scores[id] = score + termScore
+				} else {
+					delete(scores, id)
+				}
+			}
+		}
+		if len(scores) == 0 {
+			return nil, nil
+		}
+	}
+
+	hits := make([]responses.UserSearchHit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, responses.UserSearchHit{UsersListRepository: i.users[id].user, Score: score})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		if hits[a].Lastname != hits[b].Lastname {
+			return hits[a].Lastname < hits[b].Lastname
+		}
+		if hits[a].Firstname != hits[b].Firstname {
+			return hits[a].Firstname < hits[b].Firstname
+		}
+		return hits[a].ID < hits[b].ID
+	})
+
+	if req.Limit > 0 && len(hits) > req.Limit {
+		hits = hits[:req.Limit]
+	}
+
+	return hits, nil
+}
+
+// match returns the scores of the users of an organization having a word starting with term.
+// The read lock must be held.
+func (i *UserIndex) match(organizationID, term string) map[string]float64 {
+	scores := make(map[string]float64)
+	for n := sort.SearchStrings(i.words, term); n < len(i.words) && strings.HasPrefix(i.words[n], term); n++ {
+		word := i.words[n]
+		coverage := float64(len(term)) / float64(len(word))
+
+		for id, weight := range i.postings[word] {
+			if i.users[id].organizationID != organizationID {
+				continue
+			}
+			scores[id] = max(scores[id], weight*coverage)
+		}
+	}
+
+	// Rare terms are more relevant
+	idf := math.Log(1 + float64(i.counts[organizationID])/float64(max(len(scores), 1)))
+	for id := range scores {
+		scores[id] *= idf
+	}
+
+	return scores
+}
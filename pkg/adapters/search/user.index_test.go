@@ -0,0 +1,78 @@
+package search
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const otherTenant = "8f2b6f4e-8f53-4a4c-9d1b-6a1f0f6c9eff"
+
+// newTestIndex returns an index of users of the default organization and of another one
+func newTestIndex() *UserIndex {
+	index := NewUserIndex()
+	index.Add(entities.DefaultOrganizationID, responses.UsersListRepository{ID: "1", Email: "john.doe@example.com", Lastname: "Doe", Firstname: "John"})
+	index.Add(entities.DefaultOrganizationID, responses.UsersListRepository{ID: "2", Email: "jane.doe@example.com", Lastname: "Doe", Firstname: "Jane"})
+	index.Add(entities.DefaultOrganizationID, responses.UsersListRepository{ID: "3", Email: "johnny@example.com", Lastname: "Martin", Firstname: "Paul"})
+	index.Add(otherTenant, responses.UsersListRepository{ID: "4", Email: "john@example.com", Lastname: "Smith", Firstname: "John"})
+
+	return index
+}
+
+// search returns the IDs of the users found by a query in an organization
+func search(t *testing.T, index *UserIndex, organizationID, query string, limit int) []string {
+	hits, err := index.Search(repositories.WithTenant(context.Background(), organizationID), requests.UserSearchRepository{Query: query, Limit: limit})
+	assert.Nil(t, err)
+
+	var ids []string
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestUserIndexSearch(t *testing.T) {
+	index := newTestIndex()
+	org := entities.DefaultOrganizationID
+
+	// The names are more relevant than the email, exact words more than prefixes
+	assert.Equal(t, []string{"1", "3"}, search(t, index, org, "john", 0))
+	assert.Equal(t, []string{"2", "1"}, search(t, index, org, "doe", 0))
+	assert.Equal(t, []string{"1"}, search(t, index, org, "Doe, John", 0))
+	assert.Equal(t, []string{"2"}, search(t, index, org, "doe", 1))
+	// Ties are sorted by names
+	assert.Equal(t, []string{"2", "1", "3"}, search(t, index, org, "example", 0))
+	assert.Nil(t, search(t, index, org, "jo unknown", 0))
+	assert.Nil(t, search(t, index, org, "-", 0))
+
+	// Organizations are isolated
+	assert.Equal(t, []string{"4"}, search(t, index, otherTenant, "john", 0))
+
+	_, err := index.Search(context.Background(), requests.UserSearchRepository{Query: "john"})
+	assert.ErrorIs(t, err, repositories.ErrNoTenant)
+}
+
+func TestUserIndexUpdate(t *testing.T) {
+	index := newTestIndex()
+	org := entities.DefaultOrganizationID
+
+	index.Add(org, responses.UsersListRepository{ID: "1", Email: "john.smith@example.com", Lastname: "Smith", Firstname: "John"})
+	assert.Equal(t, []string{"2"}, search(t, index, org, "doe", 0))
+	assert.Equal(t, []string{"1"}, search(t, index, org, "smith", 0))
+	assert.Equal(t, 4, index.Len())
+
+	index.Remove("1")
+	index.Remove("3")
+	index.Remove("unknown")
+	assert.Nil(t, search(t, index, org, "john", 0))
+	assert.Equal(t, 2, index.Len())
+
+	// The words of the removed users are removed
+	assert.NotContains(t, index.words, "martin")
+	assert.Contains(t, index.words, "jane")
+}
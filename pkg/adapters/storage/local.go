@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage is an implementation of the Storage interface using the local filesystem
+type LocalStorage struct {
+	root      string
+	publicURL string
+}
+
+// NewLocalStorage creates a new LocalStorage storing files in root directory.
+// Files are publicly available under publicURL.
+func NewLocalStorage(root, publicURL string) (*LocalStorage, error) {
+	if root == "" {
+		return nil, errors.New("missing local storage path")
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{
+		root:      filepath.Clean(root),
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}, nil
+}
+
+// Root returns the root directory of the storage
+func (s *LocalStorage) Root() string {
+	return s.root
+}
+
+// Put stores a file
+func (s *LocalStorage) Put(key string, content io.Reader, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Get returns the content of a file
+func (s *LocalStorage) Get(key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, repositories.ErrFileNotFound
+	}
+
+	return f, err
+}
+
+// Delete deletes a file
+func (s *LocalStorage) Delete(key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return repositories.ErrFileNotFound
+	}
+
+	return err
+}
+
+// URL returns the public URL of a file
+func (s *LocalStorage) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// path returns the file path of a key and prevents to go outside the root directory
+func (s *LocalStorage) path(key string) (string, error) {
+	if !fs.ValidPath(key) {
+		return "", errors.New("invalid file key")
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
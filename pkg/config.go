@@ -1,7 +1,9 @@
 package pkg
 
 import (
+	"chi_boilerplate/utils"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fabienbellanger/goutils"
@@ -19,6 +21,21 @@ type ConfigServer struct {
 	// Timeout
 	Timeout int
 
+	// Maximum duration of the reading of the request headers
+	ReadHeaderTimeout time.Duration
+
+	// Maximum duration of the reading of the whole request
+	ReadTimeout time.Duration
+
+	// Maximum duration of the writing of the response
+	WriteTimeout time.Duration
+
+	// Maximum wait of the next request on a keep-alive connection
+	IdleTimeout time.Duration
+
+	// Maximum wait of the in-flight requests at shutdown
+	ShutdownTimeout time.Duration
+
 	// Basic Auth username
 	BasicAuthUsername string
 
@@ -39,13 +56,40 @@ func NewConfigServer() (*ConfigServer, error) {
 		return nil, fmt.Errorf("missing server port")
 	}
 
-	return &ConfigServer{
+	c := &ConfigServer{
 		Addr:              addr,
 		Port:              port,
 		Timeout:           viper.GetInt("SERVER_TIMEOUT"),
+		ReadHeaderTimeout: viper.GetDuration("SERVER_READ_HEADER_TIMEOUT") * time.Second,
+		ReadTimeout:       viper.GetDuration("SERVER_READ_TIMEOUT") * time.Second,
+		WriteTimeout:      viper.GetDuration("SERVER_WRITE_TIMEOUT") * time.Second,
+		IdleTimeout:       viper.GetDuration("SERVER_IDLE_TIMEOUT") * time.Second,
+		ShutdownTimeout:   viper.GetDuration("SERVER_SHUTDOWN_TIMEOUT") * time.Second,
 		BasicAuthUsername: viper.GetString("SERVER_BASICAUTH_USERNAME"),
 		BasicAuthPassword: viper.GetString("SERVER_BASICAUTH_PASSWORD"),
-	}, nil
+	}
+
+	for _, t := range []struct {
+		value        *time.Duration
+		defaultValue time.Duration
+	}{
+		{&c.ReadHeaderTimeout, 5 * time.Second},
+		{&c.ReadTimeout, 30 * time.Second},
+		{&c.WriteTimeout, 60 * time.Second},
+		{&c.IdleTimeout, 120 * time.Second},
+		{&c.ShutdownTimeout, 20 * time.Second},
+	} {
+		if *t.value <= 0 {
+			*t.value = t.defaultValue
+		}
+	}
+
+	// The response of a request timed out by the handlers must still be written
+	if c.WriteTimeout <= time.Duration(c.Timeout)*time.Second {
+		return nil, fmt.Errorf("server write timeout must be greater than the server timeout")
+	}
+
+	return c, nil
 }
 
 // ConfigDatabase represents the configuration of the database
@@ -53,6 +97,9 @@ type ConfigDatabase struct {
 	// Driver
 	Driver string
 
+	// Backend (sqlx | gorm), GORM is only available with MySQL
+	Backend string
+
 	// Host
 	Host string
 
@@ -65,7 +112,7 @@ type ConfigDatabase struct {
 	// Port
 	Port int
 
-	// Database
+	// Database (file path or :memory: for SQLite)
 	Database string
 
 	// Charset
@@ -77,6 +124,9 @@ type ConfigDatabase struct {
 	// Location (UTC | Local)
 	Location string
 
+	// SSL mode (PostgreSQL only)
+	SSLMode string
+
 	// Max idle connections
 	MaxIdleConns int
 
@@ -85,18 +135,63 @@ type ConfigDatabase struct {
 
 	// Connection max lifetime
 	ConnMaxLifetime time.Duration
+
+	// Connection max idle time
+	ConnMaxIdleTime time.Duration
+
+	// Slow SQL queries threshold
+	SlowThreshold time.Duration
+
+	// Maximum wait of the database at startup
+	ConnectTimeout time.Duration
+
+	// Transaction isolation level (read-uncommitted | read-committed | repeatable-read | serializable),
+	// empty to use the default level of the database
+	TxIsolation string
+
+	// Apply migrations when the server starts
+	AutoMigrate bool
+
+	// Read replicas DSNs (MySQL with sqlx only)
+	ReplicaDSNs []string
+
+	// Interval between two health checks of the replicas
+	ReplicaHealthCheckInterval time.Duration
+
+	// Read from the primary after a write of the client
+	ReadYourWrites bool
+
+	// Duration of the reads from the primary after a write of the client
+	ReadYourWritesWindow time.Duration
+
+	// Log the parameters of the SQL queries
+	LogParams bool
 }
 
 // NewConfigDatabase creates a new ConfigDatabase instance
 func NewConfigDatabase() (*ConfigDatabase, error) {
 	driver := viper.GetString("DB_DRIVER")
+	backend := viper.GetString("DB_BACKEND")
 	location := viper.GetString("DB_LOCATION")
 	database := viper.GetString("DB_DATABASE")
+	txIsolation := viper.GetString("DB_TX_ISOLATION")
+	replicaDSNs := viper.GetStringSlice("DB_REPLICA_DSNS")
+	readYourWritesWindow := viper.GetDuration("DB_READ_YOUR_WRITES_WINDOW") * time.Second
 
-	if driver != "mysql" {
+	if driver != "mysql" && driver != "postgres" && driver != "sqlite" {
 		return nil, fmt.Errorf("invalid database driver")
 	}
 
+	if backend == "" {
+		backend = "sqlx"
+	}
+	if backend != "sqlx" && backend != "gorm" {
+		return nil, fmt.Errorf("invalid database backend")
+	}
+	if backend == "gorm" && driver != "mysql" {
+		return nil, fmt.Errorf("gorm database backend is only available with mysql")
+	}
+
 	if location != "UTC" && location != "Local" {
 		return nil, fmt.Errorf("invalid database location")
 	}
@@ -105,19 +200,44 @@ func NewConfigDatabase() (*ConfigDatabase, error) {
 		return nil, fmt.Errorf("missing database name")
 	}
 
+	if txIsolation != "" && txIsolation != "read-uncommitted" && txIsolation != "read-committed" &&
+		txIsolation != "repeatable-read" && txIsolation != "serializable" {
+		return nil, fmt.Errorf("invalid database transaction isolation level")
+	}
+
+	if len(replicaDSNs) > 0 && (driver != "mysql" || backend != "sqlx") {
+		return nil, fmt.Errorf("database read replicas are only available with mysql and sqlx")
+	}
+
+	if readYourWritesWindow <= 0 {
+		readYourWritesWindow = 5 * time.Second
+	}
+
 	return &ConfigDatabase{
-		Driver:          driver,
-		Host:            viper.GetString("DB_HOST"),
-		Username:        viper.GetString("DB_USERNAME"),
-		Password:        viper.GetString("DB_PASSWORD"),
-		Port:            viper.GetInt("DB_PORT"),
-		Database:        database,
-		Charset:         viper.GetString("DB_CHARSET"),
-		Collation:       viper.GetString("DB_COLLATION"),
-		Location:        location,
-		MaxIdleConns:    viper.GetInt("DB_MAX_IDLE_CONNS"),
-		MaxOpenConns:    viper.GetInt("DB_MAX_OPEN_CONNS"),
-		ConnMaxLifetime: viper.GetDuration("DB_CONN_MAX_LIFETIME") * time.Hour,
+		Driver:                     driver,
+		Backend:                    backend,
+		Host:                       viper.GetString("DB_HOST"),
+		Username:                   viper.GetString("DB_USERNAME"),
+		Password:                   viper.GetString("DB_PASSWORD"),
+		Port:                       viper.GetInt("DB_PORT"),
+		Database:                   database,
+		Charset:                    viper.GetString("DB_CHARSET"),
+		Collation:                  viper.GetString("DB_COLLATION"),
+		Location:                   location,
+		SSLMode:                    viper.GetString("DB_SSL_MODE"),
+		MaxIdleConns:               viper.GetInt("DB_MAX_IDLE_CONNS"),
+		MaxOpenConns:               viper.GetInt("DB_MAX_OPEN_CONNS"),
+		ConnMaxLifetime:            viper.GetDuration("DB_CONN_MAX_LIFETIME") * time.Hour,
+		ConnMaxIdleTime:            viper.GetDuration("DB_CONN_MAX_IDLE_TIME") * time.Minute,
+		SlowThreshold:              viper.GetDuration("DB_SLOW_THRESHOLD") * time.Millisecond,
+		ConnectTimeout:             viper.GetDuration("DB_CONNECT_TIMEOUT") * time.Second,
+		TxIsolation:                txIsolation,
+		AutoMigrate:                viper.GetBool("DB_AUTO_MIGRATE"),
+		ReplicaDSNs:                replicaDSNs,
+		ReplicaHealthCheckInterval: viper.GetDuration("DB_REPLICA_HEALTH_CHECK_INTERVAL") * time.Second,
+		ReadYourWrites:             viper.GetBool("DB_READ_YOUR_WRITES"),
+		ReadYourWritesWindow:       readYourWritesWindow,
+		LogParams:                  viper.GetBool("DB_LOG_PARAMS"),
 	}, nil
 }
 
@@ -268,13 +388,270 @@ func NewConfigPprof() *ConfigPprof {
 type ConfigAMQP struct {
 	// URL
 	URL string
+
+	// Exchange of the domain events
+	Exchange string
 }
 
 // NewConfigServer creates a new ConfigServer instance
 func NewConfigAMQP() *ConfigAMQP {
 	return &ConfigAMQP{
-		URL: viper.GetString("AMQP_URL"),
+		URL:      viper.GetString("AMQP_URL"),
+		Exchange: viper.GetString("AMQP_EXCHANGE"),
+	}
+}
+
+// ConfigOutbox represents the configuration of the outbox relay
+type ConfigOutbox struct {
+	// Start the relay with the server
+	RelayEnable bool
+
+	// Interval between two relays of the pending events
+	RelayInterval time.Duration
+
+	// Number of pending events read by each relay
+	BatchSize int
+}
+
+// NewConfigOutbox creates a new ConfigOutbox instance
+func NewConfigOutbox() *ConfigOutbox {
+	interval := viper.GetDuration("OUTBOX_RELAY_INTERVAL") * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	return &ConfigOutbox{
+		RelayEnable:   viper.GetBool("OUTBOX_RELAY_ENABLE"),
+		RelayInterval: interval,
+		BatchSize:     viper.GetInt("OUTBOX_BATCH_SIZE"),
+	}
+}
+
+// ConfigTenant represents the configuration of the multi-tenancy
+type ConfigTenant struct {
+	// Domain whose subdomains are the slugs of the organizations (ex: example.com for acme.example.com),
+	// empty to disable the resolution of the organization by subdomain
+	Domain string
+}
+
+// NewConfigTenant creates a new ConfigTenant instance
+func NewConfigTenant() *ConfigTenant {
+	return &ConfigTenant{
+		Domain: strings.ToLower(strings.TrimPrefix(viper.GetString("TENANT_DOMAIN"), ".")),
+	}
+}
+
+// ConfigStorage represents the configuration of the file storage
+type ConfigStorage struct {
+	// Driver (local | s3)
+	Driver string
+
+	// Local root path
+	LocalPath string
+
+	// Public base URL of the stored files
+	PublicURL string
+
+	// S3 endpoint
+	S3Endpoint string
+
+	// S3 region
+	S3Region string
+
+	// S3 bucket
+	S3Bucket string
+
+	// S3 access key
+	S3AccessKey string
+
+	// S3 secret key
+	S3SecretKey string
+
+	// Avatar max size (in bytes)
+	AvatarMaxSize int64
+
+	// Avatar max dimensions (width x height, in pixels)
+	AvatarMaxPixels int
+}
+
+// NewConfigStorage creates a new ConfigStorage instance
+func NewConfigStorage() (*ConfigStorage, error) {
+	driver := viper.GetString("STORAGE_DRIVER")
+	localPath := viper.GetString("STORAGE_LOCAL_PATH")
+	avatarMaxSize := viper.GetInt64("AVATAR_MAX_SIZE")
+	avatarMaxPixels := viper.GetInt("AVATAR_MAX_PIXELS")
+
+	if driver == "" {
+		driver = "local"
+	}
+
+	if driver != "local" && driver != "s3" {
+		return nil, fmt.Errorf("invalid storage driver")
+	}
+
+	if driver == "local" && localPath == "" {
+		return nil, fmt.Errorf("missing storage local path")
 	}
+
+	if driver == "s3" && (viper.GetString("S3_ENDPOINT") == "" || viper.GetString("S3_BUCKET") == "") {
+		return nil, fmt.Errorf("missing S3 endpoint or bucket")
+	}
+
+	if avatarMaxSize <= 0 {
+		avatarMaxSize = 2048
+	}
+
+	if avatarMaxPixels <= 0 {
+		avatarMaxPixels = 16_000_000
+	}
+
+	return &ConfigStorage{
+		Driver:          driver,
+		LocalPath:       localPath,
+		PublicURL:       viper.GetString("STORAGE_PUBLIC_URL"),
+		S3Endpoint:      viper.GetString("S3_ENDPOINT"),
+		S3Region:        viper.GetString("S3_REGION"),
+		S3Bucket:        viper.GetString("S3_BUCKET"),
+		S3AccessKey:     viper.GetString("S3_ACCESS_KEY"),
+		S3SecretKey:     viper.GetString("S3_SECRET_KEY"),
+		AvatarMaxSize:   avatarMaxSize * 1024,
+		AvatarMaxPixels: avatarMaxPixels,
+	}, nil
+}
+
+// ConfigCache represents the configuration of the cache of the user reads
+type ConfigCache struct {
+	// Driver (none | memory | redis)
+	Driver string
+
+	// Lifetime of the cached values
+	TTL time.Duration
+
+	// Maximum number of entries of the memory cache
+	Size int
+
+	// Redis address (host:port)
+	RedisAddr string
+
+	// Redis password
+	RedisPassword string
+
+	// Redis database number
+	RedisDB int
+
+	// Prefix of the Redis keys
+	RedisPrefix string
+}
+
+// NewConfigCache creates a new ConfigCache instance
+func NewConfigCache() (*ConfigCache, error) {
+	driver := viper.GetString("CACHE_DRIVER")
+	if driver == "" {
+		driver = "none"
+	}
+
+	if driver != "none" && driver != "memory" && driver != "redis" {
+		return nil, fmt.Errorf("invalid cache driver")
+	}
+
+	if driver == "redis" && viper.GetString("REDIS_ADDR") == "" {
+		return nil, fmt.Errorf("missing Redis address")
+	}
+
+	ttl := viper.GetDuration("CACHE_TTL") * time.Second
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	return &ConfigCache{
+		Driver:        driver,
+		TTL:           ttl,
+		Size:          viper.GetInt("CACHE_SIZE"),
+		RedisAddr:     viper.GetString("REDIS_ADDR"),
+		RedisPassword: viper.GetString("REDIS_PASSWORD"),
+		RedisDB:       viper.GetInt("REDIS_DB"),
+		RedisPrefix:   viper.GetString("REDIS_PREFIX"),
+	}, nil
+}
+
+// ConfigScheduler represents the configuration of the scheduled jobs.
+// An empty schedule disables the job.
+type ConfigScheduler struct {
+	// Start the scheduler with the server
+	Enable bool
+
+	// Cron expression of the erasure of the deleted users
+	UsersErasureSchedule string
+
+	// Number of days a deleted user is kept before erasure
+	UsersErasureRetention int
+
+	// Cron expression of the purge of the history of the job runs
+	JobRunsPurgeSchedule string
+
+	// Number of days the job runs are kept
+	JobRunsRetention int
+
+	// Cron expression of the purge of the published outbox events
+	OutboxPurgeSchedule string
+
+	// Number of days the published outbox events are kept
+	OutboxRetention int
+
+	// Cron expression of the purge of the audit events
+	AuditPurgeSchedule string
+
+	// Number of days the audit events are kept (365 by default)
+	AuditRetention int
+
+	// Cron expression of the purge of the rotated log files
+	LogsPurgeSchedule string
+
+	// Number of days the rotated log files are kept
+	LogsRetention int
+}
+
+// NewConfigScheduler creates a new ConfigScheduler instance
+func NewConfigScheduler() (*ConfigScheduler, error) {
+	c := &ConfigScheduler{
+		Enable:                viper.GetBool("SCHEDULER_ENABLE"),
+		UsersErasureSchedule:  viper.GetString("JOB_USERS_ERASURE_SCHEDULE"),
+		UsersErasureRetention: viper.GetInt("JOB_USERS_ERASURE_RETENTION"),
+		JobRunsPurgeSchedule:  viper.GetString("JOB_RUNS_PURGE_SCHEDULE"),
+		JobRunsRetention:      viper.GetInt("JOB_RUNS_RETENTION"),
+		OutboxPurgeSchedule:   viper.GetString("JOB_OUTBOX_PURGE_SCHEDULE"),
+		OutboxRetention:       viper.GetInt("JOB_OUTBOX_RETENTION"),
+		AuditPurgeSchedule:    viper.GetString("JOB_AUDIT_PURGE_SCHEDULE"),
+		AuditRetention:        viper.GetInt("JOB_AUDIT_RETENTION"),
+		LogsPurgeSchedule:     viper.GetString("JOB_LOGS_PURGE_SCHEDULE"),
+		LogsRetention:         viper.GetInt("JOB_LOGS_RETENTION"),
+	}
+
+	for job, schedule := range map[string]string{
+		"users erasure":  c.UsersErasureSchedule,
+		"job runs purge": c.JobRunsPurgeSchedule,
+		"outbox purge":   c.OutboxPurgeSchedule,
+		"audit purge":    c.AuditPurgeSchedule,
+		"logs purge":     c.LogsPurgeSchedule,
+	} {
+		if schedule == "" {
+			continue
+		}
+		if _, err := utils.ParseCron(schedule); err != nil {
+			return nil, fmt.Errorf("invalid schedule of the %s job: %w", job, err)
+		}
+	}
+
+	for _, retention := range []*int{&c.UsersErasureRetention, &c.JobRunsRetention, &c.OutboxRetention, &c.LogsRetention} {
+		if *retention <= 0 {
+			*retention = 30
+		}
+	}
+	if c.AuditRetention <= 0 {
+		c.AuditRetention = 365
+	}
+
+	return c, nil
 }
 
 // Config represents the configuration of the application from the .env file
@@ -305,6 +682,21 @@ type Config struct {
 
 	// AMQP configuration
 	AMQP ConfigAMQP
+
+	// Outbox configuration
+	Outbox ConfigOutbox
+
+	// Multi-tenancy configuration
+	Tenant ConfigTenant
+
+	// Storage configuration
+	Storage ConfigStorage
+
+	// Cache configuration
+	Cache ConfigCache
+
+	// Scheduled jobs configuration
+	Scheduler ConfigScheduler
 }
 
 // NewConfig creates a new Config instance
@@ -336,15 +728,35 @@ func NewConfig(file string) (*Config, error) {
 		return nil, err
 	}
 
+	storageConfig, err := NewConfigStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheConfig, err := NewConfigCache()
+	if err != nil {
+		return nil, err
+	}
+
+	schedulerConfig, err := NewConfigScheduler()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		AppEnv:   viper.GetString("APP_ENV"),
-		AppName:  viper.GetString("APP_NAME"),
-		Server:   *serverConfig,
-		Database: *databaseConfig,
-		Log:      *logConfig,
-		JWT:      *jwtConfig,
-		CORS:     *NewConfigCORS(),
-		Pprof:    *NewConfigPprof(),
-		AMQP:     *NewConfigAMQP(),
+		AppEnv:    viper.GetString("APP_ENV"),
+		AppName:   viper.GetString("APP_NAME"),
+		Server:    *serverConfig,
+		Database:  *databaseConfig,
+		Log:       *logConfig,
+		JWT:       *jwtConfig,
+		CORS:      *NewConfigCORS(),
+		Pprof:     *NewConfigPprof(),
+		AMQP:      *NewConfigAMQP(),
+		Outbox:    *NewConfigOutbox(),
+		Tenant:    *NewConfigTenant(),
+		Storage:   *storageConfig,
+		Cache:     *cacheConfig,
+		Scheduler: *schedulerConfig,
 	}, nil
 }
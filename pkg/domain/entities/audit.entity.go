@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	vo "chi_boilerplate/pkg/domain/value_objects"
+)
+
+// AuditAction is the type of mutation recorded in an audit event
+type AuditAction string
+
+const (
+	// AuditActionCreate is used when an entity is created
+	AuditActionCreate AuditAction = "create"
+
+	// AuditActionUpdate is used when an entity is updated
+	AuditActionUpdate AuditAction = "update"
+
+	// AuditActionDelete is used when an entity is deleted
+	AuditActionDelete AuditAction = "delete"
+
+	// AuditActionErase is used when the personal data of an entity are erased
+	AuditActionErase AuditAction = "erase"
+)
+
+// AuditTargetUser is the target type of user audit events
+const AuditTargetUser = "user"
+
+// AuditFieldChange represents the values of a field before and after a mutation.
+// A nil value means that the field did not exist.
+type AuditFieldChange struct {
+	Before *string `json:"before" xml:"before"`
+	After  *string `json:"after" xml:"after"`
+}
+
+// AuditChanges is the list of changed fields indexed by field name
+type AuditChanges map[string]AuditFieldChange
+
+// AuditEvent is a struct that represents a mutation performed on an entity
+type AuditEvent struct {
+	ID         vo.ID        `json:"id" xml:"id"`
+	ActorID    string       `json:"actor_id" xml:"actor_id"`
+	Action     AuditAction  `json:"action" xml:"action"`
+	TargetType string       `json:"target_type" xml:"target_type"`
+	TargetID   string       `json:"target_id" xml:"target_id"`
+	RequestID  string       `json:"request_id" xml:"request_id"`
+	IP         string       `json:"ip" xml:"ip"`
+	Changes    AuditChanges `json:"changes" xml:"changes"`
+	CreatedAt  time.Time    `json:"created_at" xml:"created_at"`
+}
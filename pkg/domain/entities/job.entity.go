@@ -0,0 +1,15 @@
+package entities
+
+// JobRunStatus is the state of a run of a scheduled job
+type JobRunStatus string
+
+const (
+	// JobRunStatusRunning is used while the job runs (or if the instance stopped during the run)
+	JobRunStatusRunning JobRunStatus = "running"
+
+	// JobRunStatusSucceeded is used when the job has ended without error
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+
+	// JobRunStatusFailed is used when the job has returned an error
+	JobRunStatusFailed JobRunStatus = "failed"
+)
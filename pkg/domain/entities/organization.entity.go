@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	vo "chi_boilerplate/pkg/domain/value_objects"
+)
+
+// OrganizationID is a type for organization ID
+type OrganizationID = vo.ID
+
+// DefaultOrganizationID is the ID of the organization created by the migrations.
+// Users existing before multi-tenancy belong to it and it is used when a public request names no organization.
+const DefaultOrganizationID = "00000000-0000-0000-0000-000000000001"
+
+// Organization is a customer of the application (tenant), it owns users and audit events.
+// An organization never sees the data of another one.
+type Organization struct {
+	ID        OrganizationID `json:"id" xml:"id" form:"id" validate:"required,uuid"`
+	Name      string         `json:"name" xml:"name" form:"name" validate:"required"`
+	Slug      string         `json:"slug" xml:"slug" form:"slug" validate:"required"`
+	CreatedAt time.Time      `json:"created_at" xml:"created_at" form:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" xml:"updated_at" form:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain events published through the outbox
+const (
+	// EventUserCreated is published when a user is created
+	EventUserCreated = "user.created"
+
+	// EventUserUpdated is published when a user is updated (profile, status or avatar)
+	EventUserUpdated = "user.updated"
+
+	// EventUserDeleted is published when a user is deleted
+	EventUserDeleted = "user.deleted"
+)
+
+// OutboxAggregateUser is the aggregate type of user events
+const OutboxAggregateUser = "user"
+
+// OutboxEvent is a domain event saved in the outbox in the same transaction as the mutation which raised it,
+// and published once the transaction is committed.
+// Events of the same aggregate are published in the order they were saved.
+type OutboxEvent struct {
+	ID            string          `json:"id" xml:"id"`
+	AggregateType string          `json:"aggregate_type" xml:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id" xml:"aggregate_id"`
+	Type          string          `json:"type" xml:"type"`
+	Payload       json.RawMessage `json:"payload" xml:"payload"`
+	RequestID     string          `json:"request_id" xml:"request_id"`
+	CreatedAt     time.Time       `json:"created_at" xml:"created_at"`
+}
@@ -9,14 +9,54 @@ import (
 // UserID is a type for user ID
 type UserID = vo.ID
 
+// UserStatus is the status of a user account
+type UserStatus string
+
+const (
+	// UserStatusActive is the status of an account which can be used
+	UserStatusActive UserStatus = "active"
+
+	// UserStatusSuspended is the status of a temporarily blocked account
+	UserStatusSuspended UserStatus = "suspended"
+
+	// UserStatusDisabled is the status of a deactivated account
+	UserStatusDisabled UserStatus = "disabled"
+)
+
+// userStatusTransitions lists the allowed status transitions
+var userStatusTransitions = map[UserStatus][]UserStatus{
+	UserStatusActive:    {UserStatusSuspended, UserStatusDisabled},
+	UserStatusSuspended: {UserStatusActive, UserStatusDisabled},
+	UserStatusDisabled:  {UserStatusActive},
+}
+
+// CanTransitionTo checks if the status can be changed to the target status
+func (s UserStatus) CanTransitionTo(target UserStatus) bool {
+	for _, t := range userStatusTransitions[s] {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive checks if the account can be used
+func (s UserStatus) IsActive() bool {
+	return s == UserStatusActive
+}
+
 // User is a struct that represents a user
 type User struct {
-	ID        UserID      `json:"id" xml:"id" form:"id" validate:"required,uuid"`
-	Email     vo.Email    `json:"email" xml:"email" form:"email" validate:"required"`
-	Password  vo.Password `json:"-" xml:"-" form:"password" validate:"required"`
-	Lastname  string      `json:"lastname" xml:"lastname" form:"lastname" validate:"required"`
-	Firstname string      `json:"firstname" xml:"firstname" form:"firstname" validate:"required"`
-	CreatedAt time.Time   `json:"created_at" xml:"created_at" form:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at" xml:"updated_at" form:"updated_at"`
-	DeletedAt *time.Time  `json:"-" xml:"-" form:"deleted_at"`
+	ID           UserID      `json:"id" xml:"id" form:"id" validate:"required,uuid"`
+	Email        vo.Email    `json:"email" xml:"email" form:"email" validate:"required"`
+	Password     vo.Password `json:"-" xml:"-" form:"password" validate:"required"`
+	Lastname     string      `json:"lastname" xml:"lastname" form:"lastname" validate:"required"`
+	Firstname    string      `json:"firstname" xml:"firstname" form:"firstname" validate:"required"`
+	Avatar       string      `json:"avatar" xml:"avatar" form:"avatar"`
+	Status       UserStatus  `json:"status" xml:"status" form:"status" validate:"required,oneof=active suspended disabled"`
+	StatusReason string      `json:"status_reason" xml:"status_reason" form:"status_reason"`
+	Version      uint64      `json:"-" xml:"-" form:"version"`
+	CreatedAt    time.Time   `json:"created_at" xml:"created_at" form:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at" xml:"updated_at" form:"updated_at"`
+	DeletedAt    *time.Time  `json:"-" xml:"-" form:"deleted_at"`
 }
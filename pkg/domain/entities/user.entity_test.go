@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from   UserStatus
+		to     UserStatus
+		wanted bool
+	}{
+		{from: UserStatusActive, to: UserStatusSuspended, wanted: true},
+		{from: UserStatusActive, to: UserStatusDisabled, wanted: true},
+		{from: UserStatusActive, to: UserStatusActive, wanted: false},
+		{from: UserStatusSuspended, to: UserStatusActive, wanted: true},
+		{from: UserStatusSuspended, to: UserStatusDisabled, wanted: true},
+		{from: UserStatusSuspended, to: UserStatusSuspended, wanted: false},
+		{from: UserStatusDisabled, to: UserStatusActive, wanted: true},
+		{from: UserStatusDisabled, to: UserStatusSuspended, wanted: false},
+		{from: UserStatus("unknown"), to: UserStatusActive, wanted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.wanted, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+)
+
+// AuditRepository is the interface that wraps the basic audit events repository methods.
+type AuditRepository interface {
+	Create(context.Context, requests.AuditEventCreationRepository) error
+	GetAll(context.Context, requests.AuditEventsList) ([]responses.AuditEventsListRepository, error)
+	CountAll(context.Context, requests.AuditEventsList) (int64, error)
+	GetByUser(ctx context.Context, userID string) ([]responses.AuditEventsListRepository, error)
+	Erase(context.Context, requests.AuditEventErasureRepository) error
+
+	// DeleteBefore deletes the audit events created before a date and returns their number
+	DeleteBefore(ctx context.Context, before string) (int64, error)
+}
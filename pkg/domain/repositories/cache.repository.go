@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrCacheMiss is the error returned when a key is not in the cache or has expired.
+	ErrCacheMiss = errors.New("cache miss")
+)
+
+// Cache is the interface that wraps the basic key-value cache methods.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error // A ttl of 0 never expires
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNotFound is the error returned by a generic repository when a resource is not found.
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrAlreadyExists is the error returned by a generic repository when a unique value is already used.
+	ErrAlreadyExists = errors.New("resource already exists")
+
+	// ErrVersionMismatch is the error returned by a generic repository when a resource has been modified
+	// since the version expected by an update or a deletion.
+	ErrVersionMismatch = errors.New("resource version mismatch")
+
+	// ErrInvalidSort is the error returned by a list sorted by a field which cannot be sorted.
+	ErrInvalidSort = errors.New("invalid sort field")
+
+	// ErrInvalidFilter is the error returned by a list filtered by a field which cannot be filtered.
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// Repository is the generic interface of the repository of a resource T identified by an ID.
+// It is the building block of the CRUD use cases and handlers of new resources.
+//
+// Update and Delete only write a resource which still has the expected version (0: any version),
+// or return ErrVersionMismatch.
+type Repository[T any, ID comparable] interface {
+	Create(context.Context, T) error
+	GetByID(context.Context, ID) (T, error)
+	GetAll(context.Context, requests.List) ([]T, error)
+	CountAll(context.Context, requests.List) (int64, error)
+	Update(ctx context.Context, id ID, version uint64, entity T) error
+	Delete(ctx context.Context, id ID, version uint64) error
+}
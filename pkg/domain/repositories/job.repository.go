@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+)
+
+// JobRunRepository is the interface that wraps the history of the runs of the scheduled jobs.
+// Jobs are not scoped to an organization.
+type JobRunRepository interface {
+	Create(context.Context, requests.JobRunCreationRepository) error
+	Finish(context.Context, requests.JobRunFinishRepository) error
+
+	// GetAll returns the latest runs first (by scheduled time)
+	GetAll(context.Context, requests.JobRunsList) ([]responses.JobRunRepository, error)
+
+	// DeleteBefore deletes the runs started before a date and returns their number
+	DeleteBefore(ctx context.Context, before string) (int64, error)
+}
+
+// JobLocker is the interface that wraps the locks electing the instance running a job.
+type JobLocker interface {
+	// TryLock acquires the lock of a job without waiting: ok is false if another instance holds it.
+	// An acquired lock must be released with unlock.
+	TryLock(ctx context.Context, job string) (unlock func(), ok bool, err error)
+}
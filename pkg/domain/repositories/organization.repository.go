@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+	"errors"
+)
+
+var (
+	// ErrOrganizationNotFound is the error returned when an organization is not found.
+	ErrOrganizationNotFound = errors.New("organization not found")
+
+	// ErrSlugAlreadyExists is the error returned when the slug is already used by another organization.
+	ErrSlugAlreadyExists = errors.New("slug already exists")
+)
+
+// OrganizationRepository is the interface that wraps the basic organization repository methods.
+// Organizations are the tenants, so this repository is not scoped to the tenant of the context.
+type OrganizationRepository interface {
+	Create(context.Context, requests.OrganizationCreationRepository) error
+	GetByID(context.Context, requests.OrganizationByID) (responses.OrganizationRepository, error)
+	GetBySlug(context.Context, requests.OrganizationBySlug) (responses.OrganizationRepository, error)
+	GetAll(context.Context) ([]responses.OrganizationRepository, error)
+}
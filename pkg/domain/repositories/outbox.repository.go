@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+)
+
+// OutboxRepository is the interface that wraps the basic outbox events repository methods.
+type OutboxRepository interface {
+	Create(context.Context, requests.OutboxEventCreationRepository) error
+	GetPending(context.Context, requests.OutboxEventsPendingRepository) ([]responses.OutboxEventRepository, error)
+	MarkPublished(context.Context, requests.OutboxEventPublishedRepository) error
+	MarkFailed(context.Context, requests.OutboxEventFailureRepository) error
+	Erase(context.Context, requests.OutboxEventsErasureRepository) error
+
+	// DeletePublishedBefore deletes the events published before a date and returns their number
+	DeletePublishedBefore(ctx context.Context, before string) (int64, error)
+}
+
+// EventPublisher is the interface that wraps the publication of domain events to a message broker.
+//
+// Publish returns once the broker has acknowledged the event.
+type EventPublisher interface {
+	Publish(context.Context, entities.OutboxEvent) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	// ErrFileNotFound is the error returned when a file does not exist in the storage.
+	ErrFileNotFound = errors.New("file not found")
+)
+
+// Storage is the interface that wraps the basic file storage methods.
+type Storage interface {
+	Put(key string, content io.Reader, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	URL(key string) string
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoTenant is the error returned by a tenant-scoped repository called without organization in the context.
+var ErrNoTenant = errors.New("no organization in context")
+
+// tenantKey is the context key of the organization ID
+type tenantKey struct{}
+
+// WithTenant returns a context scoping the repositories to an organization.
+//
+// Tenant-scoped repositories (users, audit events) only read and write the rows of the organization of the context,
+// and return ErrNoTenant if there is none, so that a missing scope never exposes the data of all organizations.
+func WithTenant(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, organizationID)
+}
+
+// Tenant returns the organization ID of the context or ErrNoTenant if there is none.
+func Tenant(ctx context.Context) (string, error) {
+	id, _ := ctx.Value(tenantKey{}).(string)
+	if id == "" {
+		return "", ErrNoTenant
+	}
+
+	return id, nil
+}
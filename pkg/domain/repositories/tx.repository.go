@@ -0,0 +1,14 @@
+package repositories
+
+import "context"
+
+// TxManager is the interface that wraps the transaction management.
+//
+// WithinTx runs fn in a transaction: it is committed if fn returns nil and rolled back otherwise.
+// Repositories called with the context given to fn take part in the transaction.
+//
+// WithinReadTx runs fn in a read-only transaction, which may read from a replica.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinReadTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -3,21 +3,32 @@ package repositories
 import (
 	"chi_boilerplate/pkg/domain/requests"
 	"chi_boilerplate/pkg/domain/responses"
+	"context"
 	"errors"
 )
 
 var (
 	// ErrUserNotFound is the error returned when a user is not found.
 	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUserVersionMismatch is the error returned when a user has been modified since it was read.
+	ErrUserVersionMismatch = errors.New("user version mismatch")
+
+	// ErrEmailAlreadyExists is the error returned when the email is already used by another user.
+	ErrEmailAlreadyExists = errors.New("email already exists")
 )
 
 // UserRepository is the interface that wraps the basic user repository methods.
 type UserRepository interface {
-	Create(requests.UserCreationRepository) error
-	GetByID(requests.UserByID) (responses.UserByIdRepository, error)
-	GetAll(requests.UsersList) ([]responses.UsersListRepository, error)
-	CountAll() (int64, error)
-	Delete(requests.UserDelete) error
-	Update(requests.UserUpdateRepository) error
-	GetByEmail(requests.GetByEmail) (responses.GetByEmail, error)
+	Create(context.Context, requests.UserCreationRepository) error
+	GetByID(context.Context, requests.UserByID) (responses.UserByIdRepository, error)
+	GetAll(context.Context, requests.UsersList) ([]responses.UsersListRepository, error)
+	CountAll(context.Context, requests.UsersList) (int64, error)
+	Delete(context.Context, requests.UserDelete) error
+	Update(context.Context, requests.UserUpdateRepository) error
+	UpdateStatus(context.Context, requests.UserStatusUpdateRepository) error
+	UpdateAvatar(context.Context, requests.UserAvatarUpdateRepository) error
+	GetByEmail(context.Context, requests.GetByEmail) (responses.GetByEmail, error)
+	GetErasable(context.Context, requests.UsersErasableRepository) ([]responses.UserErasableRepository, error)
+	Erase(context.Context, requests.UserErasureRepository) error
 }
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"context"
+)
+
+// UserSearchRepository is the interface that wraps the full-text search of the users.
+// Users are searched in the organization of the context by the words of their names and email,
+// a word of the query matches the words of a user starting with it.
+type UserSearchRepository interface {
+	Search(context.Context, requests.UserSearchRepository) ([]responses.UserSearchHit, error)
+}
@@ -0,0 +1,41 @@
+package requests
+
+// Origin represents who performed a request and from where (used by the audit trail)
+type Origin struct {
+	ActorID   string
+	RequestID string
+	IP        string
+}
+
+// AuditEventCreationRepository request to create an audit event
+type AuditEventCreationRepository struct {
+	ID         string
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	IP         string
+	Changes    string
+	CreatedAt  string
+}
+
+// AuditEventsList request
+type AuditEventsList struct {
+	Page       string `query:"p"`
+	Limit      string `query:"l"`
+	Sorts      string `query:"s"`
+	ActorID    string `query:"actor_id"`
+	Action     string `query:"action" validate:"omitempty,oneof=create update delete erase"`
+	TargetType string `query:"target_type"`
+	TargetID   string `query:"target_id"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// AuditEventErasureRepository request to replace the personal data of an audit event
+type AuditEventErasureRepository struct {
+	ID      string
+	IP      string
+	Changes string
+}
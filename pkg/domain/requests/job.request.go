@@ -0,0 +1,25 @@
+package requests
+
+// JobRunCreationRepository request to record the start of a job run
+type JobRunCreationRepository struct {
+	ID          string
+	Job         string
+	Instance    string
+	ScheduledAt string
+	StartedAt   string
+}
+
+// JobRunFinishRepository request to record the end of a job run
+type JobRunFinishRepository struct {
+	ID         string
+	Status     string
+	Result     string
+	Error      string
+	FinishedAt string
+}
+
+// JobRunsList request to get the latest runs, of a job if Job is not empty
+type JobRunsList struct {
+	Job   string
+	Limit int
+}
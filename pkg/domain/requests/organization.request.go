@@ -0,0 +1,26 @@
+package requests
+
+// OrganizationCreation request to create an organization
+type OrganizationCreation struct {
+	Name string `json:"name" xml:"name" form:"name" validate:"required,max=127"`
+	Slug string `json:"slug" xml:"slug" form:"slug" validate:"required,max=63"`
+}
+
+// OrganizationCreationRepository request to create an organization
+type OrganizationCreationRepository struct {
+	ID        string
+	Name      string
+	Slug      string
+	CreatedAt string
+	UpdatedAt string
+}
+
+// OrganizationByID request
+type OrganizationByID struct {
+	ID string `json:"id" xml:"id" form:"id" validate:"required,uuid"`
+}
+
+// OrganizationBySlug request
+type OrganizationBySlug struct {
+	Slug string `json:"slug" xml:"slug" form:"slug" validate:"required"`
+}
@@ -0,0 +1,38 @@
+package requests
+
+// OutboxEventCreationRepository request to save an event in the outbox
+type OutboxEventCreationRepository struct {
+	ID            string
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       string
+	RequestID     string
+	CreatedAt     string
+}
+
+// OutboxEventPublishedRepository request to mark an outbox event as published
+type OutboxEventPublishedRepository struct {
+	ID          string
+	PublishedAt string
+}
+
+// OutboxEventFailureRepository request to record a failed publication of an outbox event
+type OutboxEventFailureRepository struct {
+	ID            string
+	LastError     string
+	NextAttemptAt string
+}
+
+// OutboxEventsErasureRepository request to replace the payloads of the events of an aggregate
+type OutboxEventsErasureRepository struct {
+	AggregateType string
+	AggregateID   string
+	Payload       string
+}
+
+// OutboxEventsPendingRepository request to list the events ready to be published at a date
+type OutboxEventsPendingRepository struct {
+	Now   string
+	Limit int
+}
@@ -6,3 +6,10 @@ type Pagination struct {
 	Limit string `query:"l"`
 	Sorts string `query:"s"`
 }
+
+// List request of a generic resource.
+// Filters are equality conditions by column, the empty values are ignored.
+type List struct {
+	Pagination
+	Filters map[string]string
+}
@@ -0,0 +1,25 @@
+package requests
+
+// UserDataExport request to export the personal data of a user
+type UserDataExport struct {
+	ID string `json:"id" xml:"id" form:"id" validate:"required,uuid"`
+}
+
+// UsersErasure request to erase the personal data of deleted users
+type UsersErasure struct {
+	RetentionDays int    `validate:"gte=0"` // Number of days a deleted user is kept before erasure
+	Origin        Origin `json:"-" xml:"-" form:"-"`
+}
+
+// UsersErasableRepository request to get the deleted users whose personal data can be erased
+type UsersErasableRepository struct {
+	DeletedBefore string
+	Limit         int
+}
+
+// UserErasureRepository request to anonymise a deleted user
+type UserErasureRepository struct {
+	ID       string
+	Email    string
+	ErasedAt string
+}
@@ -0,0 +1,19 @@
+package requests
+
+// UserSeed user created when seeding the database (fixtures)
+type UserSeed struct {
+	ID        string `json:"id" yaml:"id" validate:"omitempty,uuid"` // Generated if empty
+	Email     string `json:"email" yaml:"email" validate:"required,email"`
+	Password  string `json:"password" yaml:"password" validate:"required,min=8"` // Plain password
+	Lastname  string `json:"lastname" yaml:"lastname" validate:"required"`
+	Firstname string `json:"firstname" yaml:"firstname" validate:"required"`
+	CreatedAt string `json:"created_at" yaml:"created_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // RFC3339 (Default: now)
+	UpdatedAt string `json:"updated_at" yaml:"updated_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // RFC3339 (Default: created_at)
+}
+
+// UsersSeed request to seed the database with users
+type UsersSeed struct {
+	Users        []UserSeed `validate:"dive"`
+	Fake         int        `validate:"gte=0"`           // Number of fake users to generate
+	FakePassword string     `validate:"omitempty,min=8"` // Password of the fake users
+}
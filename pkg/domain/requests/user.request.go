@@ -1,5 +1,10 @@
 package requests
 
+import (
+	"strings"
+	"unicode"
+)
+
 // GetToken request
 type GetToken struct {
 	Email    string `json:"email" xml:"email" form:"email" validate:"required,email"`
@@ -17,6 +22,7 @@ type UserCreation struct {
 	Password  string `json:"password" xml:"password" form:"password" validate:"required,min=8"`
 	Lastname  string `json:"lastname" xml:"lastname" form:"lastname" validate:"required"`
 	Firstname string `json:"firstname" xml:"firstname" form:"firstname" validate:"required"`
+	Origin    Origin `json:"-" xml:"-" form:"-"`
 }
 
 // UserUpdate request to update a user
@@ -26,6 +32,8 @@ type UserUpdate struct {
 	Password  string `json:"password" xml:"password" form:"password" validate:"required,min=8"`
 	Lastname  string `json:"lastname" xml:"lastname" form:"lastname" validate:"required"`
 	Firstname string `json:"firstname" xml:"firstname" form:"firstname" validate:"required"`
+	Version   uint64 `json:"-" xml:"-" form:"-"` // Expected version (0 to skip the check)
+	Origin    Origin `json:"-" xml:"-" form:"-"`
 }
 
 // UserCreationRepository request to create a user
@@ -47,17 +55,87 @@ type UserUpdateRepository struct {
 	Lastname  string
 	Firstname string
 	UpdatedAt string
+	Version   uint64 // Expected version (0 to skip the check)
 }
 
 // UserDelete request
 type UserDelete struct {
-	ID string `json:"id" xml:"id" form:"id" validate:"required,uuid"`
+	ID      string `json:"id" xml:"id" form:"id" validate:"required,uuid"`
+	Version uint64 `json:"-" xml:"-" form:"-"` // Expected version (0 to skip the check)
+	Origin  Origin `json:"-" xml:"-" form:"-"`
 }
 
 // UsersList request
-type UsersList Pagination
+type UsersList struct {
+	Page   string `query:"p"`
+	Limit  string `query:"l"`
+	Sorts  string `query:"s"`
+	Status string `query:"status" validate:"omitempty,oneof=active suspended disabled"`
+}
+
+// List returns the generic list request of the users list
+func (u UsersList) List() List {
+	return List{
+		Pagination: Pagination{Page: u.Page, Limit: u.Limit, Sorts: u.Sorts},
+		Filters:    map[string]string{"status": u.Status},
+	}
+}
+
+// UserSearch request
+type UserSearch struct {
+	Query string `query:"q" validate:"required,max=100"`
+	Limit string `query:"l" validate:"omitempty,number"`
+}
+
+// UserSearchRepository request to search users by name and email
+type UserSearchRepository struct {
+	Query string
+	Limit int
+}
+
+// Terms returns the words of the query in lower case (sequences of letters and digits)
+func (u UserSearchRepository) Terms() []string {
+	return strings.FieldsFunc(strings.ToLower(u.Query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// UserStatusUpdate request to change the status of a user
+type UserStatusUpdate struct {
+	ID     string `json:"id" xml:"id" form:"id" validate:"required,uuid"`
+	Status string `json:"-" xml:"-" form:"-" validate:"required,oneof=active suspended disabled"`
+	Reason string `json:"reason" xml:"reason" form:"reason" validate:"required,max=255"`
+	Origin Origin `json:"-" xml:"-" form:"-"`
+}
+
+// UserStatusUpdateRepository request to change the status of a user
+type UserStatusUpdateRepository struct {
+	ID        string
+	Status    string
+	Reason    string
+	UpdatedAt string
+	Version   uint64 // Expected version (0 to skip the check)
+}
 
 // GetByEmail request
 type GetByEmail struct {
 	Email string `json:"email" xml:"email" form:"email" validate:"required,email"`
 }
+
+// UserAvatarUpdate request to change the avatar of a user
+type UserAvatarUpdate struct {
+	ID        string `json:"id" xml:"id" form:"id" validate:"required,uuid"`
+	Content   []byte `json:"-" xml:"-" form:"-" validate:"required"`
+	MimeType  string `json:"-" xml:"-" form:"-" validate:"required,oneof=image/jpeg image/png"`
+	MaxPixels int    `json:"-" xml:"-" form:"-"` // Maximum width x height of the image (0: DefaultAvatarMaxPixels)
+	Version   uint64 `json:"-" xml:"-" form:"-"` // Expected version (0 to skip the check)
+	Origin    Origin `json:"-" xml:"-" form:"-"`
+}
+
+// UserAvatarUpdateRepository request to change the avatar of a user
+type UserAvatarUpdateRepository struct {
+	ID        string
+	Avatar    string
+	UpdatedAt string
+	Version   uint64 // Expected version (0 to skip the check)
+}
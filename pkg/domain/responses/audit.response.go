@@ -0,0 +1,20 @@
+package responses
+
+import "encoding/json"
+
+// ======== Get all audit events ========
+
+type AuditEventsList Pagination[AuditEventsListRepository]
+
+// AuditEventsListRepository audit event returned by the repository
+type AuditEventsListRepository struct {
+	ID         string          `db:"id" json:"id" xml:"id"`
+	ActorID    string          `db:"actor_id" json:"actor_id" xml:"actor_id"`
+	Action     string          `db:"action" json:"action" xml:"action"`
+	TargetType string          `db:"target_type" json:"target_type" xml:"target_type"`
+	TargetID   string          `db:"target_id" json:"target_id" xml:"target_id"`
+	RequestID  string          `db:"request_id" json:"request_id" xml:"request_id"`
+	IP         string          `db:"ip" json:"ip" xml:"ip"`
+	Changes    json.RawMessage `db:"changes" json:"changes" xml:"changes"`
+	CreatedAt  string          `db:"created_at" json:"created_at" xml:"created_at"`
+}
@@ -0,0 +1,14 @@
+package responses
+
+// JobRunRepository run of a scheduled job returned by the repository
+type JobRunRepository struct {
+	ID          string  `db:"id" json:"id" xml:"id"`
+	Job         string  `db:"job" json:"job" xml:"job"`
+	Instance    string  `db:"instance" json:"instance" xml:"instance"`
+	Status      string  `db:"status" json:"status" xml:"status"`
+	Result      string  `db:"result" json:"result" xml:"result"`
+	Error       string  `db:"error" json:"error" xml:"error"`
+	ScheduledAt string  `db:"scheduled_at" json:"scheduled_at" xml:"scheduled_at"`
+	StartedAt   string  `db:"started_at" json:"started_at" xml:"started_at"`
+	FinishedAt  *string `db:"finished_at" json:"finished_at" xml:"finished_at"`
+}
@@ -0,0 +1,42 @@
+package responses
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"time"
+)
+
+// OrganizationRepository organization returned by the repository
+type OrganizationRepository struct {
+	ID        string `db:"id"`
+	Name      string `db:"name"`
+	Slug      string `db:"slug"`
+	CreatedAt string `db:"created_at"`
+	UpdatedAt string `db:"updated_at"`
+}
+
+// ToOrganization converts OrganizationRepository to Organization
+func (o *OrganizationRepository) ToOrganization() (entities.Organization, error) {
+	id, err := vo.NewIDFrom(o.ID)
+	if err != nil {
+		return entities.Organization{}, err
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, o.CreatedAt)
+	if err != nil {
+		return entities.Organization{}, err
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339, o.UpdatedAt)
+	if err != nil {
+		return entities.Organization{}, err
+	}
+
+	return entities.Organization{
+		ID:        id,
+		Name:      o.Name,
+		Slug:      o.Slug,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}, nil
+}
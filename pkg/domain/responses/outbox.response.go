@@ -0,0 +1,17 @@
+package responses
+
+import "encoding/json"
+
+// OutboxEventRepository outbox event returned by the repository
+type OutboxEventRepository struct {
+	Seq           int64           `db:"seq"`
+	ID            string          `db:"id"`
+	AggregateType string          `db:"aggregate_type"`
+	AggregateID   string          `db:"aggregate_id"`
+	EventType     string          `db:"event_type"`
+	Payload       json.RawMessage `db:"payload"`
+	RequestID     string          `db:"request_id"`
+	Attempts      int             `db:"attempts"`
+	CreatedAt     string          `db:"created_at"`
+	NextAttemptAt string          `db:"next_attempt_at"`
+}
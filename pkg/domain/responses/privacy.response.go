@@ -0,0 +1,30 @@
+package responses
+
+// ======== Personal data export ========
+
+// UserDataExport personal data stored about a user
+type UserDataExport struct {
+	ExportedAt  string                      `json:"exported_at" xml:"exported_at"`
+	Profile     UserHTTP                    `json:"profile" xml:"profile"`
+	AuditEvents []AuditEventsListRepository `json:"audit_events" xml:"audit_events"`
+	Files       []UserDataExportFile        `json:"-" xml:"-"`
+}
+
+// UserDataExportFile file stored about a user (avatar, ...)
+type UserDataExportFile struct {
+	Name    string
+	Content []byte
+}
+
+// ======== Personal data erasure ========
+
+// UsersErasure result of the personal data erasure
+type UsersErasure struct {
+	Erased int `json:"erased" xml:"erased"`
+}
+
+// UserErasableRepository deleted user whose personal data can be erased
+type UserErasableRepository struct {
+	ID     string `db:"id"`
+	Avatar string `db:"avatar"`
+}
@@ -0,0 +1,7 @@
+package responses
+
+// UsersSeed result of the users seeding
+type UsersSeed struct {
+	Created int `json:"created" xml:"created"`
+	Skipped int `json:"skipped" xml:"skipped"` // Users whose email already exists
+}
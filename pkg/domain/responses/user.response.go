@@ -3,6 +3,7 @@ package responses
 import (
 	"chi_boilerplate/pkg/domain/entities"
 	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/utils"
 	"time"
 )
 
@@ -14,12 +15,23 @@ type UsersListPaginated struct {
 
 // UserHTTP HTTP response
 type UserHTTP struct {
-	ID        string `json:"id" xml:"id"`
-	Email     string `json:"email" xml:"email"`
-	Lastname  string `json:"lastname" xml:"lastname"`
-	Firstname string `json:"firstname" xml:"firstname"`
-	CreatedAt string `json:"created_at" xml:"created_at"`
-	UpdatedAt string `json:"updated_at" xml:"updated_at"`
+	ID             string `json:"id" xml:"id"`
+	OrganizationID string `json:"organization_id" xml:"organization_id"`
+	Email          string `json:"email" xml:"email"`
+	Lastname       string `json:"lastname" xml:"lastname"`
+	Firstname      string `json:"firstname" xml:"firstname"`
+	Status         string `json:"status" xml:"status"`
+	StatusReason   string `json:"status_reason,omitempty" xml:"status_reason,omitempty"`
+	AvatarURL      string `json:"avatar_url,omitempty" xml:"avatar_url,omitempty"`
+	ThumbnailURL   string `json:"avatar_thumbnail_url,omitempty" xml:"avatar_thumbnail_url,omitempty"`
+	Version        uint64 `json:"-" xml:"-"`
+	CreatedAt      string `json:"created_at" xml:"created_at"`
+	UpdatedAt      string `json:"updated_at" xml:"updated_at"`
+}
+
+// ETag returns the entity tag of the user
+func (u UserHTTP) ETag() string {
+	return utils.ETagFromVersion(u.Version)
 }
 
 // ======== Get token ========
@@ -76,23 +88,27 @@ func (ulr *UserLoginRepository) ToUser() (entities.User, error) {
 
 // UserCreation response to create a user
 type UserCreation struct {
-	ID        entities.UserID `json:"id" xml:"id"`
-	Email     vo.Email        `json:"email" xml:"email"`
-	Lastname  string          `json:"lastname" xml:"lastname"`
-	Firstname string          `json:"firstname" xml:"firstname"`
-	CreatedAt time.Time       `json:"created_at" xml:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at" xml:"updated_at"`
+	ID             entities.UserID         `json:"id" xml:"id"`
+	OrganizationID entities.OrganizationID `json:"organization_id" xml:"organization_id"`
+	Email          vo.Email                `json:"email" xml:"email"`
+	Lastname       string                  `json:"lastname" xml:"lastname"`
+	Firstname      string                  `json:"firstname" xml:"firstname"`
+	Status         entities.UserStatus     `json:"status" xml:"status"`
+	CreatedAt      time.Time               `json:"created_at" xml:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at" xml:"updated_at"`
 }
 
 // ToUserHTTP converts UserCreation to UserHTTP
 func (u *UserCreation) ToUserHTTP() UserHTTP {
 	return UserHTTP{
-		ID:        u.ID.String(),
-		Email:     u.Email.String(),
-		Lastname:  u.Lastname,
-		Firstname: u.Firstname,
-		CreatedAt: u.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
+		ID:             u.ID.String(),
+		OrganizationID: u.OrganizationID.String(),
+		Email:          u.Email.String(),
+		Lastname:       u.Lastname,
+		Firstname:      u.Firstname,
+		Status:         string(u.Status),
+		CreatedAt:      u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      u.UpdatedAt.Format(time.RFC3339),
 	}
 }
 
@@ -100,33 +116,56 @@ func (u *UserCreation) ToUserHTTP() UserHTTP {
 
 // UserByID request to get a user by ID
 type UserById struct {
-	ID        entities.UserID `json:"id" xml:"id"`
-	Email     vo.Email        `json:"email" xml:"email"`
-	Lastname  string          `json:"lastname" xml:"lastname"`
-	Firstname string          `json:"firstname" xml:"firstname"`
-	CreatedAt time.Time       `json:"created_at" xml:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at" xml:"updated_at"`
+	ID             entities.UserID         `json:"id" xml:"id"`
+	OrganizationID entities.OrganizationID `json:"organization_id" xml:"organization_id"`
+	Email          vo.Email                `json:"email" xml:"email"`
+	Lastname       string                  `json:"lastname" xml:"lastname"`
+	Firstname      string                  `json:"firstname" xml:"firstname"`
+	Status         entities.UserStatus     `json:"status" xml:"status"`
+	StatusReason   string                  `json:"status_reason" xml:"status_reason"`
+	Avatar         string                  `json:"-" xml:"-"`
+	AvatarURL      string                  `json:"avatar_url" xml:"avatar_url"`
+	ThumbnailURL   string                  `json:"avatar_thumbnail_url" xml:"avatar_thumbnail_url"`
+	Version        uint64                  `json:"-" xml:"-"`
+	CreatedAt      time.Time               `json:"created_at" xml:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at" xml:"updated_at"`
 }
 
 // UserByIDRepository request to get a user by ID
 type UserByIdRepository struct {
-	ID        string `db:"id"`
-	Email     string `db:"email"`
-	Lastname  string `db:"lastname"`
-	Firstname string `db:"firstname"`
-	CreatedAt string `db:"created_at"`
-	UpdatedAt string `db:"updated_at"`
+	ID             string `db:"id"`
+	OrganizationID string `db:"organization_id"`
+	Email          string `db:"email"`
+	Lastname       string `db:"lastname"`
+	Firstname      string `db:"firstname"`
+	Status         string `db:"status"`
+	StatusReason   string `db:"status_reason"`
+	Avatar         string `db:"avatar"`
+	Version        uint64 `db:"version"`
+	CreatedAt      string `db:"created_at"`
+	UpdatedAt      string `db:"updated_at"`
+}
+
+// ETag returns the entity tag of the user
+func (u *UserById) ETag() string {
+	return utils.ETagFromVersion(u.Version)
 }
 
 // ToUserHTTP converts UserById to UserHTTP
 func (u *UserById) ToUserHTTP() UserHTTP {
 	return UserHTTP{
-		ID:        u.ID.String(),
-		Email:     u.Email.String(),
-		Lastname:  u.Lastname,
-		Firstname: u.Firstname,
-		CreatedAt: u.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
+		ID:             u.ID.String(),
+		OrganizationID: u.OrganizationID.String(),
+		Email:          u.Email.String(),
+		Lastname:       u.Lastname,
+		Firstname:      u.Firstname,
+		Status:         string(u.Status),
+		StatusReason:   u.StatusReason,
+		AvatarURL:      u.AvatarURL,
+		ThumbnailURL:   u.ThumbnailURL,
+		Version:        u.Version,
+		CreatedAt:      u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      u.UpdatedAt.Format(time.RFC3339),
 	}
 }
 
@@ -140,20 +179,36 @@ type UsersListRepository struct {
 	Email     string `db:"email" json:"email" xml:"email"`
 	Lastname  string `db:"lastname" json:"lastname" xml:"lastname"`
 	Firstname string `db:"firstname" json:"firstname" xml:"firstname"`
+	Status    string `db:"status" json:"status" xml:"status"`
 	CreatedAt string `db:"created_at" json:"created_at" xml:"created_at"`
 	UpdatedAt string `db:"updated_at" json:"updated_at" xml:"updated_at"`
 }
 
+// ======== Search users ========
+
+// UsersSearch response
+type UsersSearch struct {
+	Data []UserSearchHit `json:"data" xml:"data"`
+}
+
+// UserSearchHit user found by a search, the most relevant users have the highest scores
+type UserSearchHit struct {
+	UsersListRepository
+	Score float64 `db:"score" json:"score" xml:"score"`
+}
+
 // ======== Get by email ========
 
 type GetByEmail struct {
-	ID       entities.UserID `json:"id" xml:"id" form:"id"`
-	Password vo.Password     `json:"password" xml:"password" form:"password"`
+	ID       entities.UserID     `json:"id" xml:"id" form:"id"`
+	Password vo.Password         `json:"password" xml:"password" form:"password"`
+	Status   entities.UserStatus `json:"status" xml:"status" form:"status"`
 }
 
 type GetByEmailRepository struct {
 	ID       string `json:"id" xml:"id" form:"id"`
 	Password string `json:"password" xml:"password" form:"password"`
+	Status   string `json:"status" xml:"status" form:"status"`
 }
 
 // ToGetByEmail converts GetByEmailRepository to GetByEmail
@@ -170,5 +225,6 @@ func (e *GetByEmailRepository) ToGetByEmail() (GetByEmail, error) {
 	return GetByEmail{
 		ID:       id,
 		Password: password,
+		Status:   entities.UserStatus(e.Status),
 	}, nil
 }
@@ -11,24 +11,27 @@ import (
 
 func TestUserByIdToUserHTTP(t *testing.T) {
 	id, _ := values_objects.NewIDFrom("f47ac10b-58cc-0372-8562-0b8e853961a1")
+	organizationID, _ := values_objects.NewIDFrom(entities.DefaultOrganizationID)
 	email, _ := values_objects.NewEmail("test@test.com")
 	tt, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
 	user := UserById{
-		ID:        id,
-		Email:     email,
-		Lastname:  "Doe",
-		Firstname: "John",
-		CreatedAt: tt,
-		UpdatedAt: tt,
+		ID:             id,
+		OrganizationID: organizationID,
+		Email:          email,
+		Lastname:       "Doe",
+		Firstname:      "John",
+		CreatedAt:      tt,
+		UpdatedAt:      tt,
 	}
 
 	expected := UserHTTP{
-		ID:        "f47ac10b-58cc-0372-8562-0b8e853961a1",
-		Email:     "test@test.com",
-		Lastname:  "Doe",
-		Firstname: "John",
-		CreatedAt: "2021-01-01T00:00:00Z",
-		UpdatedAt: "2021-01-01T00:00:00Z",
+		ID:             "f47ac10b-58cc-0372-8562-0b8e853961a1",
+		OrganizationID: entities.DefaultOrganizationID,
+		Email:          "test@test.com",
+		Lastname:       "Doe",
+		Firstname:      "John",
+		CreatedAt:      "2021-01-01T00:00:00Z",
+		UpdatedAt:      "2021-01-01T00:00:00Z",
 	}
 
 	assert.Equal(t, user.ToUserHTTP(), expected)
@@ -36,24 +39,27 @@ func TestUserByIdToUserHTTP(t *testing.T) {
 
 func TestUserCreationToUserHTTP(t *testing.T) {
 	id, _ := values_objects.NewIDFrom("f47ac10b-58cc-0372-8562-0b8e853961a1")
+	organizationID, _ := values_objects.NewIDFrom(entities.DefaultOrganizationID)
 	email, _ := values_objects.NewEmail("test@test.com")
 	tt, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
 	user := UserCreation{
-		ID:        id,
-		Email:     email,
-		Lastname:  "Doe",
-		Firstname: "John",
-		CreatedAt: tt,
-		UpdatedAt: tt,
+		ID:             id,
+		OrganizationID: organizationID,
+		Email:          email,
+		Lastname:       "Doe",
+		Firstname:      "John",
+		CreatedAt:      tt,
+		UpdatedAt:      tt,
 	}
 
 	expected := UserHTTP{
-		ID:        "f47ac10b-58cc-0372-8562-0b8e853961a1",
-		Email:     "test@test.com",
-		Lastname:  "Doe",
-		Firstname: "John",
-		CreatedAt: "2021-01-01T00:00:00Z",
-		UpdatedAt: "2021-01-01T00:00:00Z",
+		ID:             "f47ac10b-58cc-0372-8562-0b8e853961a1",
+		OrganizationID: entities.DefaultOrganizationID,
+		Email:          "test@test.com",
+		Lastname:       "Doe",
+		Firstname:      "John",
+		CreatedAt:      "2021-01-01T00:00:00Z",
+		UpdatedAt:      "2021-01-01T00:00:00Z",
 	}
 
 	assert.Equal(t, user.ToUserHTTP(), expected)
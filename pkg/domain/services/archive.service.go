@@ -0,0 +1,29 @@
+package services
+
+import (
+	"archive/zip"
+	"io"
+	"sort"
+)
+
+// WriteZip writes the files (content indexed by name) into a ZIP archive
+func WriteZip(w io.Writer, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(files[name]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
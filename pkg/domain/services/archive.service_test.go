@@ -0,0 +1,30 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteZip(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteZip(&buf, map[string][]byte{
+		"data.json":        []byte(`{"id":"1"}`),
+		"files/avatar.png": []byte("png"),
+	})
+	assert.Nil(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	assert.Len(t, zr.File, 2)
+	assert.Equal(t, "data.json", zr.File[0].Name)
+	assert.Equal(t, "files/avatar.png", zr.File[1].Name)
+
+	f, err := zr.File[0].Open()
+	assert.Nil(t, err)
+	content, _ := io.ReadAll(f)
+	assert.Equal(t, `{"id":"1"}`, string(content))
+}
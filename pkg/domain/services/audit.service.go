@@ -0,0 +1,86 @@
+package services
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/utils"
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Audit records the mutations performed on entities
+type Audit struct {
+	auditRepository repositories.AuditRepository
+}
+
+// NewAudit creates a new Audit service
+func NewAudit(auditRepository repositories.AuditRepository) *Audit {
+	return &Audit{auditRepository}
+}
+
+// Record saves an audit event for a mutation of the target entity
+func (a *Audit) Record(ctx context.Context, origin requests.Origin, action entities.AuditAction, targetType, targetID string, changes entities.AuditChanges) error {
+	if changes == nil {
+		changes = entities.AuditChanges{}
+	}
+	c, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	id := vo.NewID()
+
+	return a.auditRepository.Create(ctx, requests.AuditEventCreationRepository{
+		ID:         id.String(),
+		ActorID:    origin.ActorID,
+		Action:     string(action),
+		TargetType: targetType,
+		TargetID:   targetID,
+		RequestID:  origin.RequestID,
+		IP:         origin.IP,
+		Changes:    string(c),
+		CreatedAt:  time.Now().Format(utils.SqlDateTimeFormat),
+	})
+}
+
+// DiffFields returns the field-level changes between two states of an entity.
+// A nil state means that the entity did not exist (creation) or does not exist anymore (deletion).
+func DiffFields(before, after map[string]string) entities.AuditChanges {
+	keys := make(map[string]struct{}, len(before)+len(after))
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+
+	fields := make([]string, 0, len(keys))
+	for k := range keys {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	changes := make(entities.AuditChanges)
+	for _, f := range fields {
+		b, hasBefore := before[f]
+		a, hasAfter := after[f]
+		if hasBefore && hasAfter && a == b {
+			continue
+		}
+
+		var change entities.AuditFieldChange
+		if hasBefore {
+			change.Before = &b
+		}
+		if hasAfter {
+			change.After = &a
+		}
+		changes[f] = change
+	}
+
+	return changes
+}
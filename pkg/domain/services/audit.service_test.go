@@ -0,0 +1,56 @@
+package services
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFields(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name   string
+		before map[string]string
+		after  map[string]string
+		wanted entities.AuditChanges
+	}{
+		{
+			name:   "Creation",
+			before: nil,
+			after:  map[string]string{"lastname": "Doe"},
+			wanted: entities.AuditChanges{
+				"lastname": {Before: nil, After: ptr("Doe")},
+			},
+		},
+		{
+			name:   "Update",
+			before: map[string]string{"lastname": "Doe", "firstname": "John"},
+			after:  map[string]string{"lastname": "Smith", "firstname": "John"},
+			wanted: entities.AuditChanges{
+				"lastname": {Before: ptr("Doe"), After: ptr("Smith")},
+			},
+		},
+		{
+			name:   "Deletion",
+			before: map[string]string{"lastname": "Doe"},
+			after:  nil,
+			wanted: entities.AuditChanges{
+				"lastname": {Before: ptr("Doe"), After: nil},
+			},
+		},
+		{
+			name:   "No change",
+			before: map[string]string{"lastname": "Doe"},
+			after:  map[string]string{"lastname": "Doe"},
+			wanted: entities.AuditChanges{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wanted, DiffFields(tt.before, tt.after))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+)
+
+var (
+	// ErrUnsupportedImage is returned when an image format is not supported
+	ErrUnsupportedImage = errors.New("unsupported image format")
+
+	// ErrImageTooLarge is returned when the dimensions of an image exceed the maximum number of pixels
+	ErrImageTooLarge = errors.New("image dimensions are too large")
+)
+
+// Image MIME types supported for resizing
+const (
+	MimeTypeJPEG = "image/jpeg"
+	MimeTypePNG  = "image/png"
+)
+
+// DecodeImage decodes a JPEG or PNG image of at most maxPixels pixels (width x height).
+// The dimensions are read from the header before decoding: a small file can declare huge dimensions
+// and allocate gigabytes once decoded (decompression bomb).
+func DecodeImage(content []byte, mimeType string, maxPixels int) (image.Image, error) {
+	var decodeConfig func(io.Reader) (image.Config, error)
+	var decode func(io.Reader) (image.Image, error)
+
+	switch mimeType {
+	case MimeTypeJPEG:
+		decodeConfig, decode = jpeg.DecodeConfig, jpeg.Decode
+	case MimeTypePNG:
+		decodeConfig, decode = png.DecodeConfig, png.Decode
+	default:
+		return nil, ErrUnsupportedImage
+	}
+
+	config, err := decodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if int64(config.Width)*int64(config.Height) > int64(maxPixels) {
+		return nil, ErrImageTooLarge
+	}
+
+	return decode(bytes.NewReader(content))
+}
+
+// EncodeImage encodes an image in JPEG or PNG
+func EncodeImage(img image.Image, mimeType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+
+	switch mimeType {
+	case MimeTypeJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	case MimeTypePNG:
+		err = png.Encode(&buf, img)
+	default:
+		err = ErrUnsupportedImage
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ResizeImage scales an image down to fit in a maxSize x maxSize box while keeping its ratio.
+// Smaller images are not enlarged.
+func ResizeImage(img image.Image, maxSize int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSize && h <= maxSize {
+		return img
+	}
+
+	if w >= h {
+		h = max(1, h*maxSize/w)
+		w = maxSize
+	} else {
+		w = max(1, w*maxSize/h)
+		h = maxSize
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+
+	return dst
+}
+
+// ThumbnailImage crops the center square of an image and scales it to size x size
+func ThumbnailImage(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	src := image.Rect(x, y, x+side, y+side)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Over, nil)
+
+	return dst
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeImage(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		wantedWidth   int
+		wantedHeight  int
+	}{
+		{name: "Landscape", width: 1000, height: 500, wantedWidth: 200, wantedHeight: 100},
+		{name: "Portrait", width: 500, height: 1000, wantedWidth: 100, wantedHeight: 200},
+		{name: "Small image", width: 50, height: 80, wantedWidth: 50, wantedHeight: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := ResizeImage(image.NewRGBA(image.Rect(0, 0, tt.width, tt.height)), 200)
+
+			assert.Equal(t, tt.wantedWidth, img.Bounds().Dx())
+			assert.Equal(t, tt.wantedHeight, img.Bounds().Dy())
+		})
+	}
+}
+
+func TestThumbnailImage(t *testing.T) {
+	img := ThumbnailImage(image.NewRGBA(image.Rect(0, 0, 300, 100)), 64)
+
+	assert.Equal(t, image.Rect(0, 0, 64, 64), img.Bounds())
+}
+
+func TestEncodeDecodeImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 20))
+
+	for _, mimeType := range []string{MimeTypeJPEG, MimeTypePNG} {
+		content, err := EncodeImage(img, mimeType)
+		assert.Nil(t, err)
+
+		decoded, err := DecodeImage(content, mimeType, 200)
+		assert.Nil(t, err)
+		assert.Equal(t, img.Bounds(), decoded.Bounds())
+
+		// The dimensions are checked before decoding
+		_, err = DecodeImage(content, mimeType, 199)
+		assert.Equal(t, ErrImageTooLarge, err)
+	}
+
+	_, err := DecodeImage([]byte("GIF89a"), "image/gif", 200)
+	assert.Equal(t, ErrUnsupportedImage, err)
+}
+
+func TestDecodeImageBomb(t *testing.T) {
+	// A PNG header declaring 100000 x 100000 pixels, without image data
+	content, err := EncodeImage(image.NewGray(image.Rect(0, 0, 1, 1)), MimeTypePNG)
+	assert.Nil(t, err)
+	binary.BigEndian.PutUint32(content[16:], 100000)
+	binary.BigEndian.PutUint32(content[20:], 100000)
+	binary.BigEndian.PutUint32(content[29:], crc32.ChecksumIEEE(content[12:29]))
+
+	_, err = DecodeImage(content, MimeTypePNG, 16_000_000)
+	assert.Equal(t, ErrImageTooLarge, err)
+}
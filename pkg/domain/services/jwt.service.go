@@ -9,14 +9,17 @@ import (
 	"github.com/spf13/viper"
 )
 
+// JWTOrganizationClaim is the JWT claim holding the organization (tenant) of the user
+const JWTOrganizationClaim = "org"
+
 // JWT reprensents a JWT token
 type JWT struct {
 	Value     string
 	ExpiredAt time.Time
 }
 
-// NewJWT creates a new JWT token
-func NewJWT(id entities.UserID, lifetime time.Duration, algo, secret string) (JWT, error) {
+// NewJWT creates a new JWT token for a user of an organization
+func NewJWT(id entities.UserID, organizationID entities.OrganizationID, lifetime time.Duration, algo, secret string) (JWT, error) {
 	// Create token and key
 	token, key, err := utils.GetTokenAndKeyFromAlgo(algo, secret, viper.GetString("JWT_PRIVATE_KEY_PATH"))
 	if err != nil {
@@ -30,6 +33,7 @@ func NewJWT(id entities.UserID, lifetime time.Duration, algo, secret string) (JW
 	// Set claims
 	claims := token.Claims.(jwt.MapClaims)
 	claims["sub"] = id.String()
+	claims[JWTOrganizationClaim] = organizationID.String()
 	claims["exp"] = expiresAt.Unix()
 	claims["iat"] = now.Unix()
 	claims["nbf"] = now.Unix()
@@ -2,10 +2,12 @@ package services
 
 import (
 	"chi_boilerplate/pkg/domain/entities"
+	vo "chi_boilerplate/pkg/domain/value_objects"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -74,6 +76,7 @@ func TestGenerateJWT(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			jwt, err := NewJWT(
 				tt.args.user.ID,
+				entities.OrganizationID{},
 				tt.args.lifetime,
 				tt.args.algo,
 				tt.args.secret,
@@ -91,3 +94,17 @@ func TestGenerateJWT(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateJWTOrganizationClaim(t *testing.T) {
+	organizationID, _ := vo.NewIDFrom(entities.DefaultOrganizationID)
+
+	token, err := NewJWT(vo.NewID(), organizationID, time.Duration(2), "HS512", "my-secret")
+	assert.Nil(t, err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token.Value, claims, func(*jwt.Token) (any, error) {
+		return []byte("my-secret"), nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, entities.DefaultOrganizationID, claims[JWTOrganizationClaim])
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultOutboxBatchSize is the default number of pending events read by each relay
+	DefaultOutboxBatchSize = 100
+
+	// outboxRetryInitialDelay is the delay before the first retry of a failed publication, doubled at each retry
+	outboxRetryInitialDelay = time.Second
+
+	// outboxRetryMaxDelay is the maximum delay between two publication retries
+	outboxRetryMaxDelay = 5 * time.Minute
+
+	// OutboxRelayLock is the name of the lock elected relays hold while publishing a batch
+	OutboxRelayLock = "outbox-relay"
+)
+
+// Outbox saves the domain events raised by mutations.
+// Events must be recorded in the transaction of the mutation so that they are published only if it is committed.
+type Outbox struct {
+	outboxRepository repositories.OutboxRepository
+}
+
+// NewOutbox creates a new Outbox service
+func NewOutbox(outboxRepository repositories.OutboxRepository) *Outbox {
+	return &Outbox{outboxRepository}
+}
+
+// Record saves an event of an aggregate with its JSON payload
+func (o *Outbox) Record(ctx context.Context, origin requests.Origin, eventType, aggregateType, aggregateID string, payload any) error {
+	p, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	id := vo.NewID()
+
+	return o.outboxRepository.Create(ctx, requests.OutboxEventCreationRepository{
+		ID:            id.String(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(p),
+		RequestID:     origin.RequestID,
+		CreatedAt:     time.Now().Format(utils.SqlDateTimeFormat),
+	})
+}
+
+// OutboxRelay publishes the pending events of the outbox.
+//
+// Events are published at least once: an event published but not marked as published
+// (database error, crash) is published again, consumers must deduplicate them by ID.
+// Events of an aggregate are published in order: when the publication of an event fails,
+// the next events of its aggregate wait until it is published (exponential back-off).
+// When several instances run a relay, the one acquiring the OutboxRelayLock publishes each batch.
+type OutboxRelay struct {
+	outboxRepository repositories.OutboxRepository
+	publisher        repositories.EventPublisher
+	locker           repositories.JobLocker
+	batchSize        int
+}
+
+// NewOutboxRelay creates a new OutboxRelay (Default batch size: 100)
+func NewOutboxRelay(outboxRepository repositories.OutboxRepository, publisher repositories.EventPublisher, locker repositories.JobLocker, batchSize int) *OutboxRelay {
+	if batchSize <= 0 {
+		batchSize = DefaultOutboxBatchSize
+	}
+
+	return &OutboxRelay{outboxRepository, publisher, locker, batchSize}
+}
+
+// RelayPending publishes a batch of pending events and returns the number of published events.
+// Nothing is published while another relay holds the lock.
+// Publication failures are recorded for a later retry and returned joined.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	unlock, ok, err := r.locker.TryLock(ctx, OutboxRelayLock)
+	if err != nil || !ok {
+		return 0, err
+	}
+	defer unlock()
+
+	now := time.Now()
+	events, err := r.outboxRepository.GetPending(ctx, requests.OutboxEventsPendingRepository{
+		Now:   now.Format(utils.SqlDateTimeFormat),
+		Limit: r.batchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	blocked := make(map[string]bool) // Aggregates with an event failed in this batch
+	var errs []error
+	for _, e := range events {
+		aggregate := e.AggregateType + "/" + e.AggregateID
+		if blocked[aggregate] {
+			continue
+		}
+
+		event, err := outboxEvent(e)
+		if err != nil {
+			return published, err
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			blocked[aggregate] = true
+			errs = append(errs, fmt.Errorf("event %s: %w", e.ID, err))
+
+			if err := r.outboxRepository.MarkFailed(ctx, requests.OutboxEventFailureRepository{
+				ID:            e.ID,
+				LastError:     err.Error(),
+				NextAttemptAt: now.Add(OutboxRetryDelay(e.Attempts + 1)).Format(utils.SqlDateTimeFormat),
+			}); err != nil {
+				return published, errors.Join(append(errs, err)...)
+			}
+			continue
+		}
+
+		if err := r.outboxRepository.MarkPublished(ctx, requests.OutboxEventPublishedRepository{
+			ID:          e.ID,
+			PublishedAt: time.Now().Format(utils.SqlDateTimeFormat),
+		}); err != nil {
+			return published, errors.Join(append(errs, err)...)
+		}
+		published++
+	}
+
+	return published, errors.Join(errs...)
+}
+
+// Run relays the pending events at each interval until the context is canceled.
+// A full batch is followed by the next one without waiting. onError is called with the relay errors.
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := r.RelayPending(ctx)
+		if err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		if err == nil && published == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// OutboxRetryDelay returns the delay before the next publication of an event which failed attempts times
+func OutboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryInitialDelay
+	for i := 1; i < attempts && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, outboxRetryMaxDelay)
+}
+
+// outboxEvent converts an outbox event returned by the repository
+func outboxEvent(e responses.OutboxEventRepository) (entities.OutboxEvent, error) {
+	createdAt, err := time.Parse(time.RFC3339, e.CreatedAt)
+	if err != nil {
+		return entities.OutboxEvent{}, err
+	}
+
+	return entities.OutboxEvent{
+		ID:            e.ID,
+		AggregateType: e.AggregateType,
+		AggregateID:   e.AggregateID,
+		Type:          e.EventType,
+		Payload:       e.Payload,
+		RequestID:     e.RequestID,
+		CreatedAt:     createdAt,
+	}, nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPublisher records the published events and fails for the aggregates in failing
+type testPublisher struct {
+	published []entities.OutboxEvent
+	failing   map[string]bool
+}
+
+func (p *testPublisher) Publish(ctx context.Context, event entities.OutboxEvent) error {
+	if p.failing[event.AggregateID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+// pendingEvents returns the events of the repository ready to be published now
+func pendingEvents(t *testing.T, repo *memory.OutboxMemoryRepository) []responses.OutboxEventRepository {
+	events, err := repo.GetPending(t.Context(), requests.OutboxEventsPendingRepository{
+		Now:   time.Now().Format(utils.SqlDateTimeFormat),
+		Limit: 10,
+	})
+	assert.Nil(t, err)
+
+	return events
+}
+
+// publishedEvents returns the aggregate IDs and types of the published events
+func (p *testPublisher) publishedEvents() []string {
+	events := make([]string, 0, len(p.published))
+	for _, event := range p.published {
+		events = append(events, event.AggregateID+":"+event.Type)
+	}
+	return events
+}
+
+func TestOutboxRecord(t *testing.T) {
+	repo := memory.NewOutboxMemoryRepository()
+	outbox := NewOutbox(repo)
+
+	err := outbox.Record(t.Context(), requests.Origin{RequestID: "request-1"}, entities.EventUserCreated, entities.OutboxAggregateUser, "1", map[string]string{"id": "1"})
+	assert.Nil(t, err)
+
+	events := pendingEvents(t, repo)
+	assert.Len(t, events, 1)
+	assert.Equal(t, entities.EventUserCreated, events[0].EventType)
+	assert.Equal(t, "1", events[0].AggregateID)
+	assert.Equal(t, "request-1", events[0].RequestID)
+	assert.JSONEq(t, `{"id": "1"}`, string(events[0].Payload))
+}
+
+func TestOutboxRelay(t *testing.T) {
+	repo := memory.NewOutboxMemoryRepository()
+	outbox := NewOutbox(repo)
+	for _, e := range []struct{ aggregateID, eventType string }{
+		{"1", entities.EventUserCreated},
+		{"2", entities.EventUserCreated},
+		{"1", entities.EventUserUpdated},
+		{"2", entities.EventUserDeleted},
+	} {
+		assert.Nil(t, outbox.Record(t.Context(), requests.Origin{}, e.eventType, entities.OutboxAggregateUser, e.aggregateID, json.RawMessage(`{}`)))
+	}
+
+	// Events of the aggregate 2 wait until its first event is published
+	publisher := &testPublisher{failing: map[string]bool{"2": true}}
+	relay := NewOutboxRelay(repo, publisher, db.NewLocalLocker(), 0)
+
+	published, err := relay.RelayPending(t.Context())
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []string{"1:user.created", "1:user.updated"}, publisher.publishedEvents())
+
+	// The events of the aggregate 2 are not pending before the next attempt of the failed one
+	assert.Empty(t, pendingEvents(t, repo))
+	events, err := repo.GetPending(t.Context(), requests.OutboxEventsPendingRepository{
+		Now:   time.Now().Add(time.Hour).Format(utils.SqlDateTimeFormat),
+		Limit: 10,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, 1, events[0].Attempts)
+	assert.Equal(t, 0, events[1].Attempts)
+
+	// The failed event is not retried before its next attempt date
+	publisher.failing = nil
+	published, err = relay.RelayPending(t.Context())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, published)
+
+	assert.Nil(t, repo.MarkFailed(t.Context(), requests.OutboxEventFailureRepository{
+		ID:            events[0].ID,
+		NextAttemptAt: time.Now().Add(-time.Minute).Format(utils.SqlDateTimeFormat),
+	}))
+	published, err = relay.RelayPending(t.Context())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []string{"1:user.created", "1:user.updated", "2:user.created", "2:user.deleted"}, publisher.publishedEvents())
+
+	assert.Empty(t, pendingEvents(t, repo))
+}
+
+func TestOutboxRelayLocked(t *testing.T) {
+	repo := memory.NewOutboxMemoryRepository()
+	assert.Nil(t, NewOutbox(repo).Record(t.Context(), requests.Origin{}, entities.EventUserCreated, entities.OutboxAggregateUser, "1", nil))
+
+	publisher := &testPublisher{}
+	locker := db.NewLocalLocker()
+	relay := NewOutboxRelay(repo, publisher, locker, 0)
+
+	// Another relay is publishing the events
+	unlock, ok, err := locker.TryLock(t.Context(), OutboxRelayLock)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	published, err := relay.RelayPending(t.Context())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, published)
+	assert.Empty(t, publisher.publishedEvents())
+
+	unlock()
+
+	published, err = relay.RelayPending(t.Context())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, published)
+}
+
+func TestOutboxRelayRun(t *testing.T) {
+	repo := memory.NewOutboxMemoryRepository()
+	assert.Nil(t, NewOutbox(repo).Record(t.Context(), requests.Origin{}, entities.EventUserCreated, entities.OutboxAggregateUser, "1", nil))
+
+	publisher := &testPublisher{}
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	NewOutboxRelay(repo, publisher, db.NewLocalLocker(), 10).Run(ctx, 10*time.Millisecond, nil)
+	assert.Equal(t, []string{"1:user.created"}, publisher.publishedEvents())
+}
+
+func TestOutboxRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, OutboxRetryDelay(1))
+	assert.Equal(t, 2*time.Second, OutboxRetryDelay(2))
+	assert.Equal(t, 8*time.Second, OutboxRetryDelay(4))
+	assert.Equal(t, 5*time.Minute, OutboxRetryDelay(100))
+}
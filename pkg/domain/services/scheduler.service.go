@@ -0,0 +1,229 @@
+package services
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// jobRunResultMaxLength is the maximum length of the recorded result of a run
+	jobRunResultMaxLength = 255
+
+	// jobRunErrorMaxLength is the maximum length of the recorded error of a run
+	jobRunErrorMaxLength = 1023
+)
+
+// ErrJobNotFound is returned when running a job which is not scheduled
+var ErrJobNotFound = errors.New("job not found")
+
+// Job is a job run by the Scheduler
+type Job struct {
+	// Name identifies the job in the locks and the history of the runs
+	Name string
+
+	// Schedule is the cron expression of the runs
+	Schedule utils.CronSchedule
+
+	// Local jobs are run by every instance (ex: cleaning of local files), without leader election
+	Local bool
+
+	// Run runs the job and returns a summary of what it has done (ex: "3 user(s) erased")
+	Run func(ctx context.Context) (string, error)
+}
+
+// Scheduler runs jobs at the times of their cron schedule, in the local time zone.
+//
+// When several instances share the database, they elect the one running each scheduled run:
+// the instance acquiring the lock of the job runs it unless the history shows that the run already took place.
+// Runs are recorded in the history with their result or error. Runs missed while no instance was running are skipped,
+// as are the runs due while the previous run of the job is not finished.
+type Scheduler struct {
+	jobRunRepository repositories.JobRunRepository
+	locker           repositories.JobLocker
+	instance         string
+	jobs             []Job
+	now              func() time.Time
+}
+
+// NewScheduler creates a new Scheduler. instance identifies the current instance in the history of the runs.
+func NewScheduler(jobRunRepository repositories.JobRunRepository, locker repositories.JobLocker, instance string) *Scheduler {
+	return &Scheduler{
+		jobRunRepository: jobRunRepository,
+		locker:           locker,
+		instance:         instance,
+		now:              time.Now,
+	}
+}
+
+// Add adds a job to the scheduler. Jobs must be added before Run is called.
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Jobs returns the jobs of the scheduler
+func (s *Scheduler) Jobs() []Job {
+	return s.jobs
+}
+
+// Run runs the jobs at the times of their schedule until the context is canceled.
+// onError is called with the errors of the runs and of their recording.
+func (s *Scheduler) Run(ctx context.Context, onError func(error)) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runSchedule(ctx, job, onError)
+		}()
+	}
+	wg.Wait()
+}
+
+// runSchedule runs a job at the times of its schedule until the context is canceled
+func (s *Scheduler) runSchedule(ctx context.Context, job Job, onError func(error)) {
+	after := s.now()
+	for {
+		next := job.Schedule.Next(after)
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.RunJob(ctx, job.Name, next); err != nil && onError != nil && ctx.Err() == nil {
+			onError(fmt.Errorf("job %s: %w", job.Name, err))
+		}
+
+		// Runs due during this one are skipped
+		after = next
+		if now := s.now(); now.After(after) {
+			after = now
+		}
+	}
+}
+
+// RunJob runs the run of a job scheduled at a time and returns whether it has been run by the current instance.
+// The run is skipped if another instance is running the job or has already run it (see Scheduler).
+// The error of the job is returned after being recorded.
+func (s *Scheduler) RunJob(ctx context.Context, name string, scheduledAt time.Time) (bool, error) {
+	job, ok := s.job(name)
+	if !ok {
+		return false, ErrJobNotFound
+	}
+
+	if !job.Local {
+		unlock, ok, err := s.locker.TryLock(ctx, job.Name)
+		if err != nil || !ok {
+			return false, err
+		}
+		defer unlock()
+
+		done, err := s.alreadyRun(ctx, job.Name, scheduledAt)
+		if err != nil || done {
+			return false, err
+		}
+	}
+
+	return true, s.run(ctx, job, scheduledAt)
+}
+
+// job returns the job of a name
+func (s *Scheduler) job(name string) (Job, bool) {
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+
+	return Job{}, false
+}
+
+// alreadyRun checks if the last run of a job was scheduled at or after a time
+func (s *Scheduler) alreadyRun(ctx context.Context, name string, scheduledAt time.Time) (bool, error) {
+	runs, err := s.jobRunRepository.GetAll(ctx, requests.JobRunsList{Job: name, Limit: 1})
+	if err != nil || len(runs) == 0 {
+		return false, err
+	}
+
+	last, err := time.Parse(time.RFC3339, runs[0].ScheduledAt)
+	if err != nil {
+		return false, err
+	}
+
+	// Dates are stored without time zone: the wall clocks are compared
+	return last.Format(utils.SqlDateTimeFormat) >= scheduledAt.Format(utils.SqlDateTimeFormat), nil
+}
+
+// run runs a job and records the run
+func (s *Scheduler) run(ctx context.Context, job Job, scheduledAt time.Time) error {
+	id := vo.NewID()
+	if err := s.jobRunRepository.Create(ctx, requests.JobRunCreationRepository{
+		ID:          id.String(),
+		Job:         job.Name,
+		Instance:    s.instance,
+		ScheduledAt: scheduledAt.Format(utils.SqlDateTimeFormat),
+		StartedAt:   s.now().Format(utils.SqlDateTimeFormat),
+	}); err != nil {
+		return err
+	}
+
+	result, errRun := runJob(ctx, job)
+
+	finish := requests.JobRunFinishRepository{
+		ID:         id.String(),
+		Status:     string(entities.JobRunStatusSucceeded),
+		Result:     truncate(result, jobRunResultMaxLength),
+		FinishedAt: s.now().Format(utils.SqlDateTimeFormat),
+	}
+	if errRun != nil {
+		finish.Status = string(entities.JobRunStatusFailed)
+		finish.Error = truncate(errRun.Error(), jobRunErrorMaxLength)
+	}
+
+	// The end of the run is recorded even if the context is canceled
+	if err := s.jobRunRepository.Finish(context.WithoutCancel(ctx), finish); err != nil {
+		return errors.Join(errRun, err)
+	}
+
+	return errRun
+}
+
+// runJob runs a job, a panic of the job being returned as an error
+func runJob(ctx context.Context, job Job) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return job.Run(ctx)
+}
+
+// truncate truncates a string to a maximum number of bytes without splitting a UTF-8 character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+
+	return s
+}
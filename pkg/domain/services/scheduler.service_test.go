@@ -0,0 +1,173 @@
+package services
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestScheduler returns a scheduler with a job counting its runs
+func newTestScheduler(t *testing.T, local bool) (*Scheduler, *memory.JobRunMemoryRepository, *db.LocalLocker, *int) {
+	schedule, err := utils.ParseCron("@hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runs := memory.NewJobRunMemoryRepository()
+	locker := db.NewLocalLocker()
+	scheduler := NewScheduler(runs, locker, "host-1")
+
+	count := 0
+	scheduler.Add(Job{
+		Name:     "count",
+		Schedule: schedule,
+		Local:    local,
+		Run: func(ctx context.Context) (string, error) {
+			count++
+			return "counted", nil
+		},
+	})
+
+	return scheduler, runs, locker, &count
+}
+
+// jobRuns returns the runs of the history, the latest first
+func jobRuns(t *testing.T, runs *memory.JobRunMemoryRepository) []responses.JobRunRepository {
+	list, err := runs.GetAll(context.Background(), requests.JobRunsList{Limit: 10})
+	assert.Nil(t, err)
+
+	return list
+}
+
+func TestSchedulerRunJob(t *testing.T) {
+	scheduler, runs, _, count := newTestScheduler(t, false)
+	scheduledAt := time.Date(2025, 3, 3, 10, 0, 0, 0, time.Local)
+
+	ran, err := scheduler.RunJob(t.Context(), "count", scheduledAt)
+	assert.Nil(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, 1, *count)
+
+	list := jobRuns(t, runs)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "count", list[0].Job)
+	assert.Equal(t, "host-1", list[0].Instance)
+	assert.Equal(t, string(entities.JobRunStatusSucceeded), list[0].Status)
+	assert.Equal(t, "counted", list[0].Result)
+	assert.NotNil(t, list[0].FinishedAt)
+
+	// A run already done by an instance is skipped
+	ran, err = scheduler.RunJob(t.Context(), "count", scheduledAt)
+	assert.Nil(t, err)
+	assert.False(t, ran)
+	assert.Equal(t, 1, *count)
+
+	ran, err = scheduler.RunJob(t.Context(), "count", scheduledAt.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, 2, *count)
+	assert.Len(t, jobRuns(t, runs), 2)
+
+	_, err = scheduler.RunJob(t.Context(), "unknown", scheduledAt)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestSchedulerRunJobLocked(t *testing.T) {
+	scheduler, runs, locker, count := newTestScheduler(t, false)
+
+	// Another instance is running the job
+	unlock, ok, err := locker.TryLock(t.Context(), "count")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ran, err := scheduler.RunJob(t.Context(), "count", time.Now())
+	assert.Nil(t, err)
+	assert.False(t, ran)
+	assert.Equal(t, 0, *count)
+	assert.Empty(t, jobRuns(t, runs))
+
+	unlock()
+
+	ran, err = scheduler.RunJob(t.Context(), "count", time.Now())
+	assert.Nil(t, err)
+	assert.True(t, ran)
+}
+
+func TestSchedulerRunLocalJob(t *testing.T) {
+	scheduler, runs, locker, count := newTestScheduler(t, true)
+	scheduledAt := time.Date(2025, 3, 3, 10, 0, 0, 0, time.Local)
+
+	unlock, _, _ := locker.TryLock(t.Context(), "count")
+	defer unlock()
+
+	// Local jobs are run by every instance
+	for range 2 {
+		ran, err := scheduler.RunJob(t.Context(), "count", scheduledAt)
+		assert.Nil(t, err)
+		assert.True(t, ran)
+	}
+	assert.Equal(t, 2, *count)
+	assert.Len(t, jobRuns(t, runs), 2)
+}
+
+func TestSchedulerRunJobFailure(t *testing.T) {
+	runs := memory.NewJobRunMemoryRepository()
+	scheduler := NewScheduler(runs, db.NewLocalLocker(), "host-1")
+	errJob := errors.New(strings.Repeat("é", 600))
+	scheduler.Add(Job{Name: "failing", Run: func(ctx context.Context) (string, error) {
+		return "", errJob
+	}})
+	scheduler.Add(Job{Name: "panicking", Run: func(ctx context.Context) (string, error) {
+		panic("unexpected")
+	}})
+
+	ran, err := scheduler.RunJob(t.Context(), "failing", time.Now())
+	assert.True(t, ran)
+	assert.ErrorIs(t, err, errJob)
+
+	list := jobRuns(t, runs)
+	assert.Equal(t, string(entities.JobRunStatusFailed), list[0].Status)
+	assert.Equal(t, strings.Repeat("é", 511), list[0].Error, "the error is truncated to 1023 bytes")
+
+	ran, err = scheduler.RunJob(t.Context(), "panicking", time.Now())
+	assert.True(t, ran)
+	assert.EqualError(t, err, "panic: unexpected")
+
+	list, err = runs.GetAll(t.Context(), requests.JobRunsList{Job: "panicking", Limit: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, string(entities.JobRunStatusFailed), list[0].Status)
+	assert.Equal(t, "panic: unexpected", list[0].Error)
+}
+
+func TestSchedulerRun(t *testing.T) {
+	scheduler, runs, _, count := newTestScheduler(t, false)
+
+	// The next run is due in 100 ms
+	now := time.Date(2025, 3, 3, 10, 59, 59, 900_000_000, time.Local)
+	scheduler.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx, func(err error) { t.Error(err) })
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(jobRuns(t, runs)) == 1 }, 2*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	list := jobRuns(t, runs)
+	assert.Equal(t, 1, *count)
+	assert.True(t, strings.HasPrefix(list[0].ScheduledAt, "2025-03-03T11:00:00"), list[0].ScheduledAt)
+}
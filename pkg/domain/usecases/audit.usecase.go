@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/utils"
+	"context"
+)
+
+// Audit is an interface for audit use cases
+type Audit interface {
+	GetAll(context.Context, requests.AuditEventsList) (responses.AuditEventsList, *utils.HTTPError)
+}
+
+type auditUseCase struct {
+	auditRepository repositories.AuditRepository
+	txManager       repositories.TxManager
+}
+
+// NewAudit returns a new Audit use case
+func NewAudit(auditRepository repositories.AuditRepository, txManager repositories.TxManager) Audit {
+	return &auditUseCase{auditRepository, txManager}
+}
+
+// GetAll returns all audit events matching the filters with pagination
+func (uc *auditUseCase) GetAll(ctx context.Context, req requests.AuditEventsList) (responses.AuditEventsList, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return responses.AuditEventsList{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	var list responses.AuditEventsList
+	e := withinReadTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		events, err := uc.auditRepository.GetAll(ctx, req)
+		if err != nil {
+			return listError(err, "Error when getting audit events")
+		}
+		list.Data = events
+
+		total, err := uc.auditRepository.CountAll(ctx, req)
+		if err != nil {
+			return listError(err, "Error when getting audit events")
+		}
+		list.Total = total
+
+		return nil
+	})
+	if e != nil {
+		return responses.AuditEventsList{}, e
+	}
+
+	return list, nil
+}
@@ -0,0 +1,239 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+	"strings"
+)
+
+// CRUDReader is a generic interface for the read use cases of a resource T identified by a UUID and listed as items I
+type CRUDReader[T, I any] interface {
+	GetByID(context.Context, string) (T, *utils.HTTPError)
+	GetAll(context.Context, requests.List) (responses.Pagination[I], *utils.HTTPError)
+}
+
+// CRUD is a generic interface for the use cases of a resource T identified by a UUID,
+// created from the requests C and updated from the requests U.
+// Update and Delete are given the version expected by the client (0: any version, see utils.VersionFromETag).
+type CRUD[T, C, U any] interface {
+	CRUDReader[T, T]
+	Create(context.Context, C) (T, *utils.HTTPError)
+	Update(ctx context.Context, id string, version uint64, req U) (T, *utils.HTTPError)
+	Delete(ctx context.Context, id string, version uint64) *utils.HTTPError
+}
+
+// CRUDHooks customize a CRUDUseCase. All hooks are optional.
+type CRUDHooks[T any] struct {
+	// Validate checks a created or updated resource (utils.ValidateStruct by default)
+	Validate func(context.Context, T) utils.ValidatorErrors
+
+	// BeforeCreate completes a valid new resource before it is stored (ID, dates...)
+	BeforeCreate func(context.Context, *T) *utils.HTTPError
+
+	// BeforeUpdate completes a valid updated resource from its current state before it is stored
+	BeforeUpdate func(ctx context.Context, current T, updated *T) *utils.HTTPError
+}
+
+// CRUDUseCase is a generic implementation of the CRUD use cases with a repositories.Repository.
+// An update reads the current resource, and the updated one, in the same transaction.
+type CRUDUseCase[T any] struct {
+	name       string
+	repository repositories.Repository[T, string]
+	txManager  repositories.TxManager
+	hooks      CRUDHooks[T]
+}
+
+// NewCRUDUseCase returns a new CRUDUseCase. name is the name of the resource in the error messages (e.g. "Project").
+func NewCRUDUseCase[T any](
+	name string,
+	repository repositories.Repository[T, string],
+	txManager repositories.TxManager,
+	hooks CRUDHooks[T],
+) *CRUDUseCase[T] {
+	if hooks.Validate == nil {
+		hooks.Validate = func(_ context.Context, resource T) utils.ValidatorErrors {
+			return utils.ValidateStruct(resource)
+		}
+	}
+
+	return &CRUDUseCase[T]{name, repository, txManager, hooks}
+}
+
+// Create resource
+func (uc *CRUDUseCase[T]) Create(ctx context.Context, resource T) (T, *utils.HTTPError) {
+	var zero T
+
+	if reqErrors := uc.hooks.Validate(ctx, resource); reqErrors != nil {
+		return zero, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+	if uc.hooks.BeforeCreate != nil {
+		if e := uc.hooks.BeforeCreate(ctx, &resource); e != nil {
+			return zero, e
+		}
+	}
+
+	if err := uc.repository.Create(ctx, resource); err != nil {
+		return zero, uc.writeError(err, "Error during "+uc.lowerName()+" creation")
+	}
+
+	return resource, nil
+}
+
+// GetByID resource
+func (uc *CRUDUseCase[T]) GetByID(ctx context.Context, id string) (T, *utils.HTTPError) {
+	var zero T
+
+	if e := validateID(id); e != nil {
+		return zero, e
+	}
+
+	resource, err := uc.repository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return zero, uc.errNotFound()
+		}
+		return zero, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting "+uc.lowerName(), err)
+	}
+
+	return resource, nil
+}
+
+// GetAll returns a page of resources with the total number of resources
+func (uc *CRUDUseCase[T]) GetAll(ctx context.Context, req requests.List) (responses.Pagination[T], *utils.HTTPError) {
+	return readPage(ctx, uc.txManager, uc.lowerName()+"s",
+		func(ctx context.Context) ([]T, error) {
+			return uc.repository.GetAll(ctx, req)
+		},
+		func(ctx context.Context) (int64, error) {
+			return uc.repository.CountAll(ctx, req)
+		},
+	)
+}
+
+// Update resource if it has the expected version (0: any version)
+func (uc *CRUDUseCase[T]) Update(ctx context.Context, id string, version uint64, resource T) (T, *utils.HTTPError) {
+	var zero T
+
+	if reqErrors := uc.hooks.Validate(ctx, resource); reqErrors != nil {
+		return zero, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	var after T
+	e := withinTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		current, e := uc.GetByID(ctx, id)
+		if e != nil {
+			return e
+		}
+		if uc.hooks.BeforeUpdate != nil {
+			if e := uc.hooks.BeforeUpdate(ctx, current, &resource); e != nil {
+				return e
+			}
+		}
+
+		if err := uc.repository.Update(ctx, id, version, resource); err != nil {
+			return uc.writeError(err, "Error when updating "+uc.lowerName())
+		}
+
+		after, e = uc.GetByID(ctx, id)
+		return e
+	})
+	if e != nil {
+		return zero, e
+	}
+
+	return after, nil
+}
+
+// Delete resource if it has the expected version (0: any version)
+func (uc *CRUDUseCase[T]) Delete(ctx context.Context, id string, version uint64) *utils.HTTPError {
+	if e := validateID(id); e != nil {
+		return e
+	}
+
+	if err := uc.repository.Delete(ctx, id, version); err != nil {
+		return uc.writeError(err, "Error when deleting "+uc.lowerName())
+	}
+
+	return nil
+}
+
+// writeError converts the error of a write in the repository
+func (uc *CRUDUseCase[T]) writeError(err error, message string) *utils.HTTPError {
+	if errors.Is(err, repositories.ErrNotFound) {
+		return uc.errNotFound()
+	}
+	if errors.Is(err, repositories.ErrAlreadyExists) {
+		return utils.NewHTTPError(utils.StatusConflict, uc.name+" already exists", nil, nil)
+	}
+	if errors.Is(err, repositories.ErrVersionMismatch) {
+		return utils.NewHTTPError(utils.StatusPreconditionFailed, uc.name+" has been modified", nil, nil)
+	}
+
+	return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", message, err)
+}
+
+// errNotFound returns the not found error of the resource
+func (uc *CRUDUseCase[T]) errNotFound() *utils.HTTPError {
+	return utils.NewHTTPError(utils.StatusNotFound, uc.name+" not found", nil, nil)
+}
+
+// lowerName returns the name of the resource in the middle of a sentence
+func (uc *CRUDUseCase[T]) lowerName() string {
+	return strings.ToLower(uc.name)
+}
+
+// readPage reads a page of a list and the total number of items in the same read-only transaction to be consistent.
+// The sorts and filters rejected by the repository are request errors.
+func readPage[T any](
+	ctx context.Context,
+	txManager repositories.TxManager,
+	name string,
+	getAll func(context.Context) ([]T, error),
+	countAll func(context.Context) (int64, error),
+) (responses.Pagination[T], *utils.HTTPError) {
+	var page responses.Pagination[T]
+	e := withinReadTx(ctx, txManager, func(ctx context.Context) *utils.HTTPError {
+		list, err := getAll(ctx)
+		if err != nil {
+			return listError(err, "Error when getting all "+name)
+		}
+		page.Data = list
+
+		total, err := countAll(ctx)
+		if err != nil {
+			return listError(err, "Error when getting all "+name)
+		}
+		page.Total = total
+
+		return nil
+	})
+	if e != nil {
+		return responses.Pagination[T]{}, e
+	}
+
+	return page, nil
+}
+
+// listError converts the error of a list read in the repository
+func listError(err error, message string) *utils.HTTPError {
+	if errors.Is(err, repositories.ErrInvalidSort) || errors.Is(err, repositories.ErrInvalidFilter) {
+		return utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", err.Error(), nil)
+	}
+
+	return utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", message, err)
+}
+
+// validateID checks that the ID of a resource is a UUID
+func validateID(id string) *utils.HTTPError {
+	if _, err := vo.NewIDFrom(id); err != nil {
+		details := utils.ValidatorErrors{{FailedField: "ID", Tag: "uuid", Value: ""}}
+		return utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", details, nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/utils"
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testProject struct {
+	ID        string `json:"id"`
+	Name      string `json:"name" validate:"required"`
+	Version   uint64 `json:"-"`
+	CreatedAt string `json:"created_at"`
+}
+
+// testProjectRepository is a minimal repositories.Repository of projects
+type testProjectRepository map[string]testProject
+
+func (r testProjectRepository) Create(_ context.Context, p testProject) error {
+	for _, existing := range r {
+		if existing.Name == p.Name {
+			return repositories.ErrAlreadyExists
+		}
+	}
+	p.Version = 1
+	r[p.ID] = p
+	return nil
+}
+
+func (r testProjectRepository) GetByID(_ context.Context, id string) (testProject, error) {
+	p, ok := r[id]
+	if !ok {
+		return testProject{}, repositories.ErrNotFound
+	}
+	return p, nil
+}
+
+func (r testProjectRepository) GetAll(_ context.Context, _ requests.List) ([]testProject, error) {
+	list := make([]testProject, 0, len(r))
+	for _, p := range r {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (r testProjectRepository) CountAll(_ context.Context, _ requests.List) (int64, error) {
+	return int64(len(r)), nil
+}
+
+func (r testProjectRepository) Update(_ context.Context, id string, version uint64, p testProject) error {
+	current, ok := r[id]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	if version != 0 && version != current.Version {
+		return repositories.ErrVersionMismatch
+	}
+	p.Version = current.Version + 1
+	r[id] = p
+	return nil
+}
+
+func (r testProjectRepository) Delete(_ context.Context, id string, version uint64) error {
+	current, ok := r[id]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+	if version != 0 && version != current.Version {
+		return repositories.ErrVersionMismatch
+	}
+	delete(r, id)
+	return nil
+}
+
+// newTestCRUDUseCase returns a CRUD use case of projects whose ID and creation date are set by hooks
+func newTestCRUDUseCase() CRUD[testProject, testProject, testProject] {
+	return NewCRUDUseCase("Project", testProjectRepository{}, memory.NewDatabase(), CRUDHooks[testProject]{
+		BeforeCreate: func(_ context.Context, p *testProject) *utils.HTTPError {
+			id := vo.NewID()
+			p.ID = id.String()
+			p.CreatedAt = "2024-01-01T10:00:00Z"
+			return nil
+		},
+		BeforeUpdate: func(_ context.Context, current testProject, updated *testProject) *utils.HTTPError {
+			updated.ID = current.ID
+			updated.CreatedAt = current.CreatedAt
+			return nil
+		},
+	})
+}
+
+func TestCRUDCreate(t *testing.T) {
+	uc := newTestCRUDUseCase()
+
+	p, e := uc.Create(t.Context(), testProject{Name: "Project A"})
+	assert.Nil(t, e)
+	assert.NotEmpty(t, p.ID)
+	assert.Equal(t, "2024-01-01T10:00:00Z", p.CreatedAt)
+
+	_, e = uc.Create(t.Context(), testProject{Name: "Project A"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusConflict, e.Code)
+	assert.Equal(t, "Project already exists", e.Message)
+
+	_, e = uc.Create(t.Context(), testProject{})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+}
+
+func TestCRUDGetByIDAndGetAll(t *testing.T) {
+	uc := newTestCRUDUseCase()
+	b, _ := uc.Create(t.Context(), testProject{Name: "Project B"})
+	_, _ = uc.Create(t.Context(), testProject{Name: "Project A"})
+
+	p, e := uc.GetByID(t.Context(), b.ID)
+	assert.Nil(t, e)
+	assert.Equal(t, "Project B", p.Name)
+
+	_, e = uc.GetByID(t.Context(), "f47ac10b-58cc-0372-8562-0b8e853961c1")
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+	assert.Equal(t, "Project not found", e.Message)
+
+	_, e = uc.GetByID(t.Context(), "1")
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+
+	list, e := uc.GetAll(t.Context(), requests.List{})
+	assert.Nil(t, e)
+	assert.Equal(t, int64(2), list.Total)
+	assert.Equal(t, "Project A", list.Data[0].Name)
+}
+
+func TestCRUDUpdateAndDelete(t *testing.T) {
+	uc := newTestCRUDUseCase()
+	p, _ := uc.Create(t.Context(), testProject{Name: "Project A"})
+
+	updated, e := uc.Update(t.Context(), p.ID, 1, testProject{Name: "Project A2", CreatedAt: "2030-01-01T10:00:00Z"})
+	assert.Nil(t, e)
+	assert.Equal(t, p.ID, updated.ID)
+	assert.Equal(t, "Project A2", updated.Name)
+	assert.Equal(t, p.CreatedAt, updated.CreatedAt)
+	assert.Equal(t, uint64(2), updated.Version)
+
+	_, e = uc.Update(t.Context(), p.ID, 0, testProject{})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+
+	_, e = uc.Update(t.Context(), "f47ac10b-58cc-0372-8562-0b8e853961c1", 0, testProject{Name: "Project B"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+
+	// Outdated version
+	_, e = uc.Update(t.Context(), p.ID, 1, testProject{Name: "Project A3"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusPreconditionFailed, e.Code)
+	assert.Equal(t, "Project has been modified", e.Message)
+
+	e = uc.Delete(t.Context(), p.ID, 1)
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusPreconditionFailed, e.Code)
+
+	assert.Nil(t, uc.Delete(t.Context(), p.ID, 2))
+
+	e = uc.Delete(t.Context(), p.ID, 0)
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+}
@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// organizationSlugRegexp matches the slugs which can be used as subdomains (lowercase letters, digits and hyphens)
+var organizationSlugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Organization is an interface for organization (tenant) use cases
+type Organization interface {
+	Create(context.Context, requests.OrganizationCreation) (entities.Organization, *utils.HTTPError)
+	GetByID(context.Context, requests.OrganizationByID) (entities.Organization, *utils.HTTPError)
+	GetBySlug(context.Context, requests.OrganizationBySlug) (entities.Organization, *utils.HTTPError)
+	GetAll(context.Context) ([]entities.Organization, *utils.HTTPError)
+}
+
+type organizationUseCase struct {
+	organizationRepository repositories.OrganizationRepository
+}
+
+// NewOrganization returns a new Organization use case
+func NewOrganization(organizationRepository repositories.OrganizationRepository) Organization {
+	return &organizationUseCase{organizationRepository}
+}
+
+// Create organization
+func (uc *organizationUseCase) Create(ctx context.Context, req requests.OrganizationCreation) (entities.Organization, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors == nil && !organizationSlugRegexp.MatchString(req.Slug) {
+		reqErrors = utils.ValidatorErrors{{FailedField: "Slug", Tag: "slug", Value: ""}}
+	}
+	if reqErrors != nil {
+		return entities.Organization{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	now := time.Now()
+	id := vo.NewID()
+	err := uc.organizationRepository.Create(ctx, requests.OrganizationCreationRepository{
+		ID:        id.String(),
+		Name:      req.Name,
+		Slug:      req.Slug,
+		CreatedAt: now.Format(utils.SqlDateTimeFormat),
+		UpdatedAt: now.Format(utils.SqlDateTimeFormat),
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrSlugAlreadyExists) {
+			details := utils.ValidatorErrors{{FailedField: "Slug", Tag: "unique", Value: ""}}
+			return entities.Organization{}, utils.NewHTTPError(utils.StatusConflict, "Slug already exists", details, nil)
+		}
+		return entities.Organization{}, utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error during organization creation", err)
+	}
+
+	return entities.Organization{
+		ID:        id,
+		Name:      req.Name,
+		Slug:      req.Slug,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// GetByID organization
+func (uc *organizationUseCase) GetByID(ctx context.Context, req requests.OrganizationByID) (entities.Organization, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return entities.Organization{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	organization, err := uc.organizationRepository.GetByID(ctx, req)
+
+	return toOrganization(organization, err)
+}
+
+// GetBySlug organization
+func (uc *organizationUseCase) GetBySlug(ctx context.Context, req requests.OrganizationBySlug) (entities.Organization, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return entities.Organization{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	organization, err := uc.organizationRepository.GetBySlug(ctx, req)
+
+	return toOrganization(organization, err)
+}
+
+// GetAll returns all organizations
+func (uc *organizationUseCase) GetAll(ctx context.Context) ([]entities.Organization, *utils.HTTPError) {
+	list, err := uc.organizationRepository.GetAll(ctx)
+	if err != nil {
+		return nil, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting organizations", err)
+	}
+
+	organizations := make([]entities.Organization, 0, len(list))
+	for _, o := range list {
+		organization, e := toOrganization(o, nil)
+		if e != nil {
+			return nil, e
+		}
+		organizations = append(organizations, organization)
+	}
+
+	return organizations, nil
+}
+
+// toOrganization converts an organization returned by the repository, or its error
+func toOrganization(o responses.OrganizationRepository, err error) (entities.Organization, *utils.HTTPError) {
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrganizationNotFound) {
+			return entities.Organization{}, utils.NewHTTPError(utils.StatusNotFound, "Organization not found", nil, nil)
+		}
+		return entities.Organization{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting organization", err)
+	}
+
+	organization, err := o.ToOrganization()
+	if err != nil {
+		return entities.Organization{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting organization", err)
+	}
+
+	return organization, nil
+}
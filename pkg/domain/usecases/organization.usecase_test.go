@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationCreate(t *testing.T) {
+	uc := NewOrganization(memory.NewOrganizationMemoryRepository())
+
+	organization, e := uc.Create(t.Context(), requests.OrganizationCreation{Name: "Acme", Slug: "acme"})
+	assert.Nil(t, e)
+	assert.Equal(t, "acme", organization.Slug)
+
+	found, e := uc.GetBySlug(t.Context(), requests.OrganizationBySlug{Slug: "acme"})
+	assert.Nil(t, e)
+	assert.Equal(t, organization.ID, found.ID)
+
+	_, e = uc.Create(t.Context(), requests.OrganizationCreation{Name: "Acme 2", Slug: "acme"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusConflict, e.Code)
+
+	_, e = uc.Create(t.Context(), requests.OrganizationCreation{Name: "Acme", Slug: "Not a slug"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+
+	list, e := uc.GetAll(t.Context())
+	assert.Nil(t, e)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "acme", list[0].Slug)
+	assert.Equal(t, "default", list[1].Slug)
+}
+
+func TestOrganizationGetByID(t *testing.T) {
+	uc := NewOrganization(memory.NewOrganizationMemoryRepository())
+
+	organization, e := uc.GetByID(t.Context(), requests.OrganizationByID{ID: entities.DefaultOrganizationID})
+	assert.Nil(t, e)
+	assert.Equal(t, "default", organization.Slug)
+
+	_, e = uc.GetByID(t.Context(), requests.OrganizationByID{ID: "f47ac10b-58cc-0372-8562-0b8e853961c1"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+
+	_, e = uc.GetByID(t.Context(), requests.OrganizationByID{ID: "invalid"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+}
@@ -0,0 +1,273 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+// ErasedEmailDomain is the domain of the email given to erased users.
+// The email stays unique (users.email index) but does not identify anyone.
+const ErasedEmailDomain = "erased.invalid"
+
+// ErasedValue replaces the personal data of the erased users in the audit trail
+const ErasedValue = "[erased]"
+
+// erasureBatchSize is the number of users erased per query
+const erasureBatchSize = 100
+
+// userPersonalAuditFields are the fields of the user audit changes holding personal data
+var userPersonalAuditFields = []string{"email", "lastname", "firstname", "status_reason"}
+
+// Privacy is an interface for personal data use cases (GDPR)
+type Privacy interface {
+	ExportData(context.Context, requests.UserDataExport) (responses.UserDataExport, *utils.HTTPError)
+	EraseDeleted(context.Context, requests.UsersErasure) (responses.UsersErasure, *utils.HTTPError)
+}
+
+type privacyUseCase struct {
+	userUseCase      User
+	userRepository   repositories.UserRepository
+	auditRepository  repositories.AuditRepository
+	outboxRepository repositories.OutboxRepository
+	audit            *services.Audit
+	storage          repositories.Storage
+	txManager        repositories.TxManager
+}
+
+// NewPrivacy returns a new Privacy use case
+func NewPrivacy(
+	userUseCase User,
+	userRepository repositories.UserRepository,
+	auditRepository repositories.AuditRepository,
+	outboxRepository repositories.OutboxRepository,
+	audit *services.Audit,
+	storage repositories.Storage,
+	txManager repositories.TxManager,
+) Privacy {
+	return &privacyUseCase{userUseCase, userRepository, auditRepository, outboxRepository, audit, storage, txManager}
+}
+
+// ExportData returns everything stored about a user: profile, audit events and files
+func (uc *privacyUseCase) ExportData(ctx context.Context, req requests.UserDataExport) (responses.UserDataExport, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return responses.UserDataExport{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	var user responses.UserById
+	var events []responses.AuditEventsListRepository
+	e := withinTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		var e *utils.HTTPError
+		user, e = uc.userUseCase.GetByID(ctx, requests.UserByID{ID: req.ID})
+		if e != nil {
+			return e
+		}
+
+		var err error
+		events, err = uc.auditRepository.GetByUser(ctx, req.ID)
+		if err != nil {
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting audit events", err)
+		}
+
+		return nil
+	})
+	if e != nil {
+		return responses.UserDataExport{}, e
+	}
+
+	export := responses.UserDataExport{
+		ExportedAt:  time.Now().UTC().Format(time.RFC3339),
+		Profile:     user.ToUserHTTP(),
+		AuditEvents: events,
+	}
+
+	if user.Avatar != "" {
+		content, err := uc.readFile(user.Avatar)
+		if err != nil {
+			return responses.UserDataExport{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when reading avatar", err)
+		}
+		if content != nil {
+			export.Files = append(export.Files, responses.UserDataExportFile{Name: path.Base(user.Avatar), Content: content})
+		}
+	}
+
+	return export, nil
+}
+
+// EraseDeleted anonymises the personal data (email, names, password, avatar) of the users
+// deleted for more than the retention period. Rows and audit events are kept, their copies of
+// the personal data are replaced: values of the audit changes, IPs of the users actions and payloads of the user events.
+func (uc *privacyUseCase) EraseDeleted(ctx context.Context, req requests.UsersErasure) (responses.UsersErasure, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return responses.UsersErasure{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	deletedBefore := time.Now().AddDate(0, 0, -req.RetentionDays).Format(utils.SqlDateTimeFormat)
+
+	var result responses.UsersErasure
+	for {
+		users, err := uc.userRepository.GetErasable(ctx, requests.UsersErasableRepository{
+			DeletedBefore: deletedBefore,
+			Limit:         erasureBatchSize,
+		})
+		if err != nil {
+			return result, utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when getting users to erase", err)
+		}
+
+		for _, user := range users {
+			if e := uc.erase(ctx, user, req.Origin); e != nil {
+				return result, e
+			}
+			result.Erased++
+		}
+
+		if len(users) < erasureBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// erase anonymises one deleted user and removes its files
+func (uc *privacyUseCase) erase(ctx context.Context, user responses.UserErasableRepository, origin requests.Origin) *utils.HTTPError {
+	e := withinTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		err := uc.userRepository.Erase(ctx, requests.UserErasureRepository{
+			ID:       user.ID,
+			Email:    fmt.Sprintf("%s@%s", user.ID, ErasedEmailDomain),
+			ErasedAt: time.Now().Format(utils.SqlDateTimeFormat),
+		})
+		if err != nil {
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when erasing user", err)
+		}
+
+		if e := uc.eraseAuditEvents(ctx, user.ID); e != nil {
+			return e
+		}
+
+		payload, err := json.Marshal(erasedUserPayload{ID: user.ID, Erased: true})
+		if err != nil {
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when erasing user events", err)
+		}
+		err = uc.outboxRepository.Erase(ctx, requests.OutboxEventsErasureRepository{
+			AggregateType: entities.OutboxAggregateUser,
+			AggregateID:   user.ID,
+			Payload:       string(payload),
+		})
+		if err != nil {
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when erasing user events", err)
+		}
+
+		// Erased values are not recorded, otherwise the audit trail would keep them
+		if err := uc.audit.Record(ctx, origin, entities.AuditActionErase, entities.AuditTargetUser, user.ID, nil); err != nil {
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when recording audit event", err)
+		}
+
+		return nil
+	})
+	if e != nil {
+		return e
+	}
+
+	// Files are removed once the erasure is committed
+	if user.Avatar != "" {
+		_ = uc.storage.Delete(user.Avatar)
+		_ = uc.storage.Delete(avatarThumbnailKey(user.Avatar))
+	}
+
+	return nil
+}
+
+// erasedUserPayload replaces the payloads of the events of an erased user
+type erasedUserPayload struct {
+	ID     string `json:"id"`
+	Erased bool   `json:"erased"`
+}
+
+// eraseAuditEvents replaces the personal data of a user in its audit events:
+// the IP of the actions of the user and the values of the personal fields changed on the user
+func (uc *privacyUseCase) eraseAuditEvents(ctx context.Context, userID string) *utils.HTTPError {
+	events, err := uc.auditRepository.GetByUser(ctx, userID)
+	if err != nil {
+		return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when getting audit events", err)
+	}
+
+	for _, event := range events {
+		ip := event.IP
+		if event.ActorID == userID {
+			ip = ""
+		}
+
+		changes := string(event.Changes)
+		if event.TargetType == entities.AuditTargetUser && event.TargetID == userID {
+			changes, err = eraseAuditChanges(event.Changes)
+			if err != nil {
+				return utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when erasing audit events", err)
+			}
+		}
+
+		if ip == event.IP && changes == string(event.Changes) {
+			continue
+		}
+
+		err = uc.auditRepository.Erase(ctx, requests.AuditEventErasureRepository{ID: event.ID, IP: ip, Changes: changes})
+		if err != nil {
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when erasing audit events", err)
+		}
+	}
+
+	return nil
+}
+
+// eraseAuditChanges replaces the values of the personal fields of user audit changes by ErasedValue
+func eraseAuditChanges(raw []byte) (string, error) {
+	var changes entities.AuditChanges
+	if err := json.Unmarshal(raw, &changes); err != nil {
+		return "", err
+	}
+	if len(changes) == 0 {
+		return string(raw), nil
+	}
+
+	erased := ErasedValue
+	for _, field := range userPersonalAuditFields {
+		change, ok := changes[field]
+		if !ok {
+			continue
+		}
+		if change.Before != nil {
+			change.Before = &erased
+		}
+		if change.After != nil {
+			change.After = &erased
+		}
+		changes[field] = change
+	}
+
+	b, err := json.Marshal(changes)
+	return string(b), err
+}
+
+// readFile returns the content of a stored file or nil if the file does not exist anymore
+func (uc *privacyUseCase) readFile(key string) ([]byte, error) {
+	f, err := uc.storage.Get(key)
+	if err != nil {
+		if errors.Is(err, repositories.ErrFileNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// FakeUserPassword is the default password of the fake users
+const FakeUserPassword = "password"
+
+var (
+	fakeFirstnames = []string{"Alice", "Bob", "Charlie", "David", "Emma", "Frank", "Grace", "Hugo", "Iris", "Jules"}
+	fakeLastnames  = []string{"Martin", "Bernard", "Dubois", "Thomas", "Robert", "Richard", "Petit", "Durand", "Leroy", "Moreau"}
+)
+
+// Seed is an interface for database seeding use cases
+type Seed interface {
+	Users(context.Context, requests.UsersSeed) (responses.UsersSeed, *utils.HTTPError)
+}
+
+type seedUseCase struct {
+	userRepository repositories.UserRepository
+}
+
+// NewSeed returns a new Seed use case
+func NewSeed(userRepository repositories.UserRepository) Seed {
+	return &seedUseCase{userRepository}
+}
+
+// Users creates the users of the request and the fake users.
+// Seeding is idempotent: users whose email already exists are skipped,
+// so it can be run again after a failure. No audit event is recorded.
+func (uc *seedUseCase) Users(ctx context.Context, req requests.UsersSeed) (responses.UsersSeed, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return responses.UsersSeed{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	users := slices.Concat(req.Users, fakeUsers(req.Fake, req.FakePassword))
+
+	// Hashing is slow, fake users share the same password
+	hashes := make(map[string]string)
+
+	var result responses.UsersSeed
+	for _, user := range users {
+		_, err := uc.userRepository.GetByEmail(ctx, requests.GetByEmail{Email: user.Email})
+		if err == nil {
+			result.Skipped++
+			continue
+		}
+		if !errors.Is(err, repositories.ErrUserNotFound) {
+			return result, utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when getting user by email", err)
+		}
+
+		hashedPassword, ok := hashes[user.Password]
+		if !ok {
+			password, err := vo.NewPassword(user.Password)
+			if err != nil {
+				return result, utils.NewHTTPError(utils.StatusInternalServerError, "Password error", err, nil)
+			}
+			if hashedPassword, err = password.HashUserPassword(); err != nil {
+				return result, utils.NewHTTPError(utils.StatusInternalServerError, "Error when hashing password", err, nil)
+			}
+			hashes[user.Password] = hashedPassword
+		}
+
+		created, err := seedUserCreation(user, hashedPassword)
+		if err != nil {
+			return result, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", err.Error(), nil)
+		}
+
+		if err := uc.userRepository.Create(ctx, created); err != nil {
+			// The email is used by a deleted user
+			if errors.Is(err, repositories.ErrEmailAlreadyExists) {
+				result.Skipped++
+				continue
+			}
+			return result, utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error during user creation", err)
+		}
+		result.Created++
+	}
+
+	return result, nil
+}
+
+// seedUserCreation returns the repository request to create a seeded user
+func seedUserCreation(user requests.UserSeed, hashedPassword string) (requests.UserCreationRepository, error) {
+	id := user.ID
+	if id == "" {
+		userID := vo.NewID()
+		id = userID.String()
+	}
+
+	createdAt := time.Now()
+	if user.CreatedAt != "" {
+		t, err := time.Parse(time.RFC3339, user.CreatedAt)
+		if err != nil {
+			return requests.UserCreationRepository{}, err
+		}
+		createdAt = t
+	}
+
+	updatedAt := createdAt
+	if user.UpdatedAt != "" {
+		t, err := time.Parse(time.RFC3339, user.UpdatedAt)
+		if err != nil {
+			return requests.UserCreationRepository{}, err
+		}
+		updatedAt = t
+	}
+
+	return requests.UserCreationRepository{
+		ID:        id,
+		Email:     user.Email,
+		Password:  hashedPassword,
+		Lastname:  user.Lastname,
+		Firstname: user.Firstname,
+		CreatedAt: createdAt.Format(utils.SqlDateTimeFormat),
+		UpdatedAt: updatedAt.Format(utils.SqlDateTimeFormat),
+	}, nil
+}
+
+// fakeUsers returns n fake users. Emails are numbered so that seeding again skips them.
+func fakeUsers(n int, password string) []requests.UserSeed {
+	if password == "" {
+		password = FakeUserPassword
+	}
+
+	users := make([]requests.UserSeed, 0, n)
+	for i := range n {
+		firstname := fakeFirstnames[i%len(fakeFirstnames)]
+		lastname := fakeLastnames[(i/len(fakeFirstnames))%len(fakeLastnames)]
+
+		users = append(users, requests.UserSeed{
+			Email:     fmt.Sprintf("fake.user.%d@example.com", i+1),
+			Password:  password,
+			Lastname:  lastname,
+			Firstname: firstname,
+		})
+	}
+
+	return users
+}
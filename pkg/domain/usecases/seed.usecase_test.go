@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedUsers(t *testing.T) {
+	database := memory.NewDatabase()
+	uc := NewSeed(database.Users)
+	req := requests.UsersSeed{
+		Users: []requests.UserSeed{{
+			ID:        "f47ac10b-58cc-0372-8562-0b8e853961a1",
+			Email:     "john.doe@test.com",
+			Password:  "00000000",
+			Lastname:  "Doe",
+			Firstname: "John",
+			CreatedAt: "2024-08-19T09:36:18Z",
+		}},
+		Fake: 3,
+	}
+
+	res, e := uc.Users(tenantContext(t), req)
+	assert.Nil(t, e)
+	assert.Equal(t, 4, res.Created)
+	assert.Equal(t, 0, res.Skipped)
+
+	user, err := database.Users.GetByID(tenantContext(t), requests.UserByID{ID: "f47ac10b-58cc-0372-8562-0b8e853961a1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "john.doe@test.com", user.Email)
+	assert.Equal(t, "2024-08-19T09:36:18Z", user.CreatedAt)
+
+	// Passwords are hashed
+	login, err := database.Users.GetByEmail(tenantContext(t), requests.GetByEmail{Email: "fake.user.1@example.com"})
+	assert.Nil(t, err)
+	assert.Nil(t, login.Password.Verify(FakeUserPassword))
+
+	// Seeding is idempotent
+	req.Fake = 5
+	res, e = uc.Users(tenantContext(t), req)
+	assert.Nil(t, e)
+	assert.Equal(t, 2, res.Created)
+	assert.Equal(t, 4, res.Skipped)
+
+	_, e = uc.Users(tenantContext(t), requests.UsersSeed{Users: []requests.UserSeed{{Email: "invalid", Password: "0000"}}})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+
+	_, e = uc.Users(tenantContext(t), requests.UsersSeed{Fake: 1, FakePassword: "short"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+}
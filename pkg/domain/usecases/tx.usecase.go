@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+)
+
+// errRollback is returned to the transaction manager to roll back a transaction after a use case error
+var errRollback = errors.New("rollback")
+
+// withinTx runs fn in a transaction which is rolled back if fn returns an error
+func withinTx(ctx context.Context, txManager repositories.TxManager, fn func(ctx context.Context) *utils.HTTPError) *utils.HTTPError {
+	return runTx(ctx, txManager.WithinTx, fn)
+}
+
+// withinReadTx runs fn in a read-only transaction, which may read from a replica
+func withinReadTx(ctx context.Context, txManager repositories.TxManager, fn func(ctx context.Context) *utils.HTTPError) *utils.HTTPError {
+	return runTx(ctx, txManager.WithinReadTx, fn)
+}
+
+// runTx runs fn with a transaction function of a TxManager and returns the use case error of fn, if any
+func runTx(
+	ctx context.Context,
+	within func(context.Context, func(ctx context.Context) error) error,
+	fn func(ctx context.Context) *utils.HTTPError,
+) *utils.HTTPError {
+	var e *utils.HTTPError
+	err := within(ctx, func(ctx context.Context) error {
+		if e = fn(ctx); e != nil {
+			return errRollback
+		}
+		return nil
+	})
+	if e != nil {
+		return e
+	}
+	if err != nil {
+		return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error during transaction", err)
+	}
+
+	return nil
+}
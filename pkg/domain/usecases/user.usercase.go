@@ -1,13 +1,19 @@
 package usecases
 
 import (
+	"bytes"
+	"chi_boilerplate/pkg/domain/entities"
 	"chi_boilerplate/pkg/domain/repositories"
 	"chi_boilerplate/pkg/domain/requests"
 	"chi_boilerplate/pkg/domain/responses"
 	"chi_boilerplate/pkg/domain/services"
 	vo "chi_boilerplate/pkg/domain/value_objects"
 	"chi_boilerplate/utils"
+	"context"
 	"errors"
+	"fmt"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,31 +21,87 @@ import (
 
 // User is an interface for user use cases
 type User interface {
-	GetToken(requests.GetToken) (responses.GetToken, *utils.HTTPError)
-	Create(requests.UserCreation) (responses.UserCreation, *utils.HTTPError)
-	GetByID(requests.UserByID) (responses.UserById, *utils.HTTPError)
-	GetAll(requests.UsersList) (responses.UsersList, *utils.HTTPError)
-	Delete(requests.UserDelete) *utils.HTTPError
-	Update(requests.UserUpdate) (responses.UserById, *utils.HTTPError)
+	GetToken(context.Context, requests.GetToken) (responses.GetToken, *utils.HTTPError)
+	Create(context.Context, requests.UserCreation) (responses.UserCreation, *utils.HTTPError)
+	GetByID(context.Context, requests.UserByID) (responses.UserById, *utils.HTTPError)
+	GetAll(context.Context, requests.UsersList) (responses.UsersList, *utils.HTTPError)
+	Delete(context.Context, requests.UserDelete) *utils.HTTPError
+	Update(context.Context, requests.UserUpdate) (responses.UserById, *utils.HTTPError)
+	ChangeStatus(context.Context, requests.UserStatusUpdate) (responses.UserById, *utils.HTTPError)
+	UpdateAvatar(context.Context, requests.UserAvatarUpdate) (responses.UserById, *utils.HTTPError)
 }
 
+const (
+	// AvatarSize is the maximum width and height of an avatar (in pixels)
+	AvatarSize = 512
+
+	// AvatarThumbnailSize is the width and height of an avatar thumbnail (in pixels)
+	AvatarThumbnailSize = 128
+
+	// DefaultAvatarMaxPixels is the default maximum width x height of an uploaded avatar
+	DefaultAvatarMaxPixels = 16_000_000
+)
+
 type userUseCase struct {
 	userRepository repositories.UserRepository
+	audit          *services.Audit
+	outbox         *services.Outbox
+	storage        repositories.Storage
+	txManager      repositories.TxManager
 }
 
 // NewUser returns a new User use case
-func NewUser(userRepository repositories.UserRepository) User {
-	return &userUseCase{userRepository}
+func NewUser(
+	userRepository repositories.UserRepository,
+	audit *services.Audit,
+	outbox *services.Outbox,
+	storage repositories.Storage,
+	txManager repositories.TxManager,
+) User {
+	return &userUseCase{userRepository, audit, outbox, storage, txManager}
+}
+
+// userReader exposes the reads of the users to the generic CRUD handlers
+type userReader struct {
+	users User
+}
+
+// NewUserReader returns the reads of the users as generic CRUD use cases: users are returned as UserHTTP
+// and listed with the status filter.
+func NewUserReader(users User) CRUDReader[responses.UserHTTP, responses.UsersListRepository] {
+	return userReader{users}
+}
+
+// GetByID user
+func (r userReader) GetByID(ctx context.Context, id string) (responses.UserHTTP, *utils.HTTPError) {
+	user, e := r.users.GetByID(ctx, requests.UserByID{ID: id})
+	if e != nil {
+		return responses.UserHTTP{}, e
+	}
+
+	return user.ToUserHTTP(), nil
+}
+
+// GetAll users
+func (r userReader) GetAll(ctx context.Context, req requests.List) (responses.Pagination[responses.UsersListRepository], *utils.HTTPError) {
+	list, e := r.users.GetAll(ctx, requests.UsersList{
+		Page:   req.Page,
+		Limit:  req.Limit,
+		Sorts:  req.Sorts,
+		Status: req.Filters["status"],
+	})
+
+	return responses.Pagination[responses.UsersListRepository](list), e
 }
 
 // GetToken user
-func (uc *userUseCase) GetToken(req requests.GetToken) (responses.GetToken, *utils.HTTPError) {
+func (uc *userUseCase) GetToken(ctx context.Context, req requests.GetToken) (responses.GetToken, *utils.HTTPError) {
 	getTokenErrors := utils.ValidateStruct(req)
 	if getTokenErrors != nil {
 		return responses.GetToken{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", getTokenErrors, nil)
 	}
 
-	loginResponse, err := uc.userRepository.GetByEmail(requests.GetByEmail{Email: req.Email})
+	loginResponse, err := uc.userRepository.GetByEmail(ctx, requests.GetByEmail{Email: req.Email})
 	if err != nil {
 		var e *utils.HTTPError
 		if errors.Is(err, repositories.ErrUserNotFound) {
@@ -54,9 +116,20 @@ func (uc *userUseCase) GetToken(req requests.GetToken) (responses.GetToken, *uti
 		return responses.GetToken{}, utils.NewHTTPError(utils.StatusUnauthorized, "Unauthorized", nil, nil)
 	}
 
+	if !loginResponse.Status.IsActive() {
+		return responses.GetToken{}, utils.NewHTTPError(utils.StatusForbidden, "Account is not active", loginResponse.Status, nil)
+	}
+
+	// The token is only valid in the organization of the user
+	organizationID, e := tenantID(ctx)
+	if e != nil {
+		return responses.GetToken{}, e
+	}
+
 	// Create token
 	jwt, err := services.NewJWT(
 		loginResponse.ID,
+		organizationID,
 		viper.GetDuration("JWT_LIFETIME"),
 		viper.GetString("JWT_ALGO"),
 		viper.GetString("JWT_SECRET"))
@@ -71,12 +144,17 @@ func (uc *userUseCase) GetToken(req requests.GetToken) (responses.GetToken, *uti
 }
 
 // Create user
-func (uc *userUseCase) Create(req requests.UserCreation) (responses.UserCreation, *utils.HTTPError) {
+func (uc *userUseCase) Create(ctx context.Context, req requests.UserCreation) (responses.UserCreation, *utils.HTTPError) {
 	creationErrors := utils.ValidateStruct(req)
 	if creationErrors != nil {
 		return responses.UserCreation{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", creationErrors, nil)
 	}
 
+	organizationID, e := tenantID(ctx)
+	if e != nil {
+		return responses.UserCreation{}, e
+	}
+
 	now := time.Now()
 	userID := vo.NewID()
 	password, err := vo.NewPassword(req.Password)
@@ -96,27 +174,45 @@ func (uc *userUseCase) Create(req requests.UserCreation) (responses.UserCreation
 		CreatedAt: now.Format(utils.SqlDateTimeFormat),
 		UpdatedAt: now.Format(utils.SqlDateTimeFormat),
 	}
-
-	if err := uc.userRepository.Create(user); err != nil {
-		return responses.UserCreation{}, utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error during user creation", err)
-	}
 	email, err := vo.NewEmail(user.Email)
 	if err != nil {
 		return responses.UserCreation{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting user ", err)
 	}
+	created := responses.UserCreation{
+		ID:             userID,
+		OrganizationID: organizationID,
+		Email:          email,
+		Lastname:       user.Lastname,
+		Firstname:      user.Firstname,
+		Status:         entities.UserStatusActive,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
 
-	return responses.UserCreation{
-		ID:        userID,
-		Email:     email,
-		Lastname:  user.Lastname,
-		Firstname: user.Firstname,
-		CreatedAt: now,
-		UpdatedAt: now,
-	}, nil
+	e = withinTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		if err := uc.userRepository.Create(ctx, user); err != nil {
+			if errors.Is(err, repositories.ErrEmailAlreadyExists) {
+				return errEmailAlreadyExists()
+			}
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error during user creation", err)
+		}
+
+		changes := services.DiffFields(nil, userAuditFields(user.Email, user.Lastname, user.Firstname, entities.UserStatusActive, ""))
+		if err := uc.audit.Record(ctx, req.Origin, entities.AuditActionCreate, entities.AuditTargetUser, user.ID, changes); err != nil {
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when recording audit event", err)
+		}
+
+		return uc.recordEvent(ctx, req.Origin, entities.EventUserCreated, user.ID, created)
+	})
+	if e != nil {
+		return responses.UserCreation{}, e
+	}
+
+	return created, nil
 }
 
 // GetByID user
-func (uc *userUseCase) GetByID(req requests.UserByID) (responses.UserById, *utils.HTTPError) {
+func (uc *userUseCase) GetByID(ctx context.Context, req requests.UserByID) (responses.UserById, *utils.HTTPError) {
 	reqErrors := utils.ValidateStruct(req)
 	if reqErrors != nil {
 		return responses.UserById{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
@@ -127,7 +223,7 @@ func (uc *userUseCase) GetByID(req requests.UserByID) (responses.UserById, *util
 		return responses.UserById{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
 	}
 
-	userRepo, err := uc.userRepository.GetByID(requests.UserByID{ID: req.ID})
+	userRepo, err := uc.userRepository.GetByID(ctx, requests.UserByID{ID: req.ID})
 	if err != nil {
 		var e *utils.HTTPError
 		if errors.Is(err, repositories.ErrUserNotFound) {
@@ -138,6 +234,10 @@ func (uc *userUseCase) GetByID(req requests.UserByID) (responses.UserById, *util
 		return responses.UserById{}, e
 	}
 
+	organizationID, err := vo.NewIDFrom(userRepo.OrganizationID)
+	if err != nil {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting user ", err)
+	}
 	email, err := vo.NewEmail(userRepo.Email)
 	if err != nil {
 		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting user ", err)
@@ -151,63 +251,91 @@ func (uc *userUseCase) GetByID(req requests.UserByID) (responses.UserById, *util
 		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting user ", err)
 	}
 	user := responses.UserById{
-		ID:        id,
-		Email:     email,
-		Lastname:  userRepo.Lastname,
-		Firstname: userRepo.Firstname,
-		CreatedAt: createdAt,
-		UpdatedAt: updatedAt,
+		ID:             id,
+		OrganizationID: organizationID,
+		Email:          email,
+		Lastname:       userRepo.Lastname,
+		Firstname:      userRepo.Firstname,
+		Status:         entities.UserStatus(userRepo.Status),
+		StatusReason:   userRepo.StatusReason,
+		Avatar:         userRepo.Avatar,
+		Version:        userRepo.Version,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+	if user.Avatar != "" {
+		user.AvatarURL = uc.storage.URL(user.Avatar)
+		user.ThumbnailURL = uc.storage.URL(avatarThumbnailKey(user.Avatar))
 	}
 
 	return user, nil
 }
 
 // Delete user
-func (uc *userUseCase) Delete(req requests.UserDelete) *utils.HTTPError {
+func (uc *userUseCase) Delete(ctx context.Context, req requests.UserDelete) *utils.HTTPError {
 	reqErrors := utils.ValidateStruct(req)
 	if reqErrors != nil {
 		return utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
 	}
 
-	err := uc.userRepository.Delete(requests.UserDelete{ID: req.ID})
-	if err != nil {
-		var e *utils.HTTPError
-		if errors.Is(err, repositories.ErrUserNotFound) {
-			e = utils.NewHTTPError(utils.StatusNotFound, "User not found", nil, nil)
-		} else {
-			e = utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting user ", err)
+	return withinTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		before, e := uc.GetByID(ctx, requests.UserByID{ID: req.ID})
+		if e != nil {
+			return e
 		}
-		return e
-	}
 
-	return nil
+		err := uc.userRepository.Delete(ctx, requests.UserDelete{ID: req.ID, Version: req.Version})
+		if err != nil {
+			var e *utils.HTTPError
+			if errors.Is(err, repositories.ErrUserNotFound) {
+				e = utils.NewHTTPError(utils.StatusNotFound, "User not found", nil, nil)
+			} else if errors.Is(err, repositories.ErrUserVersionMismatch) {
+				e = utils.NewHTTPError(utils.StatusPreconditionFailed, "User has been modified", nil, nil)
+			} else {
+				e = utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting user ", err)
+			}
+			return e
+		}
+
+		changes := services.DiffFields(userAuditFields(before.Email.String(), before.Lastname, before.Firstname, before.Status, before.StatusReason), nil)
+		if err := uc.audit.Record(ctx, req.Origin, entities.AuditActionDelete, entities.AuditTargetUser, req.ID, changes); err != nil {
+			return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when recording audit event", err)
+		}
+
+		return uc.recordEvent(ctx, req.Origin, entities.EventUserDeleted, req.ID, before)
+	})
 }
 
 // GetAll returns all users with pagination
-func (uc *userUseCase) GetAll(req requests.UsersList) (responses.UsersList, *utils.HTTPError) {
-	var list responses.UsersList
-	users, err := uc.userRepository.GetAll(req)
-	if err != nil {
-		return responses.UsersList{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting all users", err)
+func (uc *userUseCase) GetAll(ctx context.Context, req requests.UsersList) (responses.UsersList, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return responses.UsersList{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
 	}
-	list.Data = users
 
-	total, err := uc.userRepository.CountAll()
-	if err != nil {
-		return responses.UsersList{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting all users", err)
+	list, e := readPage(ctx, uc.txManager, "users",
+		func(ctx context.Context) ([]responses.UsersListRepository, error) {
+			return uc.userRepository.GetAll(ctx, req)
+		},
+		func(ctx context.Context) (int64, error) {
+			return uc.userRepository.CountAll(ctx, req)
+		},
+	)
+	if e != nil {
+		return responses.UsersList{}, e
 	}
-	list.Total = total
 
-	return list, nil
+	return responses.UsersList(list), nil
 }
 
 // Update user
-func (uc *userUseCase) Update(req requests.UserUpdate) (responses.UserById, *utils.HTTPError) {
+func (uc *userUseCase) Update(ctx context.Context, req requests.UserUpdate) (responses.UserById, *utils.HTTPError) {
 	reqErrors := utils.ValidateStruct(req)
 	if reqErrors != nil {
 		return responses.UserById{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
 	}
 
+	// The password is hashed before the transaction because it is slow
 	password, err := vo.NewPassword(req.Password)
 	if err != nil {
 		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Password error", err, nil)
@@ -217,17 +345,289 @@ func (uc *userUseCase) Update(req requests.UserUpdate) (responses.UserById, *uti
 		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Error when hashing password", err, nil)
 	}
 
-	err = uc.userRepository.Update(requests.UserUpdateRepository{
+	var after responses.UserById
+	e := withinTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		var e *utils.HTTPError
+		after, e = uc.update(ctx, req, hashedPassword)
+		return e
+	})
+	if e != nil {
+		return responses.UserById{}, e
+	}
+
+	return after, nil
+}
+
+// update updates a user with its hashed password and records the changes
+func (uc *userUseCase) update(ctx context.Context, req requests.UserUpdate, hashedPassword string) (responses.UserById, *utils.HTTPError) {
+	before, e := uc.GetByID(ctx, requests.UserByID{ID: req.ID})
+	if e != nil {
+		return responses.UserById{}, e
+	}
+
+	err := uc.userRepository.Update(ctx, requests.UserUpdateRepository{
 		ID:        req.ID,
 		Lastname:  req.Lastname,
 		Firstname: req.Firstname,
 		Email:     req.Email,
 		Password:  hashedPassword,
 		UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+		Version:   req.Version,
 	})
 	if err != nil {
-		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Error when updating user", err, nil)
+		var e *utils.HTTPError
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			e = utils.NewHTTPError(utils.StatusNotFound, "User not found", nil, nil)
+		} else if errors.Is(err, repositories.ErrUserVersionMismatch) {
+			e = utils.NewHTTPError(utils.StatusPreconditionFailed, "User has been modified", nil, nil)
+		} else if errors.Is(err, repositories.ErrEmailAlreadyExists) {
+			e = errEmailAlreadyExists()
+		} else {
+			e = utils.NewHTTPError(utils.StatusInternalServerError, "Error when updating user", err, nil)
+		}
+		return responses.UserById{}, e
+	}
+
+	after, e := uc.GetByID(ctx, requests.UserByID{ID: req.ID})
+	if e != nil {
+		return responses.UserById{}, e
+	}
+
+	if e := uc.recordUpdate(ctx, req.Origin, before, after); e != nil {
+		return responses.UserById{}, e
 	}
 
-	return uc.GetByID(requests.UserByID{ID: req.ID})
+	return after, nil
+}
+
+// ChangeStatus changes the status of a user (suspension, reactivation, deactivation)
+func (uc *userUseCase) ChangeStatus(ctx context.Context, req requests.UserStatusUpdate) (responses.UserById, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	if req.ID == req.Origin.ActorID {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusForbidden, "Cannot change your own status", nil, nil)
+	}
+
+	var after responses.UserById
+	e := withinTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		var e *utils.HTTPError
+		after, e = uc.changeStatus(ctx, req)
+		return e
+	})
+	if e != nil {
+		return responses.UserById{}, e
+	}
+
+	return after, nil
+}
+
+// changeStatus changes the status of a user and records the changes
+func (uc *userUseCase) changeStatus(ctx context.Context, req requests.UserStatusUpdate) (responses.UserById, *utils.HTTPError) {
+	before, e := uc.GetByID(ctx, requests.UserByID{ID: req.ID})
+	if e != nil {
+		return responses.UserById{}, e
+	}
+
+	status := entities.UserStatus(req.Status)
+	if !before.Status.CanTransitionTo(status) {
+		details := map[string]string{"from": string(before.Status), "to": req.Status}
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusConflict, "Invalid status transition", details, nil)
+	}
+
+	err := uc.userRepository.UpdateStatus(ctx, requests.UserStatusUpdateRepository{
+		ID:        req.ID,
+		Status:    req.Status,
+		Reason:    req.Reason,
+		UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+		Version:   before.Version,
+	})
+	if err != nil {
+		var e *utils.HTTPError
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			e = utils.NewHTTPError(utils.StatusNotFound, "User not found", nil, nil)
+		} else if errors.Is(err, repositories.ErrUserVersionMismatch) {
+			e = utils.NewHTTPError(utils.StatusPreconditionFailed, "User has been modified", nil, nil)
+		} else {
+			e = utils.NewHTTPError(utils.StatusInternalServerError, "Error when updating user status", err, nil)
+		}
+		return responses.UserById{}, e
+	}
+
+	after, e := uc.GetByID(ctx, requests.UserByID{ID: req.ID})
+	if e != nil {
+		return responses.UserById{}, e
+	}
+
+	if e := uc.recordUpdate(ctx, req.Origin, before, after); e != nil {
+		return responses.UserById{}, e
+	}
+
+	return after, nil
+}
+
+// UpdateAvatar resizes and stores a new avatar (with its thumbnail) for a user
+func (uc *userUseCase) UpdateAvatar(ctx context.Context, req requests.UserAvatarUpdate) (responses.UserById, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	// The user must exist before processing and storing the image
+	if _, e := uc.GetByID(ctx, requests.UserByID{ID: req.ID}); e != nil {
+		return responses.UserById{}, e
+	}
+
+	maxPixels := req.MaxPixels
+	if maxPixels <= 0 {
+		maxPixels = DefaultAvatarMaxPixels
+	}
+	img, err := services.DecodeImage(req.Content, req.MimeType, maxPixels)
+	if errors.Is(err, services.ErrImageTooLarge) {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusRequestEntityTooLarge, "Image dimensions are too large", nil, nil)
+	}
+	if err != nil {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid image", nil, nil)
+	}
+	avatar, err := services.EncodeImage(services.ResizeImage(img, AvatarSize), req.MimeType)
+	if err != nil {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when resizing avatar", err)
+	}
+	thumbnail, err := services.EncodeImage(services.ThumbnailImage(img, AvatarThumbnailSize), req.MimeType)
+	if err != nil {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when resizing avatar", err)
+	}
+
+	fileID := vo.NewID()
+	key := fmt.Sprintf("avatars/%s/%s.%s", req.ID, fileID.String(), avatarExtension(req.MimeType))
+	if err := uc.storage.Put(key, bytes.NewReader(avatar), req.MimeType); err != nil {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when storing avatar", err)
+	}
+	if err := uc.storage.Put(avatarThumbnailKey(key), bytes.NewReader(thumbnail), req.MimeType); err != nil {
+		return responses.UserById{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when storing avatar", err)
+	}
+
+	var before, after responses.UserById
+	e := withinTx(ctx, uc.txManager, func(ctx context.Context) *utils.HTTPError {
+		var e *utils.HTTPError
+		before, e = uc.GetByID(ctx, requests.UserByID{ID: req.ID})
+		if e != nil {
+			return e
+		}
+
+		err := uc.userRepository.UpdateAvatar(ctx, requests.UserAvatarUpdateRepository{
+			ID:        req.ID,
+			Avatar:    key,
+			UpdatedAt: time.Now().Format(utils.SqlDateTimeFormat),
+			Version:   req.Version,
+		})
+		if err != nil {
+			var e *utils.HTTPError
+			if errors.Is(err, repositories.ErrUserNotFound) {
+				e = utils.NewHTTPError(utils.StatusNotFound, "User not found", nil, nil)
+			} else if errors.Is(err, repositories.ErrUserVersionMismatch) {
+				e = utils.NewHTTPError(utils.StatusPreconditionFailed, "User has been modified", nil, nil)
+			} else {
+				e = utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when updating user avatar", err)
+			}
+			return e
+		}
+
+		after, e = uc.GetByID(ctx, requests.UserByID{ID: req.ID})
+		if e != nil {
+			return e
+		}
+
+		return uc.recordUpdate(ctx, req.Origin, before, after)
+	})
+	if e != nil {
+		// New files are not used
+		_ = uc.storage.Delete(key)
+		_ = uc.storage.Delete(avatarThumbnailKey(key))
+		return responses.UserById{}, e
+	}
+
+	// Previous files are not used anymore
+	if before.Avatar != "" {
+		_ = uc.storage.Delete(before.Avatar)
+		_ = uc.storage.Delete(avatarThumbnailKey(before.Avatar))
+	}
+
+	return after, nil
+}
+
+// recordUpdate records the changes between two states of a user in the audit trail and raises a user.updated event
+func (uc *userUseCase) recordUpdate(ctx context.Context, origin requests.Origin, before, after responses.UserById) *utils.HTTPError {
+	beforeFields := userAuditFields(before.Email.String(), before.Lastname, before.Firstname, before.Status, before.StatusReason)
+	beforeFields["avatar"] = before.Avatar
+	afterFields := userAuditFields(after.Email.String(), after.Lastname, after.Firstname, after.Status, after.StatusReason)
+	afterFields["avatar"] = after.Avatar
+
+	changes := services.DiffFields(beforeFields, afterFields)
+	if err := uc.audit.Record(ctx, origin, entities.AuditActionUpdate, entities.AuditTargetUser, after.ID.String(), changes); err != nil {
+		return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when recording audit event", err)
+	}
+
+	return uc.recordEvent(ctx, origin, entities.EventUserUpdated, after.ID.String(), after)
+}
+
+// recordEvent saves a user event in the outbox, it is published once the transaction is committed
+func (uc *userUseCase) recordEvent(ctx context.Context, origin requests.Origin, eventType, userID string, payload any) *utils.HTTPError {
+	if err := uc.outbox.Record(ctx, origin, eventType, entities.OutboxAggregateUser, userID, payload); err != nil {
+		return utils.NewHTTPError(utils.StatusInternalServerError, "Database error", "Error when recording outbox event", err)
+	}
+
+	return nil
+}
+
+// userAuditFields returns the user fields recorded in the audit trail.
+// The password is never recorded.
+func userAuditFields(email, lastname, firstname string, status entities.UserStatus, statusReason string) map[string]string {
+	return map[string]string{
+		"email":         email,
+		"lastname":      lastname,
+		"firstname":     firstname,
+		"status":        string(status),
+		"status_reason": statusReason,
+	}
+}
+
+// tenantID returns the organization the context is scoped to
+func tenantID(ctx context.Context) (entities.OrganizationID, *utils.HTTPError) {
+	id, err := repositories.Tenant(ctx)
+	if err != nil {
+		return entities.OrganizationID{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting organization", err)
+	}
+
+	organizationID, err := vo.NewIDFrom(id)
+	if err != nil {
+		return entities.OrganizationID{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when getting organization", err)
+	}
+
+	return organizationID, nil
+}
+
+// errEmailAlreadyExists returns the conflict error pointing at the email field
+func errEmailAlreadyExists() *utils.HTTPError {
+	details := utils.ValidatorErrors{
+		{FailedField: "Email", Tag: "unique", Value: ""},
+	}
+
+	return utils.NewHTTPError(utils.StatusConflict, "Email already exists", details, nil)
+}
+
+// avatarThumbnailKey returns the storage key of the thumbnail of an avatar
+func avatarThumbnailKey(key string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + "_thumbnail" + ext
+}
+
+// avatarExtension returns the file extension of an avatar MIME type
+func avatarExtension(mimeType string) string {
+	if mimeType == services.MimeTypePNG {
+		return "png"
+	}
+	return "jpg"
 }
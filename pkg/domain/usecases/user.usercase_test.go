@@ -0,0 +1,324 @@
+package usecases
+
+import (
+	"bytes"
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/adapters/storage"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/utils"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// tenantContext returns the test context scoped to the default organization
+func tenantContext(t *testing.T) context.Context {
+	return repositories.WithTenant(t.Context(), entities.DefaultOrganizationID)
+}
+
+// newTestUserUseCase returns a User use case using in-memory repositories and a temporary local storage
+func newTestUserUseCase(t *testing.T) (User, *memory.Database) {
+	database := memory.NewDatabase()
+	st, err := storage.NewLocalStorage(t.TempDir(), "http://localhost/storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewUser(database.Users, services.NewAudit(database.Audit), services.NewOutbox(database.Outbox), st, database), database
+}
+
+// createTestUser creates a user with the use case
+func createTestUser(t *testing.T, uc User, email string) string {
+	user, e := uc.Create(tenantContext(t), requests.UserCreation{
+		Email:     email,
+		Password:  "00000000",
+		Lastname:  "Doe",
+		Firstname: "John",
+	})
+	if e != nil {
+		t.Fatal(e.Message)
+	}
+
+	return user.ID.String()
+}
+
+// auditActions returns the actions of the audit events of a user
+func auditActions(t *testing.T, database *memory.Database, userID string) []entities.AuditAction {
+	events, err := database.Audit.GetByUser(tenantContext(t), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions := make([]entities.AuditAction, 0, len(events))
+	for _, event := range events {
+		actions = append(actions, entities.AuditAction(event.Action))
+	}
+
+	return actions
+}
+
+// outboxEvents returns the types of the pending outbox events of a user
+func outboxEvents(t *testing.T, database *memory.Database, userID string) []string {
+	events, err := database.Outbox.GetPending(tenantContext(t), requests.OutboxEventsPendingRepository{Now: time.Now().Format(utils.SqlDateTimeFormat), Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		if event.AggregateType == entities.OutboxAggregateUser && event.AggregateID == userID {
+			types = append(types, event.EventType)
+		}
+	}
+
+	return types
+}
+
+func TestUserCreate(t *testing.T) {
+	uc, database := newTestUserUseCase(t)
+
+	user, e := uc.Create(tenantContext(t), requests.UserCreation{
+		Email:     "john.doe@test.com",
+		Password:  "00000000",
+		Lastname:  "Doe",
+		Firstname: "John",
+		Origin:    requests.Origin{RequestID: "request-1"},
+	})
+	assert.Nil(t, e)
+	assert.Equal(t, "john.doe@test.com", user.Email.String())
+	assert.Equal(t, entities.UserStatusActive, user.Status)
+	assert.Equal(t, []entities.AuditAction{entities.AuditActionCreate}, auditActions(t, database, user.ID.String()))
+	assert.Equal(t, []string{entities.EventUserCreated}, outboxEvents(t, database, user.ID.String()))
+
+	_, e = uc.Create(tenantContext(t), requests.UserCreation{
+		Email:     "John.Doe@test.com",
+		Password:  "00000000",
+		Lastname:  "Doe",
+		Firstname: "John",
+	})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusConflict, e.Code)
+
+	_, e = uc.Create(tenantContext(t), requests.UserCreation{Email: "invalid", Password: "0000"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+}
+
+func TestUserGetByID(t *testing.T) {
+	uc, _ := newTestUserUseCase(t)
+	id := createTestUser(t, uc, "john.doe@test.com")
+
+	user, e := uc.GetByID(tenantContext(t), requests.UserByID{ID: id})
+	assert.Nil(t, e)
+	assert.Equal(t, id, user.ID.String())
+	assert.Equal(t, "john.doe@test.com", user.Email.String())
+	assert.Equal(t, uint64(1), user.Version)
+
+	_, e = uc.GetByID(tenantContext(t), requests.UserByID{ID: "f47ac10b-58cc-0372-8562-0b8e853961b3"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+
+	_, e = uc.GetByID(tenantContext(t), requests.UserByID{ID: "invalid"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+}
+
+func TestUserGetToken(t *testing.T) {
+	viper.Set("JWT_ALGO", "HS512")
+	viper.Set("JWT_SECRET", "mySecretForTest")
+	viper.Set("JWT_LIFETIME", 2)
+
+	uc, _ := newTestUserUseCase(t)
+	id := createTestUser(t, uc, "john.doe@test.com")
+
+	token, e := uc.GetToken(tenantContext(t), requests.GetToken{Email: "john.doe@test.com", Password: "00000000"})
+	assert.Nil(t, e)
+	assert.NotEmpty(t, token.AccessToken)
+
+	_, e = uc.GetToken(tenantContext(t), requests.GetToken{Email: "john.doe@test.com", Password: "11111111"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusUnauthorized, e.Code)
+
+	_, e = uc.GetToken(tenantContext(t), requests.GetToken{Email: "unknown@test.com", Password: "00000000"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+
+	_, e = uc.ChangeStatus(tenantContext(t), requests.UserStatusUpdate{ID: id, Status: string(entities.UserStatusSuspended), Reason: "Spam"})
+	assert.Nil(t, e)
+
+	_, e = uc.GetToken(tenantContext(t), requests.GetToken{Email: "john.doe@test.com", Password: "00000000"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusForbidden, e.Code)
+}
+
+func TestUserGetAll(t *testing.T) {
+	uc, _ := newTestUserUseCase(t)
+	createTestUser(t, uc, "c@test.com")
+	createTestUser(t, uc, "a@test.com")
+	createTestUser(t, uc, "b@test.com")
+
+	list, e := uc.GetAll(tenantContext(t), requests.UsersList{Sorts: "+email", Page: "1", Limit: "2"})
+	assert.Nil(t, e)
+	assert.Equal(t, int64(3), list.Total)
+	assert.Len(t, list.Data, 2)
+	assert.Equal(t, "a@test.com", list.Data[0].Email)
+	assert.Equal(t, "b@test.com", list.Data[1].Email)
+
+	list, e = uc.GetAll(tenantContext(t), requests.UsersList{Sorts: "-email", Page: "2", Limit: "2"})
+	assert.Nil(t, e)
+	assert.Len(t, list.Data, 1)
+	assert.Equal(t, "a@test.com", list.Data[0].Email)
+
+	_, e = uc.GetAll(tenantContext(t), requests.UsersList{Status: "unknown"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+
+	_, e = uc.GetAll(tenantContext(t), requests.UsersList{Sorts: "+password_hash"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+}
+
+func TestUserUpdate(t *testing.T) {
+	uc, database := newTestUserUseCase(t)
+	id := createTestUser(t, uc, "john.doe@test.com")
+	createTestUser(t, uc, "jane.doe@test.com")
+
+	req := requests.UserUpdate{
+		ID:        id,
+		Email:     "john.doe2@test.com",
+		Password:  "11111111",
+		Lastname:  "Doe2",
+		Firstname: "John2",
+		Version:   1,
+	}
+	user, e := uc.Update(tenantContext(t), req)
+	assert.Nil(t, e)
+	assert.Equal(t, "john.doe2@test.com", user.Email.String())
+	assert.Equal(t, "Doe2", user.Lastname)
+	assert.Equal(t, uint64(2), user.Version)
+	assert.Equal(t, []entities.AuditAction{entities.AuditActionCreate, entities.AuditActionUpdate}, auditActions(t, database, id))
+	assert.Equal(t, []string{entities.EventUserCreated, entities.EventUserUpdated}, outboxEvents(t, database, id))
+
+	// Outdated version
+	_, e = uc.Update(tenantContext(t), req)
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusPreconditionFailed, e.Code)
+
+	// Email of another user
+	req.Version = 2
+	req.Email = "jane.doe@test.com"
+	_, e = uc.Update(tenantContext(t), req)
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusConflict, e.Code)
+}
+
+func TestUserChangeStatus(t *testing.T) {
+	uc, _ := newTestUserUseCase(t)
+	id := createTestUser(t, uc, "john.doe@test.com")
+	actorID := createTestUser(t, uc, "admin@test.com")
+	origin := requests.Origin{ActorID: actorID}
+
+	user, e := uc.ChangeStatus(tenantContext(t), requests.UserStatusUpdate{ID: id, Status: string(entities.UserStatusSuspended), Reason: "Spam", Origin: origin})
+	assert.Nil(t, e)
+	assert.Equal(t, entities.UserStatusSuspended, user.Status)
+	assert.Equal(t, "Spam", user.StatusReason)
+
+	_, e = uc.ChangeStatus(tenantContext(t), requests.UserStatusUpdate{ID: id, Status: string(entities.UserStatusSuspended), Reason: "Spam", Origin: origin})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusConflict, e.Code)
+
+	_, e = uc.ChangeStatus(tenantContext(t), requests.UserStatusUpdate{ID: actorID, Status: string(entities.UserStatusSuspended), Reason: "Spam", Origin: origin})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusForbidden, e.Code)
+}
+
+func TestUserUpdateAvatar(t *testing.T) {
+	uc, _ := newTestUserUseCase(t)
+	id := createTestUser(t, uc, "john.doe@test.com")
+
+	var content bytes.Buffer
+	if err := png.Encode(&content, image.NewRGBA(image.Rect(0, 0, 1024, 768))); err != nil {
+		t.Fatal(err)
+	}
+
+	user, e := uc.UpdateAvatar(tenantContext(t), requests.UserAvatarUpdate{ID: id, Content: content.Bytes(), MimeType: "image/png", Version: 1})
+	assert.Nil(t, e)
+	assert.NotEmpty(t, user.Avatar)
+	assert.Equal(t, "http://localhost/storage/"+user.Avatar, user.AvatarURL)
+	assert.Equal(t, "http://localhost/storage/"+avatarThumbnailKey(user.Avatar), user.ThumbnailURL)
+	assert.Equal(t, uint64(2), user.Version)
+
+	_, e = uc.UpdateAvatar(tenantContext(t), requests.UserAvatarUpdate{ID: id, Content: content.Bytes(), MimeType: "image/png", Version: 1})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusPreconditionFailed, e.Code)
+
+	_, e = uc.UpdateAvatar(tenantContext(t), requests.UserAvatarUpdate{ID: id, Content: []byte("not an image"), MimeType: "image/png"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+}
+
+func TestUserDelete(t *testing.T) {
+	uc, database := newTestUserUseCase(t)
+	id := createTestUser(t, uc, "john.doe@test.com")
+
+	e := uc.Delete(tenantContext(t), requests.UserDelete{ID: id, Version: 2})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusPreconditionFailed, e.Code)
+
+	e = uc.Delete(tenantContext(t), requests.UserDelete{ID: id, Version: 1})
+	assert.Nil(t, e)
+	assert.Equal(t, []entities.AuditAction{entities.AuditActionCreate, entities.AuditActionDelete}, auditActions(t, database, id))
+	assert.Equal(t, []string{entities.EventUserCreated, entities.EventUserDeleted}, outboxEvents(t, database, id))
+
+	_, e = uc.GetByID(tenantContext(t), requests.UserByID{ID: id})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+
+	e = uc.Delete(tenantContext(t), requests.UserDelete{ID: id})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+
+	// The email of a deleted user can not be used again until the user is erased
+	_, e = uc.Create(tenantContext(t), requests.UserCreation{Email: "john.doe@test.com", Password: "00000000", Lastname: "Doe", Firstname: "John"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusConflict, e.Code)
+}
+
+func TestUserTenantIsolation(t *testing.T) {
+	uc, _ := newTestUserUseCase(t)
+	id := createTestUser(t, uc, "john.doe@test.com")
+	other := repositories.WithTenant(t.Context(), "f47ac10b-58cc-0372-8562-0b8e853961c1")
+
+	user, e := uc.GetByID(tenantContext(t), requests.UserByID{ID: id})
+	assert.Nil(t, e)
+	assert.Equal(t, entities.DefaultOrganizationID, user.OrganizationID.String())
+
+	_, e = uc.GetByID(other, requests.UserByID{ID: id})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+
+	e = uc.Delete(other, requests.UserDelete{ID: id})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusNotFound, e.Code)
+
+	// Emails are unique per organization
+	created, e := uc.Create(other, requests.UserCreation{Email: "john.doe@test.com", Password: "00000000", Lastname: "Doe", Firstname: "John"})
+	assert.Nil(t, e)
+	assert.Equal(t, "f47ac10b-58cc-0372-8562-0b8e853961c1", created.OrganizationID.String())
+
+	list, e := uc.GetAll(other, requests.UsersList{})
+	assert.Nil(t, e)
+	assert.Equal(t, int64(1), list.Total)
+
+	_, e = uc.Create(t.Context(), requests.UserCreation{Email: "jane.doe@test.com", Password: "00000000", Lastname: "Doe", Firstname: "Jane"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusInternalServerError, e.Code)
+}
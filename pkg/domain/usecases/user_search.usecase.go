@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/utils"
+	"context"
+	"strconv"
+)
+
+const (
+	// userSearchDefaultLimit is the number of users returned by a search without limit
+	userSearchDefaultLimit = 20
+
+	// userSearchMaxLimit is the maximum number of users returned by a search
+	userSearchMaxLimit = 100
+)
+
+// UserSearch is an interface for user search use cases
+type UserSearch interface {
+	Search(context.Context, requests.UserSearch) (responses.UsersSearch, *utils.HTTPError)
+}
+
+type userSearchUseCase struct {
+	userSearchRepository repositories.UserSearchRepository
+}
+
+// NewUserSearch returns a new UserSearch use case
+func NewUserSearch(userSearchRepository repositories.UserSearchRepository) UserSearch {
+	return &userSearchUseCase{userSearchRepository}
+}
+
+// Search users by names and email, the most relevant first
+func (uc *userSearchUseCase) Search(ctx context.Context, req requests.UserSearch) (responses.UsersSearch, *utils.HTTPError) {
+	reqErrors := utils.ValidateStruct(req)
+	if reqErrors != nil {
+		return responses.UsersSearch{}, utils.NewHTTPError(utils.StatusBadRequest, "Invalid request data", reqErrors, nil)
+	}
+
+	limit, err := strconv.Atoi(req.Limit)
+	if err != nil || limit < 1 {
+		limit = userSearchDefaultLimit
+	}
+
+	hits, err := uc.userSearchRepository.Search(ctx, requests.UserSearchRepository{
+		Query: req.Query,
+		Limit: min(limit, userSearchMaxLimit),
+	})
+	if err != nil {
+		return responses.UsersSearch{}, utils.NewHTTPError(utils.StatusInternalServerError, "Internal server error", "Error when searching users", err)
+	}
+
+	return responses.UsersSearch{Data: hits}, nil
+}
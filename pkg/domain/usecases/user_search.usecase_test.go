@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"chi_boilerplate/pkg/adapters/search"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/utils"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserSearch(t *testing.T) {
+	ctx := repositories.WithTenant(t.Context(), entities.DefaultOrganizationID)
+	index := search.NewUserIndex()
+	for i := range userSearchMaxLimit + 1 {
+		index.Add(entities.DefaultOrganizationID, responses.UsersListRepository{
+			ID: fmt.Sprintf("%03d", i), Email: fmt.Sprintf("user%d@example.com", i), Lastname: "Doe", Firstname: "John",
+		})
+	}
+	uc := NewUserSearch(index)
+
+	users, e := uc.Search(ctx, requests.UserSearch{Query: "doe"})
+	assert.Nil(t, e)
+	assert.Len(t, users.Data, userSearchDefaultLimit)
+
+	users, e = uc.Search(ctx, requests.UserSearch{Query: "doe", Limit: "5"})
+	assert.Nil(t, e)
+	assert.Len(t, users.Data, 5)
+	assert.Equal(t, "000", users.Data[0].ID)
+
+	users, e = uc.Search(ctx, requests.UserSearch{Query: "doe", Limit: "1000"})
+	assert.Nil(t, e)
+	assert.Len(t, users.Data, userSearchMaxLimit)
+
+	users, e = uc.Search(ctx, requests.UserSearch{Query: "user42"})
+	assert.Nil(t, e)
+	assert.Len(t, users.Data, 1)
+
+	users, e = uc.Search(ctx, requests.UserSearch{Query: "unknown"})
+	assert.Nil(t, e)
+	assert.Empty(t, users.Data)
+
+	_, e = uc.Search(ctx, requests.UserSearch{})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+
+	_, e = uc.Search(ctx, requests.UserSearch{Query: "doe", Limit: "ten"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusBadRequest, e.Code)
+
+	_, e = uc.Search(t.Context(), requests.UserSearch{Query: "doe"})
+	assert.NotNil(t, e)
+	assert.Equal(t, utils.StatusInternalServerError, e.Code)
+}
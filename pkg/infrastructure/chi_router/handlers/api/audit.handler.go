@@ -0,0 +1,54 @@
+package api
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/usecases"
+	"chi_boilerplate/pkg/infrastructure/chi_router/handlers"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"chi_boilerplate/utils"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Audit handler
+type Audit struct {
+	router       chi.Router
+	auditUseCase usecases.Audit
+	logger       logger.CustomLogger
+}
+
+// NewAudit returns a new Handler
+func NewAudit(r chi.Router, l logger.CustomLogger, auditUseCase usecases.Audit) Audit {
+	return Audit{
+		router:       r,
+		auditUseCase: auditUseCase,
+		logger:       l,
+	}
+}
+
+// AuditProtectedRoutes adds audit protected routes
+func (a *Audit) AuditProtectedRoutes() {
+	a.router.Get("/", handlers.WrapError(a.getAll, a.logger))
+}
+
+func (a *Audit) getAll(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+
+	events, err := a.auditUseCase.GetAll(r.Context(), requests.AuditEventsList{
+		Page:       q.Get("p"),
+		Limit:      q.Get("l"),
+		Sorts:      q.Get("s"),
+		ActorID:    q.Get("actor_id"),
+		Action:     q.Get("action"),
+		TargetType: q.Get("target_type"),
+		TargetID:   q.Get("target_id"),
+		From:       q.Get("from"),
+		To:         q.Get("to"),
+	})
+	if err != nil {
+		return err.SendError(w)
+	}
+
+	return utils.JSON(w, events)
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/usecases"
+	"chi_boilerplate/pkg/infrastructure/chi_router/handlers"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"chi_boilerplate/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CRUDReadHandler is a generic handler of the list and get routes of a resource T listed as items I.
+// The ETag of a resource implementing ETag() is sent with it and checked against If-None-Match.
+type CRUDReadHandler[T, I any] struct {
+	router  chi.Router
+	reader  usecases.CRUDReader[T, I]
+	logger  logger.CustomLogger
+	filters []string
+}
+
+// NewCRUDReadHandler returns a new CRUDReadHandler. filters are the query parameters passed as list filters.
+func NewCRUDReadHandler[T, I any](r chi.Router, l logger.CustomLogger, reader usecases.CRUDReader[T, I], filters ...string) CRUDReadHandler[T, I] {
+	return CRUDReadHandler[T, I]{
+		router:  r,
+		reader:  reader,
+		logger:  l,
+		filters: filters,
+	}
+}
+
+// Routes adds the list and get routes.
+// The list is paginated and sorted with the p, l and s query parameters like the other lists.
+func (h *CRUDReadHandler[T, I]) Routes() {
+	h.router.Get("/", handlers.WrapError(h.getAll, h.logger))
+	h.router.Get("/{id}", handlers.WrapError(h.getByID, h.logger))
+}
+
+func (h *CRUDReadHandler[T, I]) getAll(w http.ResponseWriter, r *http.Request) error {
+	list, err := h.reader.GetAll(r.Context(), listRequest(r, h.filters...))
+	if err != nil {
+		return err.SendError(w)
+	}
+
+	return utils.JSON(w, list)
+}
+
+func (h *CRUDReadHandler[T, I]) getByID(w http.ResponseWriter, r *http.Request) error {
+	res, err := h.reader.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		return err.SendError(w)
+	}
+
+	if etag, ok := setETag(w, res); ok {
+		if match := r.Header.Get("If-None-Match"); match != "" && utils.ETagMatch(match, etag) {
+			return utils.NotModified(w)
+		}
+	}
+
+	return utils.JSON(w, res)
+}
+
+// setETag sets the ETag header of a resource implementing ETag() and returns it
+func setETag(w http.ResponseWriter, res any) (string, bool) {
+	tagged, ok := res.(interface{ ETag() string })
+	if !ok {
+		return "", false
+	}
+
+	etag := tagged.ETag()
+	w.Header().Set("ETag", etag)
+
+	return etag, true
+}
+
+// CRUDHandler is a generic handler of the routes of a resource T created from the bodies C and updated from the bodies U.
+// Updates and deletions require the If-Match header like the ones of the users (see ifMatchVersion).
+type CRUDHandler[T, C, U any] struct {
+	CRUDReadHandler[T, T]
+	useCase usecases.CRUD[T, C, U]
+}
+
+// NewCRUDHandler returns a new CRUDHandler. filters are the query parameters passed as list filters.
+func NewCRUDHandler[T, C, U any](r chi.Router, l logger.CustomLogger, useCase usecases.CRUD[T, C, U], filters ...string) CRUDHandler[T, C, U] {
+	return CRUDHandler[T, C, U]{
+		CRUDReadHandler: NewCRUDReadHandler[T, T](r, l, useCase, filters...),
+		useCase:         useCase,
+	}
+}
+
+// Routes adds the list, get, create, update and delete routes.
+// The list is paginated and sorted with the p, l and s query parameters like the other lists.
+func (h *CRUDHandler[T, C, U]) Routes() {
+	h.CRUDReadHandler.Routes()
+	h.router.Post("/", handlers.WrapError(h.create, h.logger))
+	h.router.Put("/{id}", handlers.WrapError(h.update, h.logger))
+	h.router.Delete("/{id}", handlers.WrapError(h.delete, h.logger))
+}
+
+func (h *CRUDHandler[T, C, U]) create(w http.ResponseWriter, r *http.Request) error {
+	var body C
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return utils.Err400(w, err, "Error decoding body", nil)
+	}
+
+	res, err := h.useCase.Create(r.Context(), body)
+	if err != nil {
+		return err.SendError(w)
+	}
+	setETag(w, res)
+
+	return utils.JSON(w, res)
+}
+
+func (h *CRUDHandler[T, C, U]) update(w http.ResponseWriter, r *http.Request) error {
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		return err.SendError(w)
+	}
+
+	var body U
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return utils.Err400(w, err, "Error decoding body", nil)
+	}
+
+	res, err := h.useCase.Update(r.Context(), chi.URLParam(r, "id"), version, body)
+	if err != nil {
+		return err.SendError(w)
+	}
+	setETag(w, res)
+
+	return utils.JSON(w, res)
+}
+
+func (h *CRUDHandler[T, C, U]) delete(w http.ResponseWriter, r *http.Request) error {
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		return err.SendError(w)
+	}
+
+	if err := h.useCase.Delete(r.Context(), chi.URLParam(r, "id"), version); err != nil {
+		return err.SendError(w)
+	}
+
+	return utils.NoContent(w)
+}
+
+// listRequest returns the list request of the query: pagination (p, l), sorts (s) and filters
+func listRequest(r *http.Request, filters ...string) requests.List {
+	q := r.URL.Query()
+
+	list := requests.List{
+		Pagination: requests.Pagination{
+			Page:  q.Get("p"),
+			Limit: q.Get("l"),
+			Sorts: q.Get("s"),
+		},
+		Filters: make(map[string]string, len(filters)),
+	}
+	for _, filter := range filters {
+		list.Filters[filter] = q.Get(filter)
+	}
+
+	return list
+}
+
+// ifMatchVersion returns the resource version expected by the If-Match header.
+// The version is 0 when the header is "*" (any version matches).
+func ifMatchVersion(r *http.Request) (uint64, *utils.HTTPError) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return 0, utils.NewHTTPError(utils.StatusPreconditionRequired, "If-Match header is required", nil, nil)
+	}
+	if ifMatch == utils.AnyETag {
+		return 0, nil
+	}
+
+	version, err := utils.VersionFromETag(ifMatch)
+	if err != nil {
+		return 0, utils.NewHTTPError(utils.StatusPreconditionFailed, "Invalid If-Match header", nil, nil)
+	}
+
+	return version, nil
+}
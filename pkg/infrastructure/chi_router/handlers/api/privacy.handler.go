@@ -0,0 +1,70 @@
+package api
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/pkg/domain/usecases"
+	"chi_boilerplate/pkg/infrastructure/chi_router/handlers"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"chi_boilerplate/utils"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Privacy handler
+type Privacy struct {
+	router         chi.Router
+	privacyUseCase usecases.Privacy
+	logger         logger.CustomLogger
+}
+
+// NewPrivacy returns a new Handler
+func NewPrivacy(r chi.Router, l logger.CustomLogger, privacyUseCase usecases.Privacy) Privacy {
+	return Privacy{
+		router:         r,
+		privacyUseCase: privacyUseCase,
+		logger:         l,
+	}
+}
+
+// PrivacyProtectedRoutes adds personal data protected routes (current user)
+func (p *Privacy) PrivacyProtectedRoutes() {
+	p.router.Get("/data-export", handlers.WrapError(p.dataExport, p.logger))
+}
+
+// dataExport returns the personal data of the current user as a ZIP archive (default) or as JSON (?format=json)
+func (p *Privacy) dataExport(w http.ResponseWriter, r *http.Request) error {
+	origin := handlers.RequestOrigin(r)
+
+	export, err := p.privacyUseCase.ExportData(r.Context(), requests.UserDataExport{ID: origin.ActorID})
+	if err != nil {
+		return err.SendError(w)
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		return utils.JSON(w, export)
+	case "", "zip":
+	default:
+		return utils.Err400(w, nil, "Invalid export format", nil)
+	}
+
+	data, e := json.MarshalIndent(export, "", "  ")
+	if e != nil {
+		return utils.Err500(w, e, "Error when exporting data", nil)
+	}
+
+	files := map[string][]byte{"data.json": data}
+	for _, f := range export.Files {
+		files["files/"+f.Name] = f.Content
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="data-export-%s.zip"`, origin.ActorID))
+	w.WriteHeader(http.StatusOK)
+
+	return services.WriteZip(w, files)
+}
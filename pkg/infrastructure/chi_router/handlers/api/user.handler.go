@@ -1,30 +1,57 @@
 package api
 
 import (
+	"chi_boilerplate/pkg/domain/entities"
 	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/pkg/domain/services"
 	"chi_boilerplate/pkg/domain/usecases"
 	"chi_boilerplate/pkg/infrastructure/chi_router/handlers"
 	"chi_boilerplate/pkg/infrastructure/logger"
 	"chi_boilerplate/utils"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// DefaultAvatarMaxSize is the default maximum size of an uploaded avatar (in bytes)
+const DefaultAvatarMaxSize = 2 << 20
+
 // User handler
 type User struct {
-	router      chi.Router
-	userUseCase usecases.User
-	logger      logger.CustomLogger
+	router          chi.Router
+	userUseCase     usecases.User
+	reader          CRUDReadHandler[responses.UserHTTP, responses.UsersListRepository] // List and get routes
+	logger          logger.CustomLogger
+	avatarMaxSize   int64
+	avatarMaxPixels int
 }
 
 // NewUser returns a new Handler
 func NewUser(r chi.Router, l logger.CustomLogger, userUseCase usecases.User) User {
 	return User{
-		router:      r,
-		userUseCase: userUseCase,
-		logger:      l,
+		router:        r,
+		userUseCase:   userUseCase,
+		reader:        NewCRUDReadHandler(r, l, usecases.NewUserReader(userUseCase), "status"),
+		logger:        l,
+		avatarMaxSize: DefaultAvatarMaxSize,
+	}
+}
+
+// SetAvatarMaxSize sets the maximum size of an uploaded avatar (in bytes)
+func (u *User) SetAvatarMaxSize(size int64) {
+	if size > 0 {
+		u.avatarMaxSize = size
+	}
+}
+
+// SetAvatarMaxPixels sets the maximum dimensions (width x height) of an uploaded avatar
+func (u *User) SetAvatarMaxPixels(pixels int) {
+	if pixels > 0 {
+		u.avatarMaxPixels = pixels
 	}
 }
 
@@ -35,11 +62,14 @@ func (u *User) UserPublicRoutes() {
 
 // UserProtectedRoutes adds users protected routes
 func (u *User) UserProtectedRoutes() {
+	u.reader.Routes()
 	u.router.Post("/", handlers.WrapError(u.create, u.logger))
-	u.router.Get("/", handlers.WrapError(u.getAll, u.logger))
-	u.router.Get("/{id}", handlers.WrapError(u.getByID, u.logger))
 	u.router.Put("/{id}", handlers.WrapError(u.update, u.logger))
 	u.router.Delete("/{id}", handlers.WrapError(u.delete, u.logger))
+	u.router.Put("/{id}/avatar", handlers.WrapError(u.updateAvatar, u.logger))
+	u.router.Post("/{id}/suspend", handlers.WrapError(u.changeStatus(entities.UserStatusSuspended), u.logger))
+	u.router.Post("/{id}/reactivate", handlers.WrapError(u.changeStatus(entities.UserStatusActive), u.logger))
+	u.router.Post("/{id}/disable", handlers.WrapError(u.changeStatus(entities.UserStatusDisabled), u.logger))
 }
 
 func (u *User) login(w http.ResponseWriter, r *http.Request) error {
@@ -48,7 +78,7 @@ func (u *User) login(w http.ResponseWriter, r *http.Request) error {
 		return utils.Err400(w, err, "Error decoding body", nil)
 	}
 
-	res, err := u.userUseCase.GetToken(body)
+	res, err := u.userUseCase.GetToken(r.Context(), body)
 	if err != nil {
 		return err.SendError(w)
 	}
@@ -61,8 +91,9 @@ func (u *User) create(w http.ResponseWriter, r *http.Request) error {
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		return utils.Err400(w, err, "Error decoding body", nil)
 	}
+	body.Origin = handlers.RequestOrigin(r)
 
-	res, err := u.userUseCase.Create(body)
+	res, err := u.userUseCase.Create(r.Context(), body)
 	if err != nil {
 		return err.SendError(w)
 	}
@@ -70,63 +101,129 @@ func (u *User) create(w http.ResponseWriter, r *http.Request) error {
 	return utils.JSON(w, res.ToUserHTTP())
 }
 
-func (u *User) getByID(w http.ResponseWriter, r *http.Request) error {
+func (u *User) delete(w http.ResponseWriter, r *http.Request) error {
 	id := chi.URLParam(r, "id")
 	if id == "" {
 		return utils.Err400(w, nil, "ID is required", nil)
 	}
 
-	res, err := u.userUseCase.GetByID(requests.UserByID{ID: id})
+	version, err := ifMatchVersion(r)
 	if err != nil {
 		return err.SendError(w)
 	}
 
-	return utils.JSON(w, res.ToUserHTTP())
+	err = u.userUseCase.Delete(r.Context(), requests.UserDelete{ID: id, Version: version, Origin: handlers.RequestOrigin(r)})
+	if err != nil {
+		return err.SendError(w)
+	}
+
+	return utils.NoContent(w)
 }
 
-func (u *User) delete(w http.ResponseWriter, r *http.Request) error {
+func (u *User) update(w http.ResponseWriter, r *http.Request) error {
 	id := chi.URLParam(r, "id")
 	if id == "" {
 		return utils.Err400(w, nil, "ID is required", nil)
 	}
 
-	err := u.userUseCase.Delete(requests.UserDelete{ID: id})
-	if err != nil {
-		return err.SendError(w)
+	version, httpErr := ifMatchVersion(r)
+	if httpErr != nil {
+		return httpErr.SendError(w)
 	}
 
-	return utils.NoContent(w)
-}
-
-func (u *User) getAll(w http.ResponseWriter, r *http.Request) error {
-	page := r.URL.Query().Get("p")
-	limit := r.URL.Query().Get("l")
-	sorts := r.URL.Query().Get("s")
+	var body requests.UserUpdate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return utils.Err400(w, err, "Error decoding body", nil)
+	}
+	body.ID = id
+	body.Version = version
+	body.Origin = handlers.RequestOrigin(r)
 
-	users, err := u.userUseCase.GetAll(requests.UsersList{Page: page, Limit: limit, Sorts: sorts})
+	res, err := u.userUseCase.Update(r.Context(), body)
 	if err != nil {
 		return err.SendError(w)
 	}
 
-	return utils.JSON(w, users)
+	w.Header().Set("ETag", res.ETag())
+
+	return utils.JSON(w, res.ToUserHTTP())
 }
 
-func (u *User) update(w http.ResponseWriter, r *http.Request) error {
+func (u *User) changeStatus(status entities.UserStatus) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			return utils.Err400(w, nil, "ID is required", nil)
+		}
+
+		var body requests.UserStatusUpdate
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return utils.Err400(w, err, "Error decoding body", nil)
+		}
+		body.ID = id
+		body.Status = string(status)
+		body.Origin = handlers.RequestOrigin(r)
+
+		res, err := u.userUseCase.ChangeStatus(r.Context(), body)
+		if err != nil {
+			return err.SendError(w)
+		}
+
+		w.Header().Set("ETag", res.ETag())
+
+		return utils.JSON(w, res.ToUserHTTP())
+	}
+}
+
+func (u *User) updateAvatar(w http.ResponseWriter, r *http.Request) error {
 	id := chi.URLParam(r, "id")
 	if id == "" {
 		return utils.Err400(w, nil, "ID is required", nil)
 	}
 
-	var body requests.UserUpdate
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		return utils.Err400(w, err, "Error decoding body", nil)
+	version, httpErr := ifMatchVersion(r)
+	if httpErr != nil {
+		return httpErr.SendError(w)
 	}
-	body.ID = id
 
-	res, err := u.userUseCase.Update(body)
+	// Multipart overhead is small compared to the image itself
+	r.Body = http.MaxBytesReader(w, r.Body, u.avatarMaxSize+4096)
+	file, _, err := r.FormFile("avatar")
 	if err != nil {
-		return err.SendError(w)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return utils.Err(w, utils.StatusRequestEntityTooLarge, err, "Avatar is too large", nil)
+		}
+		return utils.Err400(w, err, "Avatar file is required", nil)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, u.avatarMaxSize+1))
+	if err != nil {
+		return utils.Err400(w, err, "Error reading avatar file", nil)
+	}
+	if int64(len(content)) > u.avatarMaxSize {
+		return utils.Err(w, utils.StatusRequestEntityTooLarge, nil, "Avatar is too large", nil)
 	}
 
+	mimeType := http.DetectContentType(content)
+	if mimeType != services.MimeTypeJPEG && mimeType != services.MimeTypePNG {
+		return utils.Err(w, utils.StatusUnsupportedMediaType, nil, "Unsupported image type", mimeType)
+	}
+
+	res, httpErr := u.userUseCase.UpdateAvatar(r.Context(), requests.UserAvatarUpdate{
+		ID:        id,
+		Content:   content,
+		MimeType:  mimeType,
+		MaxPixels: u.avatarMaxPixels,
+		Version:   version,
+		Origin:    handlers.RequestOrigin(r),
+	})
+	if httpErr != nil {
+		return httpErr.SendError(w)
+	}
+
+	w.Header().Set("ETag", res.ETag())
+
 	return utils.JSON(w, res.ToUserHTTP())
 }
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/usecases"
+	"chi_boilerplate/pkg/infrastructure/chi_router/handlers"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"chi_boilerplate/utils"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// UserSearch handler
+type UserSearch struct {
+	router            chi.Router
+	userSearchUseCase usecases.UserSearch
+	logger            logger.CustomLogger
+}
+
+// NewUserSearch returns a new Handler
+func NewUserSearch(r chi.Router, l logger.CustomLogger, userSearchUseCase usecases.UserSearch) UserSearch {
+	return UserSearch{
+		router:            r,
+		userSearchUseCase: userSearchUseCase,
+		logger:            l,
+	}
+}
+
+// UserSearchProtectedRoutes adds user search protected routes
+func (u *UserSearch) UserSearchProtectedRoutes() {
+	u.router.Get("/search", handlers.WrapError(u.search, u.logger))
+}
+
+func (u *UserSearch) search(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+
+	users, err := u.userSearchUseCase.Search(r.Context(), requests.UserSearch{
+		Query: q.Get("q"),
+		Limit: q.Get("l"),
+	})
+	if err != nil {
+		return err.SendError(w)
+	}
+
+	return utils.JSON(w, users)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"net"
+	"net/http"
+
+	"github.com/go-chi/jwtauth/v5"
+)
+
+// RequestOrigin returns the actor (JWT subject), the request ID and the client IP of a request
+func RequestOrigin(r *http.Request) requests.Origin {
+	origin := requests.Origin{
+		IP: r.RemoteAddr,
+	}
+
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		origin.IP = host
+	}
+
+	origin.RequestID = logger.RequestID(r.Context())
+
+	if token, _, err := jwtauth.FromContext(r.Context()); err == nil && token != nil {
+		origin.ActorID = token.Subject()
+	}
+
+	return origin
+}
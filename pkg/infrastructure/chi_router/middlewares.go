@@ -1,12 +1,18 @@
 package chi_router
 
 import (
-	"chi_boilerplate/pkg/infrastructure/chi_router/handlers"
+	"chi_boilerplate/pkg"
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/domain/entities"
+	"chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/pkg/domain/usecases"
 	"chi_boilerplate/pkg/infrastructure/logger"
 	"chi_boilerplate/utils"
-	"context"
-	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -41,6 +47,9 @@ func (s *ChiServer) initMiddlewares(r *chi.Mux) {
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(viper.GetDuration("SERVER_TIMEOUT") * time.Second))
 	r.Use(middleware.RealIP)
+	if s.ReadYourWritesWindow > 0 {
+		r.Use(s.readYourWrites)
+	}
 
 	// Profiler
 	if viper.GetBool("PPROF_ENABLE") {
@@ -57,11 +66,7 @@ func (s *ChiServer) initAccessLogger() func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now().UTC()
-			ctxID := r.Context().Value(handlers.RequestIDKey("request_id"))
-			var requestId string
-			if ctxID != nil {
-				requestId = fmt.Sprintf("%s", r.Context().Value(handlers.RequestIDKey("request_id")))
-			}
+			requestId := logger.RequestID(r.Context())
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
 			next.ServeHTTP(ww, r)
@@ -110,12 +115,15 @@ func (s *ChiServer) initPprofBasicAuth() func(next http.Handler) http.Handler {
 	return middleware.BasicAuth("Restricted", creds)
 }
 
-func (s *ChiServer) initJWT(r chi.Router) {
+// organizationHeader is the header selecting the organization of the public routes
+const organizationHeader = "X-Organization-Id"
+
+func (s *ChiServer) initJWT(r chi.Router, userUseCase usecases.User) {
 	r.Use(jwtauth.Verifier(tokenAuth))
-	r.Use(s.jwtAuthenticator(tokenAuth))
+	r.Use(s.jwtAuthenticator(tokenAuth, userUseCase))
 }
 
-func (s *ChiServer) jwtAuthenticator(ja *jwtauth.JWTAuth) func(http.Handler) http.Handler {
+func (s *ChiServer) jwtAuthenticator(ja *jwtauth.JWTAuth, userUseCase usecases.User) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		hfn := func(w http.ResponseWriter, r *http.Request) {
 			token, _, err := jwtauth.FromContext(r.Context())
@@ -130,20 +138,121 @@ func (s *ChiServer) jwtAuthenticator(ja *jwtauth.JWTAuth) func(http.Handler) htt
 				return
 			}
 
+			// The repositories are scoped to the organization of the token
+			organizationID, _ := token.PrivateClaims()[services.JWTOrganizationClaim].(string)
+			if organizationID == "" {
+				utils.Err401(w, nil, "Unauthorized", nil)
+				return
+			}
+			ctx := repositories.WithTenant(r.Context(), organizationID)
+
+			// Tokens of deleted, suspended or disabled users are rejected
+			user, errUser := userUseCase.GetByID(ctx, requests.UserByID{ID: token.Subject()})
+			if errUser != nil {
+				if errUser.Code == utils.StatusInternalServerError {
+					if err := errUser.SendError(w); err != nil {
+						s.Logger.Error(err.Error())
+					}
+					return
+				}
+				utils.Err401(w, nil, "Unauthorized", nil)
+				return
+			}
+			if !user.Status.IsActive() {
+				utils.Err401(w, nil, "Account is not active", nil)
+				return
+			}
+
 			// Token is authenticated, pass it through
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		}
 		return http.HandlerFunc(hfn)
 	}
 }
 
+// resolveTenant scopes the repositories of the public routes to the organization selected
+// by the X-Organization-Id header, else by the subdomain of TENANT_DOMAIN, else to the default organization.
+func (s *ChiServer) resolveTenant(organizationUseCase usecases.Organization) func(http.Handler) http.Handler {
+	domain := pkg.NewConfigTenant().Domain
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			organizationID := entities.DefaultOrganizationID
+
+			var organization entities.Organization
+			var errOrganization *utils.HTTPError
+			if id := r.Header.Get(organizationHeader); id != "" {
+				organization, errOrganization = organizationUseCase.GetByID(r.Context(), requests.OrganizationByID{ID: id})
+				organizationID = organization.ID.String()
+			} else if slug := subdomain(r.Host, domain); slug != "" {
+				organization, errOrganization = organizationUseCase.GetBySlug(r.Context(), requests.OrganizationBySlug{Slug: slug})
+				organizationID = organization.ID.String()
+			}
+			if errOrganization != nil {
+				if err := errOrganization.SendError(w); err != nil {
+					s.Logger.Error(err.Error())
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(repositories.WithTenant(r.Context(), organizationID)))
+		})
+	}
+}
+
+// subdomain returns the first label of the host if it is a direct subdomain of the domain
+func subdomain(host, domain string) string {
+	if domain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	slug, found := strings.CutSuffix(strings.ToLower(host), "."+domain)
+	if !found || slug == "" || strings.Contains(slug, ".") {
+		return ""
+	}
+
+	return slug
+}
+
 func (s *ChiServer) requestID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		id := uuid.New().String()
-		ctx := context.WithValue(r.Context(), handlers.RequestIDKey("request_id"), id)
+		ctx := logger.WithRequestID(r.Context(), id)
 
 		w.Header().Add("X-Request-Id", id)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// readYourWritesCookie is the cookie routing the reads of a client to the primary database after its writes
+const readYourWritesCookie = "read_your_writes"
+
+// readYourWrites routes the reads of a client to the primary database once it has written,
+// instead of the read replicas which may lag behind. A write (transaction of a request other than GET, HEAD or OPTIONS)
+// sets a cookie expiring after ReadYourWritesWindow, so that the next requests of the client read from the primary too.
+func (s *ChiServer) readYourWrites(next http.Handler) http.Handler {
+	maxAge := max(1, int(s.ReadYourWritesWindow/time.Second))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := r.Cookie(readYourWritesCookie)
+		ctx := db.WithReadYourWrites(r.Context(), err == nil, func() {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				return
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     readYourWritesCookie,
+				Value:    "1",
+				Path:     "/",
+				MaxAge:   maxAge,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
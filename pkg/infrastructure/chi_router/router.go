@@ -2,46 +2,120 @@ package chi_router
 
 import (
 	"chi_boilerplate/pkg/adapters/db"
-	"chi_boilerplate/pkg/adapters/repositories/sqlx_mysql"
+	"chi_boilerplate/pkg/adapters/repositories"
+	"chi_boilerplate/pkg/adapters/storage"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/services"
 	"chi_boilerplate/pkg/domain/usecases"
 	"chi_boilerplate/pkg/infrastructure/chi_router/handlers"
 	"chi_boilerplate/pkg/infrastructure/chi_router/handlers/api"
 	"chi_boilerplate/pkg/infrastructure/chi_router/handlers/web"
 	"chi_boilerplate/pkg/infrastructure/logger"
 	"chi_boilerplate/utils"
+	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
 // ChiServer is a struct that represents a Chi server
 type ChiServer struct {
-	Addr   string
-	Port   string
-	DB     *db.SqlxMySQL
-	Logger logger.CustomLogger
+	Addr     string
+	Port     string
+	DB       db.Connection
+	Storage  domain.Storage
+	Logger   logger.CustomLogger
+	Cache    domain.Cache  // Cache of the user reads (nil: no cache)
+	CacheTTL time.Duration // Lifetime of the cached values
+	Timeouts Timeouts      // Timeouts of the HTTP server (zero: no timeout)
+
+	ReadYourWritesWindow time.Duration // Reads from the primary database after a write of a client (zero: disabled)
+
+	AvatarMaxSize   int64 // Maximum size of an uploaded avatar in bytes (zero: default)
+	AvatarMaxPixels int   // Maximum width x height of an uploaded avatar (zero: default)
+}
+
+// Timeouts are the timeouts of the HTTP server
+type Timeouts struct {
+	ReadHeader time.Duration // Reading of the request headers (slowloris protection)
+	Read       time.Duration // Reading of the whole request, body included
+	Write      time.Duration // From the end of the request headers to the end of the response
+	Idle       time.Duration // Wait of the next request on a keep-alive connection
+	Shutdown   time.Duration // Maximum wait of the in-flight requests at shutdown
 }
 
 // NewChiServer creates a new ChiServer
-func NewChiServer(addr, port string, db *db.SqlxMySQL, l logger.CustomLogger) ChiServer {
+func NewChiServer(addr, port string, db db.Connection, storage domain.Storage, l logger.CustomLogger) ChiServer {
 	return ChiServer{
-		Addr:   addr,
-		Port:   port,
-		DB:     db,
-		Logger: l,
+		Addr:    addr,
+		Port:    port,
+		DB:      db,
+		Storage: storage,
+		Logger:  l,
 	}
 }
 
-// Start the HTTP server
-func (s *ChiServer) Start() error {
+// Start the HTTP server until the context is canceled (see Serve)
+func (s *ChiServer) Start(ctx context.Context) error {
 	r, err := s.Setup()
 	if err != nil {
 		return err
 	}
 
+	ln, err := net.Listen("tcp", net.JoinHostPort(s.Addr, s.Port))
+	if err != nil {
+		return err
+	}
+
 	fmt.Printf("Server started on %s:%s...\n", s.Addr, s.Port)
-	return http.ListenAndServe(fmt.Sprintf("%s:%s", s.Addr, s.Port), r)
+	return s.Serve(ctx, ln, r)
+}
+
+// Serve serves the requests of the listener with handler until the context is canceled, then shuts down gracefully:
+// the listener is closed and the in-flight requests are waited for until the shutdown timeout.
+// The connections still active after it are closed and an error is returned.
+func (s *ChiServer) Serve(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: s.Timeouts.ReadHeader,
+		ReadTimeout:       s.Timeouts.Read,
+		WriteTimeout:      s.Timeouts.Write,
+		IdleTimeout:       s.Timeouts.Idle,
+	}
+
+	errServe := make(chan error, 1)
+	go func() {
+		errServe <- server.Serve(ln)
+	}()
+
+	select {
+	case err := <-errServe:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.Logger.Info("Shutting down the HTTP server", logger.Fields{
+		logger.NewField("timeout", "string", s.Timeouts.Shutdown.String()),
+	})
+
+	shutdownCtx := context.Background()
+	if s.Timeouts.Shutdown > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, s.Timeouts.Shutdown)
+		defer cancel()
+	}
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		_ = server.Close()
+		return fmt.Errorf("in-flight requests not finished after %s: %w", s.Timeouts.Shutdown, err)
+	}
+
+	s.Logger.Info("HTTP server stopped")
+	return nil
 }
 
 // Setup the HTTP server
@@ -57,6 +131,21 @@ func (s *ChiServer) Setup() (*chi.Mux, error) {
 		return r, err
 	}
 
+	// Repositories of the database driver
+	repos, err := repositories.New(s.DB)
+	if err != nil {
+		return r, err
+	}
+	if repos.UserSearch == nil {
+		repos, err = repos.WithSearchIndex(context.Background())
+		if err != nil {
+			return r, err
+		}
+	}
+	if s.Cache != nil {
+		repos = repos.WithCache(s.Cache, s.CacheTTL)
+	}
+
 	// Routes
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
 		utils.Err404(w, nil, "Ressource not found", nil)
@@ -64,7 +153,7 @@ func (s *ChiServer) Setup() (*chi.Mux, error) {
 	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
 		utils.Err405(w, nil, "Method not allowed", nil)
 	})
-	s.routes(r)
+	s.routes(r, repos)
 
 	return r, nil
 }
@@ -76,7 +165,7 @@ func (s *ChiServer) HandleError(f func(w http.ResponseWriter, r *http.Request) e
 }
 
 // routes defines the routes of the server.
-func (s *ChiServer) routes(r *chi.Mux) {
+func (s *ChiServer) routes(r *chi.Mux, repos repositories.Repositories) {
 	// Web routes
 	r.Get("/health", s.HandleError(web.HealthCheck))
 	r.Get("/big-tasks", s.HandleError(web.BigTasks))
@@ -92,18 +181,38 @@ func (s *ChiServer) routes(r *chi.Mux) {
 	fs := http.FileServer(http.Dir("./assets"))
 	r.Handle("/assets/*", http.StripPrefix("/assets/", fs))
 
+	// Uploaded files (local storage only)
+	if local, ok := s.Storage.(*storage.LocalStorage); ok {
+		sfs := http.FileServer(filesOnly{http.Dir(local.Root())})
+		r.Handle("/storage/*", http.StripPrefix("/storage/", sfs))
+	}
+
 	// API routes
 	r.Route("/api", func(a chi.Router) {
 		a.Use(s.initCORS())
 
 		// Version 1
 		a.Route("/v1", func(v1 chi.Router) {
+			// Audit
+			auditService := services.NewAudit(repos.Audit)
+			auditUseCase := usecases.NewAudit(repos.Audit, repos.Tx)
+
 			// User use case
-			userRepo := sqlx_mysql.NewUserMysqlRepository(s.DB)
-			userUseCase := usecases.NewUser(userRepo)
+			userUseCase := usecases.NewUser(repos.User, auditService, services.NewOutbox(repos.Outbox), s.Storage, repos.Tx)
+
+			// User search use case
+			userSearchUseCase := usecases.NewUserSearch(repos.UserSearch)
+
+			// Privacy use case
+			privacyUseCase := usecases.NewPrivacy(userUseCase, repos.User, repos.Audit, repos.Outbox, auditService, s.Storage, repos.Tx)
+
+			// Organization use case
+			organizationUseCase := usecases.NewOrganization(repos.Organization)
 
 			// Public routes
 			v1.Group(func(v1 chi.Router) {
+				v1.Use(s.resolveTenant(organizationUseCase))
+
 				// User routes
 				v1.Route("/", func(u chi.Router) {
 					h := api.NewUser(u, s.Logger, userUseCase)
@@ -113,14 +222,56 @@ func (s *ChiServer) routes(r *chi.Mux) {
 
 			// Protected routes
 			v1.Group(func(v1 chi.Router) {
-				s.initJWT(v1)
+				s.initJWT(v1, userUseCase)
 
 				// User routes
 				v1.Route("/users", func(u chi.Router) {
 					h := api.NewUser(u, s.Logger, userUseCase)
+					h.SetAvatarMaxSize(s.AvatarMaxSize)
+					h.SetAvatarMaxPixels(s.AvatarMaxPixels)
 					h.UserProtectedRoutes()
+
+					hs := api.NewUserSearch(u, s.Logger, userSearchUseCase)
+					hs.UserSearchProtectedRoutes()
+				})
+
+				// Current user routes
+				v1.Route("/me", func(m chi.Router) {
+					h := api.NewPrivacy(m, s.Logger, privacyUseCase)
+					h.PrivacyProtectedRoutes()
+				})
+
+				// Audit routes
+				v1.Route("/audit", func(a chi.Router) {
+					h := api.NewAudit(a, s.Logger, auditUseCase)
+					h.AuditProtectedRoutes()
 				})
 			})
 		})
 	})
 }
+
+// filesOnly is a file system hiding its directories: the file server responds 404 instead of listing them
+type filesOnly struct {
+	fs http.FileSystem
+}
+
+// Open opens a file, a directory being reported as not found
+func (f filesOnly) Open(name string) (http.File, error) {
+	file, err := f.fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, os.ErrNotExist
+	}
+
+	return file, nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories"
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var erasureRetentionDays int
+
+func init() {
+	eraseCmd.Flags().IntVarP(&erasureRetentionDays, "retention", "r", 30, "number of days a deleted user is kept before erasure")
+
+	rootCmd.AddCommand(eraseCmd)
+}
+
+var eraseCmd = &cobra.Command{
+	Use:   "erase",
+	Short: "Erase personal data of deleted users",
+	Long:  `Anonymise the email, names, password and avatar of the users of all organizations deleted for more than the retention period (GDPR right to erasure)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize configuration
+		config, err := initConfig()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize database
+		db, err := initDatabase(config, nil)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize storage
+		storage, err := initStorage(config)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize repositories
+		repos, err := repositories.New(db)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Call use case for each organization
+		fmt.Println()
+		err = eraseDeletedUsers(context.Background(), repos, storage, erasureRetentionDays, func(slug string, erased int) {
+			fmt.Printf("%s: %d user(s) erased\n", slug, erased)
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	},
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"chi_boilerplate/pkg/infrastructure/generator"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	resourceFields string
+	projectPath    string
+)
+
+func init() {
+	generateResourceCmd.Flags().StringVarP(&resourceFields, "fields", "f", "", "fields of the resource (Ex.: name:string,price:int)")
+	generateResourceCmd.Flags().StringVarP(&projectPath, "path", "p", ".", "root directory of the project")
+	_ = generateResourceCmd.MarkFlagRequired("fields")
+
+	generateCmd.AddCommand(generateResourceCmd)
+	rootCmd.AddCommand(generateCmd)
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Code generation",
+	Long:  `Generate the boilerplate code of the project`,
+}
+
+var generateResourceCmd = &cobra.Command{
+	Use:   "resource NAME",
+	Short: "Generate a CRUD resource",
+	Long: `Generate the entity, ID value object, requests, response, repository interface, sqlx adapters,
+use case with its tests, handler and migrations of a CRUD resource scoped to the organizations,
+and add its paths and schemas to the OpenAPI documentation.
+
+NAME is in CamelCase (Ex.: Product) and the types of the fields are: ` + strings.Join(generator.FieldTypes(), ", "),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		module, err := generator.Module(projectPath)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		resource, err := generator.NewResource(module, args[0], resourceFields, time.Now())
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		files, err := generator.Generate(projectPath, resource)
+		fmt.Println()
+		for _, file := range files {
+			fmt.Printf("Created %s\n", file)
+		}
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+		fmt.Printf("Updated %s\n", filepath.Join(projectPath, generator.DocPath))
+
+		fmt.Printf(`
+Next steps:
+  1. Add the repository to pkg/adapters/repositories/repositories.go (sqlx connections only):
+       %[1]s domain.%[1]sRepository
+       %[1]s: sqlx_mysql.New%[1]sMysqlRepository(c),
+  2. Add the routes to the protected group of pkg/infrastructure/chi_router/router.go:
+       %[2]sUseCase := usecases.New%[1]s(repos.%[1]s, repos.Tx)
+       v1.Route("/%[3]s", func(r chi.Router) {
+           h := api.New%[1]s(r, s.Logger, %[2]sUseCase)
+           h.%[1]sProtectedRoutes()
+       })
+  3. Apply the migrations: migrate up
+`, resource.Name, resource.Var(), resource.Route())
+	},
+}
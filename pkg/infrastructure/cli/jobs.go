@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"chi_boilerplate/pkg"
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/repositories"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/pkg/domain/usecases"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"chi_boilerplate/utils"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Names of the built-in jobs
+const (
+	jobUsersErasure = "users-erasure"
+	jobRunsPurge    = "job-runs-purge"
+	jobOutboxPurge  = "outbox-purge"
+	jobAuditPurge   = "audit-purge"
+	jobLogsPurge    = "logs-purge"
+)
+
+// scheduledJob is a built-in job with its configured cron expression (empty: disabled)
+type scheduledJob struct {
+	schedule string
+	job      services.Job
+}
+
+var (
+	jobsHistoryJob   string
+	jobsHistoryLimit int
+)
+
+func init() {
+	jobsHistoryCmd.Flags().StringVarP(&jobsHistoryJob, "job", "j", "", "only display the runs of a job")
+	jobsHistoryCmd.Flags().IntVarP(&jobsHistoryLimit, "limit", "l", 20, "number of runs to display")
+
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsRunCmd)
+	jobsCmd.AddCommand(jobsHistoryCmd)
+	rootCmd.AddCommand(jobsCmd)
+}
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Scheduled jobs",
+	Long:  `Manage the scheduled jobs (see SCHEDULER_ENABLE and the JOB_* variables)`,
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the scheduled jobs",
+	Long:  `List the configured jobs with their next run`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		scheduler, _, err := initJobsCommand()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		fmt.Println()
+		now := time.Now()
+		for _, job := range scheduler.Jobs() {
+			fmt.Printf("%-16s  next run: %s\n", job.Name, job.Schedule.Next(now).Format(utils.SqlDateTimeFormat))
+		}
+	},
+}
+
+var jobsRunCmd = &cobra.Command{
+	Use:   "run NAME",
+	Short: "Run a scheduled job",
+	Long:  `Run a configured job now, unless another instance is running it`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scheduler, conn, err := initJobsCommand()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		unlock, err := lockScheduler(context.Background(), conn)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+		defer unlock()
+
+		ran, err := scheduler.RunJob(context.Background(), args[0], time.Now())
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+		if !ran {
+			fmt.Printf("\nJob %s is running on another instance\n", args[0])
+			return
+		}
+
+		fmt.Printf("\nJob %s done (see jobs history)\n", args[0])
+	},
+}
+
+var jobsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Display the runs of the jobs",
+	Long:  `Display the latest runs of the scheduled jobs`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize configuration
+		config, err := initConfig()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize database
+		conn, err := initDatabase(config, nil)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		repos, err := repositories.New(conn)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		runs, err := repos.JobRuns.GetAll(context.Background(), requests.JobRunsList{Job: jobsHistoryJob, Limit: jobsHistoryLimit})
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		fmt.Println()
+		for _, run := range runs {
+			summary := run.Result
+			if run.Error != "" {
+				summary = run.Error
+			}
+			fmt.Printf("%s  %-16s  %-9s  %-24s  %s\n", run.ScheduledAt, run.Job, run.Status, run.Instance, summary)
+		}
+	},
+}
+
+// initJobsCommand initializes the scheduler of the jobs commands and its database connection
+func initJobsCommand() (*services.Scheduler, db.Connection, error) {
+	config, err := initConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := initDatabase(config, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storage, err := initStorage(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scheduler, err := initScheduler(config, conn, storage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return scheduler, conn, nil
+}
+
+// initScheduler initializes the scheduler of the configured built-in jobs
+func initScheduler(config *pkg.Config, conn db.Connection, storage domain.Storage) (*services.Scheduler, error) {
+	repos, err := repositories.New(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	scheduler := services.NewScheduler(repos.JobRuns, repos.JobLocker, fmt.Sprintf("%s:%d", hostname, os.Getpid()))
+
+	jobs := []scheduledJob{
+		{
+			schedule: config.Scheduler.UsersErasureSchedule,
+			job: services.Job{
+				Name: jobUsersErasure,
+				Run: func(ctx context.Context) (string, error) {
+					erased := 0
+					err := eraseDeletedUsers(ctx, repos, storage, config.Scheduler.UsersErasureRetention, func(_ string, n int) {
+						erased += n
+					})
+					return fmt.Sprintf("%d user(s) erased", erased), err
+				},
+			},
+		},
+		{
+			schedule: config.Scheduler.JobRunsPurgeSchedule,
+			job: services.Job{
+				Name: jobRunsPurge,
+				Run: func(ctx context.Context) (string, error) {
+					before := time.Now().AddDate(0, 0, -config.Scheduler.JobRunsRetention).Format(utils.SqlDateTimeFormat)
+					deleted, err := repos.JobRuns.DeleteBefore(ctx, before)
+					return fmt.Sprintf("%d run(s) deleted", deleted), err
+				},
+			},
+		},
+		{
+			schedule: config.Scheduler.OutboxPurgeSchedule,
+			job: services.Job{
+				Name: jobOutboxPurge,
+				Run: func(ctx context.Context) (string, error) {
+					before := time.Now().AddDate(0, 0, -config.Scheduler.OutboxRetention).Format(utils.SqlDateTimeFormat)
+					deleted, err := repos.Outbox.DeletePublishedBefore(ctx, before)
+					return fmt.Sprintf("%d event(s) deleted", deleted), err
+				},
+			},
+		},
+		{
+			schedule: config.Scheduler.AuditPurgeSchedule,
+			job: services.Job{
+				Name: jobAuditPurge,
+				Run: func(ctx context.Context) (string, error) {
+					before := time.Now().AddDate(0, 0, -config.Scheduler.AuditRetention).Format(utils.SqlDateTimeFormat)
+					deleted, err := purgeAuditEvents(ctx, repos, before)
+					return fmt.Sprintf("%d event(s) deleted", deleted), err
+				},
+			},
+		},
+	}
+
+	// Log files are local to each instance
+	if slices.Contains(config.Log.Outputs, "file") {
+		jobs = append(jobs, scheduledJob{
+			schedule: config.Scheduler.LogsPurgeSchedule,
+			job: services.Job{
+				Name:  jobLogsPurge,
+				Local: true,
+				Run: func(ctx context.Context) (string, error) {
+					before := time.Now().AddDate(0, 0, -config.Scheduler.LogsRetention)
+					deleted, err := logger.PurgeRotatedFiles(config.Log.Path, config.AppName, before)
+					return fmt.Sprintf("%d file(s) deleted", deleted), err
+				},
+			},
+		})
+	}
+
+	for _, j := range jobs {
+		if j.schedule == "" {
+			continue
+		}
+
+		schedule, err := utils.ParseCron(j.schedule)
+		if err != nil {
+			return nil, err
+		}
+		j.job.Schedule = schedule
+		scheduler.Add(j.job)
+	}
+
+	return scheduler, nil
+}
+
+// eraseDeletedUsers erases the personal data of the users of all organizations deleted for more than retentionDays.
+// report is called with the number of erased users of each organization.
+func eraseDeletedUsers(ctx context.Context, repos repositories.Repositories, storage domain.Storage, retentionDays int, report func(slug string, erased int)) error {
+	auditService := services.NewAudit(repos.Audit)
+	userUseCase := usecases.NewUser(repos.User, auditService, services.NewOutbox(repos.Outbox), storage, repos.Tx)
+	privacyUseCase := usecases.NewPrivacy(userUseCase, repos.User, repos.Audit, repos.Outbox, auditService, storage, repos.Tx)
+	organizations, errRes := usecases.NewOrganization(repos.Organization).GetAll(ctx)
+	if errRes != nil {
+		return fmt.Errorf("%v (%v)", errRes.Message, errRes.Details)
+	}
+
+	for _, organization := range organizations {
+		tenantCtx := domain.WithTenant(ctx, organization.ID.String())
+		res, errRes := privacyUseCase.EraseDeleted(tenantCtx, requests.UsersErasure{RetentionDays: retentionDays})
+		if errRes != nil {
+			return fmt.Errorf("%s: %v (%v)", organization.Slug, errRes.Message, errRes.Details)
+		}
+
+		report(organization.Slug, res.Erased)
+	}
+
+	return nil
+}
+
+// purgeAuditEvents deletes the audit events of all organizations created before a date and returns their number
+func purgeAuditEvents(ctx context.Context, repos repositories.Repositories, before string) (int64, error) {
+	organizations, errRes := usecases.NewOrganization(repos.Organization).GetAll(ctx)
+	if errRes != nil {
+		return 0, fmt.Errorf("%v (%v)", errRes.Message, errRes.Details)
+	}
+
+	var deleted int64
+	for _, organization := range organizations {
+		n, err := repos.Audit.DeleteBefore(domain.WithTenant(ctx, organization.ID.String()), before)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("%s: %w", organization.Slug, err)
+		}
+	}
+
+	return deleted, nil
+}
+
+// lockScheduler prevents several instances from running the scheduler on a SQLite database,
+// where the jobs are elected with local locks. The other drivers elect the instance running each job.
+// The lock is held until unlock is called.
+func lockScheduler(ctx context.Context, conn db.Connection) (func(), error) {
+	c, ok := conn.(*db.SqlxSQLite)
+	if !ok {
+		return func() {}, nil
+	}
+
+	unlock, err := c.LockInstance(ctx, "scheduler")
+	if errors.Is(err, db.ErrLocked) {
+		return nil, errors.New("the scheduler is running on another instance: with SQLite, only one instance can run it")
+	}
+
+	return unlock, err
+}
+
+// schedulerErrorLogger returns a function logging the errors of the scheduled jobs
+func schedulerErrorLogger(l logger.CustomLogger) func(error) {
+	return func(err error) {
+		l.Error("Scheduled job error", logger.Fields{logger.NewField("error", "error", err)})
+	}
+}
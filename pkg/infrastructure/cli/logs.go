@@ -55,6 +55,8 @@ type errorLog struct {
 	RequestID   string    `json:"request_id"`
 	Latency     string    `json:"latency"`
 	UserAgent   string    `json:"userAgent"`
+	Query       string    `json:"query"`
+	Rows        *int      `json:"rows"`
 }
 
 func parseLine(line []byte, verboseFlag bool) (string, error) {
@@ -76,6 +78,13 @@ func parseLine(line []byte, verboseFlag bool) (string, error) {
 	if errLog.Description != "" {
 		description = fmt.Sprintf(" | Description: %s", errLog.Description)
 	}
+	query := ""
+	if errLog.Query != "" {
+		query = fmt.Sprintf(" | Query: %s", errLog.Query)
+	}
+	if errLog.Rows != nil {
+		query += fmt.Sprintf(" | Rows: %d", *errLog.Rows)
+	}
 	errorLog := ""
 	if errLog.Error != "" && errLog.Error != "<nil>" {
 		errorLog = fmt.Sprintf(" | Error: %s", errLog.Error)
@@ -113,13 +122,14 @@ func parseLine(line []byte, verboseFlag bool) (string, error) {
 		latency = fmt.Sprintf(" | %s", errLog.Latency)
 	}
 
-	result := fmt.Sprintf("%s | %7s %s%s%s%s%s%s%s%s%s%s%s%s%s",
+	result := fmt.Sprintf("%s | %7s %s%s%s%s%s%s%s%s%s%s%s%s%s%s",
 		errLog.Time.Format(time.RFC3339),
 		displayLogLevel(errLog.Level),
 		code,
 		method,
 		message,
 		description,
+		query,
 		errorLog,
 		path,
 		url,
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"chi_boilerplate/migrations"
+	"chi_boilerplate/pkg/adapters/db"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/spf13/cobra"
+)
+
+var migrationsPath string
+
+func init() {
+	migrateCreateCmd.Flags().StringVarP(&migrationsPath, "path", "p", "migrations", "migrations directory")
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateGotoCmd, migrateForceCmd, migrateStatusCmd, migrateCreateCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Database migrations",
+	Long:  `Run the SQL migrations embedded in the binary on the configured database`,
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply all migrations",
+	Long:  `Apply all the migrations not applied yet`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runMigrator(func(m *db.Migrator) error {
+			return m.Up()
+		})
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [N]",
+	Short: "Revert migrations",
+	Long:  `Revert the N last applied migrations (Default: 1)`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		n := 1
+		if len(args) == 1 {
+			var err error
+			if n, err = strconv.Atoi(args[0]); err != nil {
+				fmt.Printf("\nError: invalid number of migrations: %s\n", args[0])
+				return
+			}
+		}
+
+		runMigrator(func(m *db.Migrator) error {
+			return m.Down(n)
+		})
+	},
+}
+
+var migrateGotoCmd = &cobra.Command{
+	Use:   "goto V",
+	Short: "Migrate to a version",
+	Long:  `Apply or revert migrations to reach the version V`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			fmt.Printf("\nError: invalid version: %s\n", args[0])
+			return
+		}
+
+		runMigrator(func(m *db.Migrator) error {
+			return m.Goto(uint(version))
+		})
+	},
+}
+
+var migrateForceCmd = &cobra.Command{
+	Use:   "force V",
+	Short: "Force a version",
+	Long:  `Set the version V without running migrations and clear the dirty state (-1: no version)`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < -1 {
+			fmt.Printf("\nError: invalid version: %s\n", args[0])
+			return
+		}
+
+		runMigrator(func(m *db.Migrator) error {
+			return m.Force(version)
+		})
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Migrations status",
+	Long:  `Display the current version and the list of migrations`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runMigrator(func(m *db.Migrator) error {
+			list, err := m.Status()
+			if err != nil {
+				return err
+			}
+
+			fmt.Println()
+			for _, migration := range list {
+				status := aurora.Yellow("pending")
+				if migration.Applied {
+					status = aurora.Green("applied")
+				}
+				fmt.Printf("%s  %d  %s\n", status, migration.Version, migration.Name)
+			}
+
+			return nil
+		})
+	},
+}
+
+var migrateCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a migration",
+	Long:  `Create empty up and down migration files for MySQL, PostgreSQL and SQLite`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := db.CreateMigration(migrationsPath, args[0], time.Now())
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		fmt.Println()
+		for _, file := range files {
+			fmt.Printf("Created %s\n", file)
+		}
+	},
+}
+
+// runMigrator runs fn with a migrator of the configured database and displays the resulting version.
+func runMigrator(fn func(m *db.Migrator) error) {
+	// Initialize configuration
+	config, err := initConfig()
+	if err != nil {
+		fmt.Printf("\nError: %v\n", err)
+		return
+	}
+
+	// Initialize database
+	conn, err := initDatabase(config, nil)
+	if err != nil {
+		fmt.Printf("\nError: %v\n", err)
+		return
+	}
+
+	m, err := db.NewMigrator(conn, migrations.FS)
+	if err != nil {
+		fmt.Printf("\nError: %v\n", err)
+		return
+	}
+	defer m.Close()
+
+	if err = fn(m); err != nil {
+		fmt.Printf("\nError: %v\n", err)
+		return
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil {
+		fmt.Printf("\nError: %v\n", err)
+		return
+	}
+
+	fmt.Printf("\nVersion: %d", version)
+	if dirty {
+		fmt.Print(aurora.Red(" (dirty)"))
+	}
+	fmt.Println()
+}
+
+// migrateDatabase applies all the embedded migrations to the database of the connection.
+func migrateDatabase(conn db.Connection) error {
+	m, err := db.NewMigrator(conn, migrations.FS)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	return m.Up()
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/usecases"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	organizationName string
+	organizationSlug string
+)
+
+func init() {
+	organizationCreateCmd.Flags().StringVarP(&organizationName, "name", "n", "", "organization name")
+	organizationCreateCmd.Flags().StringVarP(&organizationSlug, "slug", "s", "", "organization slug (used as subdomain)")
+
+	organizationCreateCmd.MarkFlagRequired("name")
+	organizationCreateCmd.MarkFlagRequired("slug")
+
+	organizationCmd.AddCommand(organizationCreateCmd)
+	organizationCmd.AddCommand(organizationListCmd)
+	rootCmd.AddCommand(organizationCmd)
+}
+
+var organizationCmd = &cobra.Command{
+	Use:   "organization",
+	Short: "Organizations (tenants)",
+	Long:  `Manage the organizations owning the users`,
+}
+
+var organizationCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Organization creation",
+	Long:  `Organization creation`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		organizationUseCase, err := initOrganizationUseCase()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		res, errRes := organizationUseCase.Create(context.Background(), requests.OrganizationCreation{
+			Name: strings.TrimSpace(organizationName),
+			Slug: strings.TrimSpace(organizationSlug),
+		})
+		if errRes != nil {
+			fmt.Printf("\nError: %v (%v)\n", errRes.Message, errRes.Details)
+			return
+		}
+
+		fmt.Printf(`
+Organization successfully created:
+    - ID:   %s
+    - Name: %s
+    - Slug: %s
+`,
+			res.ID,
+			res.Name,
+			res.Slug,
+		)
+	},
+}
+
+var organizationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Organizations list",
+	Long:  `List all organizations`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		organizationUseCase, err := initOrganizationUseCase()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		organizations, errRes := organizationUseCase.GetAll(context.Background())
+		if errRes != nil {
+			fmt.Printf("\nError: %v (%v)\n", errRes.Message, errRes.Details)
+			return
+		}
+
+		fmt.Println()
+		for _, o := range organizations {
+			fmt.Printf("%s  %-20s  %s\n", o.ID, o.Slug, o.Name)
+		}
+	},
+}
+
+// initOrganizationUseCase initializes the configuration and the database and returns the organization use case
+func initOrganizationUseCase() (usecases.Organization, error) {
+	config, err := initConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := initDatabase(config, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := repositories.New(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return usecases.NewOrganization(repos.Organization), nil
+}
+
+// tenantContext returns a context scoping the repositories to the organization of the slug
+func tenantContext(repos repositories.Repositories, slug string) (context.Context, error) {
+	organization, errRes := usecases.NewOrganization(repos.Organization).GetBySlug(context.Background(), requests.OrganizationBySlug{Slug: slug})
+	if errRes != nil {
+		return nil, fmt.Errorf("organization %q: %v", slug, errRes.Message)
+	}
+
+	return domain.WithTenant(context.Background(), organization.ID.String()), nil
+}
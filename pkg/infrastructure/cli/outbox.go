@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"chi_boilerplate/pkg"
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/rabbitmq"
+	"chi_boilerplate/pkg/adapters/repositories"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var outboxRelayOnce bool
+
+func init() {
+	outboxRelayCmd.Flags().BoolVarP(&outboxRelayOnce, "once", "o", false, "relay one batch of pending events and exit")
+
+	outboxCmd.AddCommand(outboxRelayCmd)
+	rootCmd.AddCommand(outboxCmd)
+}
+
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Domain events outbox",
+	Long:  `Manage the domain events saved in the outbox`,
+}
+
+var outboxRelayCmd = &cobra.Command{
+	Use:   "relay",
+	Short: "Publish the outbox events",
+	Long:  `Publish the pending events of the outbox to RabbitMQ until the command is stopped (CTRL+C)`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize configuration
+		config, err := initConfig()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize logger
+		l, err := logger.NewZapLogger()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize database
+		conn, err := initDatabase(config, nil)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		relay, publisher, err := initOutboxRelay(config, conn)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+		defer publisher.Close()
+
+		if outboxRelayOnce {
+			published, err := relay.RelayPending(context.Background())
+			if err != nil {
+				fmt.Printf("\nError: %v\n", err)
+			}
+			fmt.Printf("\n%d event(s) published\n", published)
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Printf("\nOutbox relay started (interval: %s)\n", config.Outbox.RelayInterval)
+		relay.Run(ctx, config.Outbox.RelayInterval, outboxRelayErrorLogger(l))
+	},
+}
+
+// initOutboxRelay initializes the outbox relay of the database connection publishing to RabbitMQ.
+// The publisher must be closed by the caller.
+func initOutboxRelay(config *pkg.Config, conn db.Connection) (*services.OutboxRelay, *rabbitmq.Publisher, error) {
+	repos, err := repositories.New(conn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publisher := rabbitmq.NewPublisher(config.AMQP.URL, config.AMQP.Exchange)
+
+	return services.NewOutboxRelay(repos.Outbox, publisher, repos.JobLocker, config.Outbox.BatchSize), publisher, nil
+}
+
+// outboxRelayErrorLogger returns a function logging the errors of the outbox relay
+func outboxRelayErrorLogger(l logger.CustomLogger) func(error) {
+	return func(err error) {
+		l.Error("Outbox relay error", logger.Fields{logger.NewField("error", "error", err)})
+	}
+}
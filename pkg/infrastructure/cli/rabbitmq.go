@@ -2,6 +2,7 @@ package cli
 
 import (
 	"chi_boilerplate/pkg"
+	"chi_boilerplate/pkg/adapters/rabbitmq"
 	"context"
 	"fmt"
 	"log"
@@ -43,16 +44,11 @@ var rabbitMQCmd = &cobra.Command{
 }
 
 func startRabbitMQ(config *pkg.ConfigAMQP) (*amqp.Connection, amqp.Queue, *amqp.Channel) {
-	conn, err := amqp.Dial(config.URL)
+	conn, ch, err := rabbitmq.Dial(config.URL)
 	if err != nil {
 		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
 	}
 
-	ch, err := conn.Channel()
-	if err != nil {
-		log.Fatalf("Failed to open a channel: %v", err)
-	}
-
 	q, err := ch.QueueDeclare(
 		"hello", // name
 		false,   // durable
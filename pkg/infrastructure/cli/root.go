@@ -2,7 +2,12 @@ package cli
 
 import (
 	"chi_boilerplate/pkg"
+	"chi_boilerplate/pkg/adapters/cache"
 	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/storage"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"context"
 
 	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
@@ -27,9 +32,13 @@ func initConfig() (*pkg.Config, error) {
 	return pkg.NewConfig(".env")
 }
 
-// initDatabase initializes database connection.
-func initDatabase(config *pkg.Config) (*db.SqlxMySQL, error) {
-	return db.NewSqlxMySQL(&db.Config{
+// initDatabase initializes database connection of the configured driver and backend
+// and waits for the database to be reachable.
+// SQL queries and connection retries are logged with l if it is not nil.
+func initDatabase(config *pkg.Config, l logger.CustomLogger) (db.Connection, error) {
+	c := db.Config{
+		Driver:          config.Database.Driver,
+		Backend:         config.Database.Backend,
 		Host:            config.Database.Host,
 		Username:        config.Database.Username,
 		Password:        config.Database.Password,
@@ -38,10 +47,75 @@ func initDatabase(config *pkg.Config) (*db.SqlxMySQL, error) {
 		Charset:         config.Database.Charset,
 		Collation:       config.Database.Collation,
 		Location:        config.Database.Location,
+		SSLMode:         config.Database.SSLMode,
 		MaxIdleConns:    config.Database.MaxIdleConns,
 		MaxOpenConns:    config.Database.MaxOpenConns,
 		ConnMaxLifetime: config.Database.ConnMaxLifetime,
-	})
+		ConnMaxIdleTime: config.Database.ConnMaxIdleTime,
+		SlowThreshold:   config.Database.SlowThreshold,
+		TxIsolation:     config.Database.TxIsolation,
+		LogParams:       config.Database.LogParams,
+		Logger:          l,
+
+		ReplicaDSNs:                config.Database.ReplicaDSNs,
+		ReplicaHealthCheckInterval: config.Database.ReplicaHealthCheckInterval,
+	}
+
+	var conn db.Connection
+	var err error
+	switch c.Driver {
+	case db.DriverPostgres:
+		conn, err = db.NewSqlxPostgres(&c)
+	case db.DriverSQLite:
+		conn, err = db.NewSqlxSQLite(&c)
+	default:
+		if c.Backend == db.BackendGorm {
+			conn, err = db.NewGormMySQL(&c)
+		} else {
+			conn, err = db.NewSqlxMySQL(&c)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.WaitForConnection(context.Background(), conn, config.Database.ConnectTimeout, l); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// initStorage initializes file storage.
+func initStorage(config *pkg.Config) (domain.Storage, error) {
+	if config.Storage.Driver == "s3" {
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  config.Storage.S3Endpoint,
+			Region:    config.Storage.S3Region,
+			Bucket:    config.Storage.S3Bucket,
+			AccessKey: config.Storage.S3AccessKey,
+			SecretKey: config.Storage.S3SecretKey,
+			PublicURL: config.Storage.PublicURL,
+		})
+	}
+	return storage.NewLocalStorage(config.Storage.LocalPath, config.Storage.PublicURL)
+}
+
+// initCache returns the cache of the configured driver, nil without cache
+func initCache(config *pkg.Config) (domain.Cache, error) {
+	switch config.Cache.Driver {
+	case "memory":
+		return cache.NewMemoryCache(config.Cache.Size), nil
+	case "redis":
+		return cache.NewRedisCache(cache.RedisConfig{
+			Addr:     config.Cache.RedisAddr,
+			Password: config.Cache.RedisPassword,
+			DB:       config.Cache.RedisDB,
+			Prefix:   config.Cache.RedisPrefix,
+		})
+	default:
+		return nil, nil
+	}
 }
 
 func displayLogLevel(l string) aurora.Value {
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"chi_boilerplate/pkg/adapters/fixtures"
+	"chi_boilerplate/pkg/adapters/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/usecases"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	seedFiles        []string
+	seedFake         int
+	seedFakePassword string
+	seedOrganization string
+)
+
+func init() {
+	seedCmd.Flags().StringSliceVarP(&seedFiles, "file", "f", nil, "fixtures files (YAML or JSON)")
+	seedCmd.Flags().IntVarP(&seedFake, "fake", "n", 0, "number of fake users to generate")
+	seedCmd.Flags().StringVarP(&seedFakePassword, "fake-password", "p", usecases.FakeUserPassword, "password of the fake users")
+	seedCmd.Flags().StringVarP(&seedOrganization, "organization", "o", "default", "slug of the organization of the users")
+
+	rootCmd.AddCommand(seedCmd)
+}
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Database seeding",
+	Long:  `Load fixtures files and generate fake users. Users whose email already exists are skipped.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Load fixtures
+		req := requests.UsersSeed{Fake: seedFake, FakePassword: seedFakePassword}
+		for _, file := range seedFiles {
+			f, err := fixtures.Load(file)
+			if err != nil {
+				fmt.Printf("\nError: %v\n", err)
+				return
+			}
+			req.Users = append(req.Users, f.Users...)
+		}
+
+		// Initialize configuration
+		config, err := initConfig()
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize database
+		db, err := initDatabase(config, nil)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize repositories
+		repos, err := repositories.New(db)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		ctx, err := tenantContext(repos, seedOrganization)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Call use case
+		res, errRes := usecases.NewSeed(repos.User).Users(ctx, req)
+		if errRes != nil {
+			fmt.Printf("\nError: %v (%v)\n", errRes.Message, errRes.Details)
+			return
+		}
+
+		fmt.Printf("\n%d user(s) created, %d skipped\n", res.Created, res.Skipped)
+	},
+}
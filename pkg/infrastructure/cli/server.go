@@ -1,15 +1,52 @@
 package cli
 
 import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/rabbitmq"
+	"chi_boilerplate/pkg/adapters/repositories"
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+	"chi_boilerplate/pkg/domain/entities"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/pkg/domain/usecases"
 	"chi_boilerplate/pkg/infrastructure/chi_router"
 	"chi_boilerplate/pkg/infrastructure/logger"
+	"context"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// demoPassword is the password of the demo users
+const demoPassword = "demo1234"
+
+// demoUsers are the users created in demo mode
+var demoUsers = []requests.UserCreation{
+	{Email: "demo@example.com", Lastname: "Demo", Firstname: "User"},
+	{Email: "john.doe@example.com", Lastname: "Doe", Firstname: "John"},
+	{Email: "jane.doe@example.com", Lastname: "Doe", Firstname: "Jane"},
+	{Email: "alice.martin@example.com", Lastname: "Martin", Firstname: "Alice"},
+	{Email: "bob.bernard@example.com", Lastname: "Bernard", Firstname: "Bob"},
+}
+
+var (
+	serverDemo    bool
+	serverMigrate bool
+)
+
 func init() {
+	serverCmd.Flags().BoolVarP(&serverDemo, "demo", "d", false, "use an in-memory database with demo data instead of the configured database")
+	serverCmd.Flags().BoolVarP(&serverMigrate, "migrate", "m", false, "apply database migrations before starting (see DB_AUTO_MIGRATE)")
+
 	rootCmd.AddCommand(serverCmd)
 }
 
@@ -28,7 +65,7 @@ func startServer() {
 		log.Fatalln(err)
 	}
 
-	db, err := initDatabase(config)
+	storage, err := initStorage(config)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -38,8 +75,157 @@ func startServer() {
 		log.Fatalln(err)
 	}
 
-	server := chi_router.NewChiServer(viper.GetString("SERVER_ADDR"), viper.GetString("SERVER_PORT"), db, l)
-	if err = server.Start(); err != nil {
+	var conn db.Connection
+	if serverDemo {
+		conn, err = initDemoDatabase(storage)
+	} else {
+		conn, err = initDatabase(config, l)
+		if err == nil && (serverMigrate || config.Database.AutoMigrate) {
+			err = migrateDatabase(conn)
+		}
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// The server stops on CTRL+C or SIGTERM (docker stop)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Background workers, stopped before the connections are closed
+	var workers sync.WaitGroup
+
+	// Outbox relay
+	var publisher *rabbitmq.Publisher
+	if config.Outbox.RelayEnable {
+		var relay *services.OutboxRelay
+		relay, publisher, err = initOutboxRelay(config, conn)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			relay.Run(ctx, config.Outbox.RelayInterval, outboxRelayErrorLogger(l))
+		}()
+	}
+
+	// Scheduled jobs
+	if config.Scheduler.Enable {
+		scheduler, err := initScheduler(config, conn, storage)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		unlock, err := lockScheduler(ctx, conn)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			defer unlock()
+			scheduler.Run(ctx, schedulerErrorLogger(l))
+		}()
+	}
+
+	// Cache of the user reads
+	cache, err := initCache(config)
+	if err != nil {
 		log.Fatalln(err)
 	}
+
+	server := chi_router.NewChiServer(viper.GetString("SERVER_ADDR"), viper.GetString("SERVER_PORT"), conn, storage, l)
+	server.Cache = cache
+	server.CacheTTL = config.Cache.TTL
+	if config.Database.ReadYourWrites {
+		server.ReadYourWritesWindow = config.Database.ReadYourWritesWindow
+	}
+	server.AvatarMaxSize = config.Storage.AvatarMaxSize
+	server.AvatarMaxPixels = config.Storage.AvatarMaxPixels
+	server.Timeouts = chi_router.Timeouts{
+		ReadHeader: config.Server.ReadHeaderTimeout,
+		Read:       config.Server.ReadTimeout,
+		Write:      config.Server.WriteTimeout,
+		Idle:       config.Server.IdleTimeout,
+		Shutdown:   config.Server.ShutdownTimeout,
+	}
+	errServer := server.Start(ctx)
+	if errServer != nil {
+		l.Error("HTTP server error", logger.Fields{logger.NewField("error", "error", errServer)})
+	}
+
+	// Shutdown
+	stop()
+	stopWorkers(&workers, config.Server.ShutdownTimeout, l)
+	if publisher != nil {
+		closeLogged("AMQP connection", publisher.Close, l)
+	}
+	if c, ok := cache.(io.Closer); ok {
+		closeLogged("cache connection", c.Close, l)
+	}
+	closeLogged("database connections", func() error { return db.Close(conn) }, l)
+	l.Info("Server stopped")
+
+	if errServer != nil {
+		log.Fatalln(errServer)
+	}
+}
+
+// stopWorkers waits for the background workers to stop, for timeout at most
+func stopWorkers(workers *sync.WaitGroup, timeout time.Duration, l logger.CustomLogger) {
+	l.Info("Stopping the background workers")
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		l.Info("Background workers stopped")
+	case <-time.After(timeout):
+		l.Warn("Background workers still running after the shutdown timeout", logger.Fields{
+			logger.NewField("timeout", "string", timeout.String()),
+		})
+	}
+}
+
+// closeLogged closes a connection with closeFn and logs the result
+func closeLogged(name string, closeFn func() error, l logger.CustomLogger) {
+	if err := closeFn(); err != nil {
+		l.Error("Error when closing the "+name, logger.Fields{logger.NewField("error", "error", err)})
+		return
+	}
+	l.Info("Closed the " + name)
+}
+
+// initDemoDatabase initializes an in-memory database with demo users.
+// Data are lost when the server stops.
+func initDemoDatabase(storage domain.Storage) (db.Connection, error) {
+	conn := memory.NewDatabase()
+
+	repos, err := repositories.New(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users are created in the default organization with the use case to hash passwords and record audit events
+	ctx := domain.WithTenant(context.Background(), entities.DefaultOrganizationID)
+	userUseCase := usecases.NewUser(repos.User, services.NewAudit(repos.Audit), services.NewOutbox(repos.Outbox), storage, repos.Tx)
+	for _, user := range demoUsers {
+		user.Password = demoPassword
+		if _, errRes := userUseCase.Create(ctx, user); errRes != nil {
+			return nil, fmt.Errorf("%v (%v)", errRes.Message, errRes.Details)
+		}
+	}
+
+	fmt.Printf("Demo mode: in-memory database with %d users (password: %s)\n", len(demoUsers), demoPassword)
+	for _, user := range demoUsers {
+		fmt.Printf("  - %s\n", user.Email)
+	}
+
+	return conn, nil
 }
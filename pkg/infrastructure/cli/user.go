@@ -1,8 +1,9 @@
 package cli
 
 import (
-	"chi_boilerplate/pkg/adapters/repositories/sqlx_mysql"
+	"chi_boilerplate/pkg/adapters/repositories"
 	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/services"
 	"chi_boilerplate/pkg/domain/usecases"
 	"fmt"
 	"strings"
@@ -11,10 +12,11 @@ import (
 )
 
 var (
-	userEmail     string
-	userPassword  string
-	userLastname  string
-	userFirstname string
+	userEmail        string
+	userPassword     string
+	userLastname     string
+	userFirstname    string
+	userOrganization string
 )
 
 func init() {
@@ -22,6 +24,7 @@ func init() {
 	userCmd.Flags().StringVarP(&userFirstname, "firstname", "f", "", "user firstname")
 	userCmd.Flags().StringVarP(&userEmail, "email", "e", "", "user email")
 	userCmd.Flags().StringVarP(&userPassword, "password", "p", "", "user password")
+	userCmd.Flags().StringVarP(&userOrganization, "organization", "o", "default", "slug of the user organization")
 
 	userCmd.MarkFlagRequired("lastname")
 	userCmd.MarkFlagRequired("firstname")
@@ -51,16 +54,36 @@ var userCmd = &cobra.Command{
 		}
 
 		// Initialize database
-		db, err := initDatabase(config)
+		db, err := initDatabase(config, nil)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize storage
+		storage, err := initStorage(config)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		// Initialize repositories
+		repos, err := repositories.New(db)
+		if err != nil {
+			fmt.Printf("\nError: %v\n", err)
+			return
+		}
+
+		ctx, err := tenantContext(repos, userOrganization)
 		if err != nil {
 			fmt.Printf("\nError: %v\n", err)
 			return
 		}
 
 		// Call use case
-		userRepo := sqlx_mysql.NewUserMysqlRepository(db)
-		userUseCase := usecases.NewUser(userRepo)
-		res, errRes := userUseCase.Create(user)
+		auditService := services.NewAudit(repos.Audit)
+		userUseCase := usecases.NewUser(repos.User, auditService, services.NewOutbox(repos.Outbox), storage, repos.Tx)
+		res, errRes := userUseCase.Create(ctx, user)
 		if errRes != nil {
 			fmt.Printf("\nError: %v (%v)\n", errRes.Message, errRes.Details)
 			return
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DocPath is the OpenAPI documentation of the API, relative to the root of the project
+var DocPath = filepath.Join("assets", "docs", "doc_api_v1.yml")
+
+// mergeDoc inserts the paths, parameters and schemas of a resource in the OpenAPI documentation:
+// the paths at the end of paths, the parameters and schemas at the end of their section of components.
+// The merged documentation is parsed to reject a resource already documented (duplicate keys).
+func mergeDoc(doc []byte, r Resource) ([]byte, error) {
+	blocks := make(map[string][]string, 3)
+	for _, name := range []string{"paths", "parameters", "schemas"} {
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, "openapi."+name, r); err != nil {
+			return nil, err
+		}
+		blocks[name] = strings.Split(strings.Trim(buf.String(), "\n"), "\n")
+	}
+
+	lines := strings.Split(strings.TrimRight(string(doc), "\n"), "\n")
+
+	components := slices.Index(lines, "components:")
+	if components < 0 {
+		return nil, fmt.Errorf("%s: no components", DocPath)
+	}
+	parameters, err := sectionEnd(lines, components, "  parameters:")
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := sectionEnd(lines, components, "  schemas:")
+	if err != nil {
+		return nil, err
+	}
+
+	// Insertions from the end of the file to keep the previous indexes
+	type insertion struct {
+		at    int
+		lines []string
+	}
+	insertions := []insertion{
+		{schemas, blocks["schemas"]},
+		{parameters, blocks["parameters"]},
+		{components, append(blocks["paths"], "")},
+	}
+	slices.SortStableFunc(insertions, func(a, b insertion) int { return b.at - a.at })
+	for _, i := range insertions {
+		lines = slices.Insert(lines, i.at, i.lines...)
+	}
+
+	merged := []byte(strings.Join(lines, "\n") + "\n")
+
+	var content map[string]any
+	if err := yaml.Unmarshal(merged, &content); err != nil {
+		return nil, fmt.Errorf("%s: %w", DocPath, err)
+	}
+
+	return merged, nil
+}
+
+// sectionEnd returns the index of the line following a section of components
+func sectionEnd(lines []string, components int, section string) (int, error) {
+	start := slices.Index(lines[components:], section)
+	if start < 0 {
+		return 0, fmt.Errorf("%s: no components.%s", DocPath, strings.TrimSuffix(strings.TrimSpace(section), ":"))
+	}
+
+	end := components + start + 1
+	for end < len(lines) && (lines[end] == "" || strings.HasPrefix(lines[end], "   ")) {
+		end++
+	}
+	for lines[end-1] == "" {
+		end--
+	}
+
+	return end, nil
+}
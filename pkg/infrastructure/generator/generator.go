@@ -0,0 +1,211 @@
+// Package generator scaffolds the layers of a new CRUD resource from Go templates:
+// entity, ID value object, requests, response, repository interface, sqlx adapters, use case and its tests,
+// handler and migrations. Its paths and schemas are added to the OpenAPI documentation.
+package generator
+
+import (
+	"bytes"
+	"chi_boilerplate/pkg/adapters/db"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+	"title": func(s string) string { return strings.ToUpper(s[:1]) + s[1:] },
+}).ParseFS(templatesFS, "templates/*.tmpl"))
+
+// dialect of an sqlx adapter and its migrations
+type dialect struct {
+	Driver  string // Database driver (see db.MigrationsDir)
+	Package string // Suffix of the sqlx_* package
+	Name    string // Name in the repositories ("Mysql" in NewUserMysqlRepository)
+	Conn    string // Connection type of the db package
+	quote   string
+	version string // Type of version
+	dates   string // Type of created_at and updated_at
+	column  func(Field) string
+}
+
+var dialects = []dialect{
+	{db.DriverMySQL, "mysql", "Mysql", "SqlxMySQL", "`", "int unsigned", "datetime(3)", func(f Field) string { return f.MySQL }},
+	{db.DriverPostgres, "postgres", "Postgres", "SqlxPostgres", `"`, "bigint", "timestamp(3)", func(f Field) string { return f.Postgres }},
+	{db.DriverSQLite, "sqlite", "SQLite", "SqlxSQLite", `"`, "integer", "datetime", func(f Field) string { return f.SQLite }},
+}
+
+// dialectData is the data of the templates of a dialect
+type dialectData struct {
+	Resource
+	Dialect dialect
+}
+
+// Columns returns the aligned column definitions of the table
+func (d dialectData) Columns() []string {
+	columns := [][2]string{{"id", "varchar(36)"}, {"organization_id", "varchar(36)"}}
+	for _, f := range d.Fields {
+		columns = append(columns, [2]string{f.Column, d.Dialect.column(f)})
+	}
+	columns = append(columns,
+		[2]string{"version", d.Dialect.version},
+		[2]string{"created_at", d.Dialect.dates},
+		[2]string{"updated_at", d.Dialect.dates},
+	)
+
+	var nameWidth, typeWidth int
+	for _, c := range columns {
+		nameWidth = max(nameWidth, len(c[0])+2)
+		typeWidth = max(typeWidth, len(c[1]))
+	}
+
+	lines := make([]string, 0, len(columns))
+	for _, c := range columns {
+		line := fmt.Sprintf("%-*s %-*s NOT NULL", nameWidth, d.Dialect.quote+c[0]+d.Dialect.quote, typeWidth, c[1])
+		if c[0] == "version" {
+			// Set by the database at creation (see sqlx_crud.Table.Versioned)
+			line += " DEFAULT 1"
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// file is a generated file
+type file struct {
+	path     string
+	template string
+	data     any
+}
+
+// files returns the files of a resource, relative to the root of the project
+func files(r Resource) ([]file, error) {
+	s := r.Snake()
+	list := []file{
+		{filepath.Join("pkg", "domain", "entities", s+".entity.go"), "entity.go.tmpl", r},
+		{filepath.Join("pkg", "domain", "value_objects", s+"_id.go"), "value_object.go.tmpl", r},
+		{filepath.Join("pkg", "domain", "requests", s+".request.go"), "request.go.tmpl", r},
+		{filepath.Join("pkg", "domain", "responses", s+".response.go"), "response.go.tmpl", r},
+		{filepath.Join("pkg", "domain", "repositories", s+".repository.go"), "repository.go.tmpl", r},
+		{filepath.Join("pkg", "domain", "usecases", s+".usecase.go"), "usecase.go.tmpl", r},
+		{filepath.Join("pkg", "domain", "usecases", s+".usecase_test.go"), "usecase_test.go.tmpl", r},
+		{filepath.Join("pkg", "infrastructure", "chi_router", "handlers", "api", s+".handler.go"), "handler.go.tmpl", r},
+	}
+
+	migration := r.Version + "_add_" + r.Table() + "_table"
+	for _, d := range dialects {
+		data := dialectData{r, d}
+		list = append(list, file{
+			filepath.Join("pkg", "adapters", "repositories", "sqlx_"+d.Package, s+"."+d.Package+".go"), "sqlx.go.tmpl", data,
+		})
+
+		dir, err := db.MigrationsDir(d.Driver)
+		if err != nil {
+			return nil, err
+		}
+		prefix := "table"
+		if d.Driver == db.DriverMySQL {
+			prefix = "mysql"
+		}
+		for _, direction := range []string{"up", "down"} {
+			list = append(list, file{
+				filepath.Join("migrations", dir, migration+"."+direction+".sql"), prefix + "." + direction + ".sql.tmpl", data,
+			})
+		}
+	}
+
+	return list, nil
+}
+
+// Module returns the name of the Go module of the project in the root directory
+func Module(root string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+
+	return "", errors.New("no module in go.mod")
+}
+
+// Generate writes the files of the resource in the root directory of the project and returns their paths,
+// then adds the resource to the OpenAPI documentation (DocPath).
+// Nothing is written if one of the files already exists or if the resource is already documented.
+func Generate(root string, r Resource) ([]string, error) {
+	list, err := files(r)
+	if err != nil {
+		return nil, err
+	}
+
+	docPath := filepath.Join(root, DocPath)
+	doc, err := os.ReadFile(docPath)
+	if err != nil {
+		return nil, err
+	}
+	if doc, err = mergeDoc(doc, r); err != nil {
+		return nil, err
+	}
+
+	contents := make([][]byte, len(list))
+	for i, f := range list {
+		p := filepath.Join(root, f.path)
+		if _, err := os.Stat(p); err == nil {
+			return nil, fmt.Errorf("%s already exists", p)
+		}
+
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, f.template, f.data); err != nil {
+			return nil, err
+		}
+
+		contents[i] = buf.Bytes()
+		if filepath.Ext(f.path) == ".go" {
+			if contents[i], err = format.Source(contents[i]); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+		}
+	}
+
+	created := make([]string, 0, len(list))
+	for i, f := range list {
+		p := filepath.Join(root, f.path)
+		if err := writeFile(p, contents[i]); err != nil {
+			return created, err
+		}
+		created = append(created, p)
+	}
+
+	return created, os.WriteFile(docPath, doc, 0o644)
+}
+
+// writeFile creates a new file and its directory, it fails if the file exists
+func writeFile(p string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,219 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		name, snake, table, plural, variable, route, label string
+	}{
+		{"Product", "product", "products", "Products", "product", "products", "Product"},
+		{"ProductCategory", "product_category", "product_categories", "ProductCategories", "productCategory", "product-categories", "Product category"},
+		{"Box", "box", "boxes", "Boxes", "box", "boxes", "Box"},
+		{"Day", "day", "days", "Days", "day", "days", "Day"},
+		{"HTTPServer", "http_server", "http_servers", "HTTPServers", "httpServer", "http-servers", "Http server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Resource{Name: tt.name}
+
+			assert.Equal(t, tt.snake, r.Snake())
+			assert.Equal(t, tt.table, r.Table())
+			assert.Equal(t, tt.plural, r.Plural())
+			assert.Equal(t, tt.variable, r.Var())
+			assert.Equal(t, tt.route, r.Route())
+			assert.Equal(t, tt.label, r.Label())
+		})
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("name:string, unit_price:float,product_id:string")
+	assert.Nil(t, err)
+	assert.Len(t, fields, 3)
+	assert.Equal(t, "UnitPrice", fields[1].GoName)
+	assert.Equal(t, "float64", fields[1].Go)
+	assert.Equal(t, "ProductID", fields[2].GoName)
+	assert.Equal(t, `"name 2"`, fields[0].Sample(2))
+
+	for _, spec := range []string{"", "name", "Name:string", "name:uuid", "id:string", "name:string,name:text"} {
+		_, err := ParseFields(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestNewResource(t *testing.T) {
+	now := time.Date(2025, 2, 24, 9, 0, 0, 0, time.UTC)
+
+	r, err := NewResource("app", "Product", "name:string", now)
+	assert.Nil(t, err)
+	assert.Equal(t, "20250224090000", r.Version)
+
+	_, err = NewResource("app", "product", "name:string", now)
+	assert.NotNil(t, err)
+}
+
+func TestModule(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	module, err := Module(root)
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com/app", module)
+
+	_, err = Module(t.TempDir())
+	assert.NotNil(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+	copyFile(t, filepath.Join("..", "..", "..", DocPath), filepath.Join(root, DocPath))
+
+	r, err := NewResource("app", "Product", "name:string,price:int,available_at:time", time.Date(2025, 2, 24, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Generate(root, r)
+	assert.Nil(t, err)
+	assert.Len(t, files, 17)
+	assert.Contains(t, files, filepath.Join(root, "pkg", "adapters", "repositories", "sqlx_postgres", "product.postgres.go"))
+	assert.Contains(t, files, filepath.Join(root, "migrations", "sqlite", "20250224090000_add_products_table.up.sql"))
+
+	entity, err := os.ReadFile(filepath.Join(root, "pkg", "domain", "entities", "product.entity.go"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(entity), "Price       int64     `json:\"price\" xml:\"price\" form:\"price\" db:\"price\"`")
+
+	migration, err := os.ReadFile(filepath.Join(root, "migrations", "20250224090000_add_products_table.up.sql"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(migration), "`available_at`    datetime     NOT NULL,")
+
+	doc, err := os.ReadFile(filepath.Join(root, DocPath))
+	assert.Nil(t, err)
+	assert.Contains(t, string(doc), "\n  /products:\n")
+	assert.Contains(t, string(doc), "\n  /products/{id}:\n")
+	assert.Contains(t, string(doc), "\n    ProductId:\n")
+	assert.Contains(t, string(doc), "\n    ProductHttpResponse:\n")
+	assert.True(t, strings.HasSuffix(string(doc), "$ref: '#/components/schemas/ProductHttpResponse'\n"))
+
+	// Existing resource: nothing is overwritten
+	if err := os.WriteFile(files[0], []byte("package entities\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Generate(root, r)
+	assert.NotNil(t, err)
+
+	entity, err = os.ReadFile(files[0])
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(entity), "package entities\n"))
+
+	// Documented resource: the documentation is not modified
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = Generate(root, r)
+	assert.NotNil(t, err)
+
+	unchanged, err := os.ReadFile(filepath.Join(root, DocPath))
+	assert.Nil(t, err)
+	assert.Equal(t, doc, unchanged)
+	assert.NoFileExists(t, files[0])
+}
+
+// TestGenerateBuild generates a resource with every type of field in a copy of the module,
+// which must build and pass go vet and the generated tests
+func TestGenerateBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a copy of the module")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	source, err := filepath.Abs(filepath.Join("..", "..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	err = filepath.WalkDir(source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(source, p)
+		if err != nil {
+			return err
+		}
+		copyFile(t, p, filepath.Join(root, rel))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	module, err := Module(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := make([]string, 0, len(fieldTypes))
+	for _, name := range FieldTypes() {
+		fields = append(fields, "field_"+name+":"+name)
+	}
+	r, err := NewResource(module, "ProductCategory", strings.Join(fields, ","), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(root, r); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"build", "./..."},
+		{"vet", "./..."},
+		{"test", "-run", "TestProductCategory", "./pkg/domain/usecases/"},
+	} {
+		cmd := exec.Command(goBin, args...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
+
+// copyFile copies the file src to dst and creates the directory of dst
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	content, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,251 @@
+package generator
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	// resourceNameRegexp matches the resource names in CamelCase ("Product", "ProductCategory")
+	resourceNameRegexp = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+
+	// fieldNameRegexp matches the field names in snake case ("name", "unit_price")
+	fieldNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+	// reservedColumns are the columns added to every resource or managed by the generic repository
+	reservedColumns = []string{"id", "organization_id", "created_at", "updated_at", "deleted_at", "version"}
+
+	// initialisms are the words written in upper case in Go names
+	initialisms = []string{"id", "ip", "url", "uri", "uuid", "api", "http", "json", "sql"}
+)
+
+// fieldType describes how a type of field is declared in each layer
+type fieldType struct {
+	Go            string // Go type
+	Validate      string // validate tag
+	MySQL         string // MySQL column type
+	Postgres      string // PostgreSQL column type
+	SQLite        string // SQLite column type
+	OpenAPIType   string
+	OpenAPIFormat string
+	samples       [2]string // Go values used by the tests
+}
+
+// fieldTypes are the types of field which can be generated
+var fieldTypes = map[string]fieldType{
+	"string": {"string", "required,max=255", "varchar(255)", "varchar(255)", "varchar(255)", "string", "", [2]string{`"%s 1"`, `"%s 2"`}},
+	"text":   {"string", "required", "text", "text", "text", "string", "", [2]string{`"%s 1"`, `"%s 2"`}},
+	"int":    {"int64", "", "bigint", "bigint", "integer", "integer", "int64", [2]string{"int64(1)", "int64(2)"}},
+	"float":  {"float64", "", "double", "double precision", "real", "number", "double", [2]string{"1.5", "2.5"}},
+	"bool":   {"bool", "", "tinyint(1)", "boolean", "boolean", "boolean", "", [2]string{"true", "false"}},
+	"time": {"time.Time", "", "datetime", "timestamp", "datetime", "string", "date-time", [2]string{
+		"time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)",
+		"time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)",
+	}},
+}
+
+// FieldTypes returns the names of the types of field which can be generated
+func FieldTypes() []string {
+	types := make([]string, 0, len(fieldTypes))
+	for name := range fieldTypes {
+		types = append(types, name)
+	}
+	slices.Sort(types)
+
+	return types
+}
+
+// Field is a field of a resource
+type Field struct {
+	fieldType
+
+	Column string // Column, JSON and query name (snake case)
+	GoName string // Go field name (CamelCase)
+}
+
+// Sample returns a Go value of the field for the tests (n is 1 or 2)
+func (f Field) Sample(n int) string {
+	sample := f.samples[n-1]
+	if strings.Contains(sample, "%s") {
+		return fmt.Sprintf(sample, f.Column)
+	}
+
+	return sample
+}
+
+// ParseFields parses a list of fields: "name:string,price:int"
+func ParseFields(spec string) ([]Field, error) {
+	var fields []Field
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, typeName, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q: the format is name:type", item)
+		}
+		if !fieldNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid field name %q: only lowercase letters, digits and underscores are allowed", name)
+		}
+		if slices.Contains(reservedColumns, name) {
+			return nil, fmt.Errorf("the field %q is added to every resource", name)
+		}
+		if slices.ContainsFunc(fields, func(f Field) bool { return f.Column == name }) {
+			return nil, fmt.Errorf("duplicate field %q", name)
+		}
+
+		t, ok := fieldTypes[typeName]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %q of field %q (types: %s)", typeName, name, strings.Join(FieldTypes(), ", "))
+		}
+
+		fields = append(fields, Field{fieldType: t, Column: name, GoName: camel(name)})
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("at least one field is required")
+	}
+
+	return fields, nil
+}
+
+// Resource is the description of a generated resource
+type Resource struct {
+	Module  string // Go module of the project
+	Name    string // CamelCase singular name ("ProductCategory")
+	Fields  []Field
+	Version string // Version of the migrations
+}
+
+// NewResource returns a new Resource whose migrations are created at t
+func NewResource(module, name, fields string, t time.Time) (Resource, error) {
+	if !resourceNameRegexp.MatchString(name) {
+		return Resource{}, fmt.Errorf("invalid resource name %q: it must be in CamelCase (e.g. Product)", name)
+	}
+
+	list, err := ParseFields(fields)
+	if err != nil {
+		return Resource{}, err
+	}
+
+	return Resource{
+		Module:  module,
+		Name:    name,
+		Fields:  list,
+		Version: t.UTC().Format(db.MigrationVersionFormat),
+	}, nil
+}
+
+// Snake returns the name in snake case ("product_category"), used for the files
+func (r Resource) Snake() string {
+	return snake(r.Name)
+}
+
+// Table returns the name of the table ("product_categories")
+func (r Resource) Table() string {
+	return plural(r.Snake())
+}
+
+// Plural returns the plural name in CamelCase ("ProductCategories")
+func (r Resource) Plural() string {
+	return camel(r.Table())
+}
+
+// Var returns the name in lower camel case ("productCategory"), used for the variables
+func (r Resource) Var() string {
+	return lowerCamel(r.Snake())
+}
+
+// Vars returns the plural name in lower camel case ("productCategories")
+func (r Resource) Vars() string {
+	return lowerCamel(r.Table())
+}
+
+// Route returns the path of the routes ("product-categories")
+func (r Resource) Route() string {
+	return strings.ReplaceAll(r.Table(), "_", "-")
+}
+
+// Label returns the name in a sentence ("Product category")
+func (r Resource) Label() string {
+	label := strings.ReplaceAll(r.Snake(), "_", " ")
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// Labels returns the plural name in a sentence ("product categories")
+func (r Resource) Labels() string {
+	return strings.ReplaceAll(r.Table(), "_", " ")
+}
+
+// HasType checks if a field has the Go type t
+func (r Resource) HasType(t string) bool {
+	return slices.ContainsFunc(r.Fields, func(f Field) bool { return f.Go == t })
+}
+
+// HasRequired checks if a field is required
+func (r Resource) HasRequired() bool {
+	return slices.ContainsFunc(r.Fields, func(f Field) bool { return strings.Contains(f.Validate, "required") })
+}
+
+// snake converts a CamelCase name to snake case: "HTTPServerName" => "http_server_name"
+func snake(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previousLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// camel converts a snake case name to CamelCase with the Go initialisms: "product_id" => "ProductID"
+func camel(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		if slices.Contains(initialisms, word) {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+
+	return b.String()
+}
+
+// lowerCamel converts a snake case name to lower camel case: "http_server" => "httpServer"
+func lowerCamel(name string) string {
+	first, rest, _ := strings.Cut(name, "_")
+	return first + camel(rest)
+}
+
+// plural returns the plural of a snake case name (English rules of the regular nouns)
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "z"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
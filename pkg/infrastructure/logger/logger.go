@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/fabienbellanger/goutils"
 )
@@ -63,3 +66,43 @@ func getLoggerOutputs(logOutputs []string, appName, filePath string) (outputs []
 	}
 	return
 }
+
+// PurgeRotatedFiles deletes the rotated log files of the application (ex: "api.log.1", "api.log.2.gz", "api.log-20250301")
+// modified before a date and returns their number. The current log file is kept.
+func PurgeRotatedFiles(logPath, appName string, before time.Time) (int, error) {
+	if appName == "" {
+		return 0, errors.New("no APP_NAME variable defined")
+	}
+
+	entries, err := os.ReadDir(path.Clean(logPath))
+	if err != nil {
+		return 0, err
+	}
+
+	prefix := appName + ".log"
+	deleted := 0
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) || (name[len(prefix)] != '.' && name[len(prefix)] != '-') {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !info.ModTime().Before(before) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(logPath, name)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, errors.Join(errs...)
+}
@@ -1,7 +1,10 @@
 package logger
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -43,3 +46,34 @@ func TestLogOutputsWithMoreThanOneOutput(t *testing.T) {
 	assert.Equal(t, []string{"/tmp/go-url-shortener.log", "stdout"}, gottenOutputs, "with stdout")
 	assert.Nil(t, err)
 }
+
+func TestPurgeRotatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().AddDate(0, 0, -40)
+	for _, name := range []string{"api.log", "api.log.1", "api.log.2.gz", "api.log-20250301", "api.logs", "other.log.1", "api.log.recent"} {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if name != "api.log.recent" {
+			if err := os.Chtimes(p, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	deleted, err := PurgeRotatedFiles(dir, "api", time.Now().AddDate(0, 0, -30))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, deleted)
+
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"api.log", "api.log.recent", "api.logs", "other.log.1"}, names)
+
+	_, err = PurgeRotatedFiles(dir, "", time.Now())
+	assert.NotNil(t, err)
+}
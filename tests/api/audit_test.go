@@ -0,0 +1,179 @@
+package api
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/tests/helpers"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditGetAll(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+
+	useCases := []helpers.Test{
+		{
+			Description: "Get all audit events without events",
+			Route:       "/api/v1/audit",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 200,
+			ExpectedBody: `{"data":[],"total":0}`,
+		},
+		{
+			Description: "User creation",
+			Route:       "/api/v1/users",
+			Method:      "POST",
+			Body: strings.NewReader(helpers.JsonToString(requests.UserCreation{
+				Email:     "audit@gmail.com",
+				Password:  "11111111",
+				Lastname:  "Test",
+				Firstname: "Audit",
+			})),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Get audit events filtered by actor and action",
+			Route:       "/api/v1/audit?action=delete&actor_id=" + helpers.UserID,
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 200,
+			ExpectedBody: `{"data":[],"total":0}`,
+		},
+		{
+			Description: "Get audit events with invalid action",
+			Route:       "/api/v1/audit?action=read",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 400,
+			ExpectedBody: `{"code":400,"message":"Invalid request data","details":[{"FailedField":"Action","Tag":"oneof","Value":"create update delete erase"}]}`,
+		},
+		{
+			Description: "Get audit events sorted by a column which cannot be sorted",
+			Route:       "/api/v1/audit?s=%2Bchanges",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 400,
+			ExpectedBody: `{"code":400,"message":"Invalid request data","details":"invalid sort field: changes"}`,
+		},
+		{
+			Description: "Get audit events sorted by a subquery",
+			Route:       "/api/v1/audit?s=-(SELECT%20SLEEP(5))",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 400,
+		},
+		{
+			Description: "Get audit events sorted by action",
+			Route:       "/api/v1/audit?s=%2Baction,-created_at",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description:  "Get audit events without token",
+			Route:        "/api/v1/audit",
+			Method:       "GET",
+			CheckCode:    true,
+			ExpectedCode: 401,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
+
+func TestAuditUserUpdate(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+
+	var requestID string
+
+	useCases := []helpers.Test{
+		{
+			Description: "User update",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "PUT",
+			Body: strings.NewReader(helpers.JsonToString(requests.UserUpdate{
+				ID:        helpers.UserID,
+				Email:     helpers.UserEmail,
+				Password:  helpers.UserPassword,
+				Lastname:  "Audited",
+				Firstname: "Test",
+			})),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+				{Key: "X-Real-IP", Value: "203.0.113.7"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+			Check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				requestID = res.Header().Get("X-Request-Id")
+				assert.NotEmpty(t, requestID)
+			},
+		},
+		{
+			Description: "Get the audit event of the update",
+			Route:       "/api/v1/audit?action=update&target_id=" + helpers.UserID,
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+			Check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				var list struct {
+					Data  []responses.AuditEventsListRepository `json:"data"`
+					Total int                                   `json:"total"`
+				}
+				if !assert.Nil(t, json.Unmarshal(res.Body.Bytes(), &list)) || !assert.Len(t, list.Data, 1) {
+					return
+				}
+
+				event := list.Data[0]
+				assert.Equal(t, helpers.UserID, event.ActorID)
+				assert.Equal(t, "update", event.Action)
+				assert.Equal(t, "user", event.TargetType)
+				assert.Equal(t, helpers.UserID, event.TargetID)
+				assert.Equal(t, requestID, event.RequestID)
+				assert.Equal(t, "203.0.113.7", event.IP)
+				assert.JSONEq(t, `{"lastname":{"before":"Test","after":"Audited"}}`, string(event.Changes))
+			},
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories"
+	"chi_boilerplate/pkg/adapters/storage"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/pkg/domain/usecases"
+	"chi_boilerplate/tests/helpers"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivacyDataExport(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+
+	useCases := []helpers.Test{
+		{
+			Description: "User update",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "PUT",
+			Body: strings.NewReader(helpers.JsonToString(requests.UserUpdate{
+				ID:        helpers.UserID,
+				Email:     helpers.UserEmail,
+				Password:  helpers.UserPassword,
+				Lastname:  "Exported",
+				Firstname: "Test",
+			})),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description:  "Data export without token",
+			Route:        "/api/v1/me/data-export",
+			Method:       "GET",
+			CheckCode:    true,
+			ExpectedCode: 401,
+		},
+		{
+			Description: "Data export as ZIP",
+			Route:       "/api/v1/me/data-export",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Data export as JSON",
+			Route:       "/api/v1/me/data-export?format=json",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+			Check: func(t *testing.T, res *httptest.ResponseRecorder) {
+				var export responses.UserDataExport
+				if !assert.Nil(t, json.Unmarshal(res.Body.Bytes(), &export)) {
+					return
+				}
+
+				assert.Equal(t, helpers.UserID, export.Profile.ID)
+				assert.Equal(t, helpers.UserEmail, export.Profile.Email)
+				assert.Equal(t, "Exported", export.Profile.Lastname)
+				if assert.Len(t, export.AuditEvents, 1) {
+					assert.Equal(t, "update", export.AuditEvents[0].Action)
+					assert.Equal(t, helpers.UserID, export.AuditEvents[0].TargetID)
+					assert.JSONEq(t, `{"lastname":{"before":"Test","after":"Exported"}}`, string(export.AuditEvents[0].Changes))
+				}
+			},
+		},
+		{
+			Description: "Data export with invalid format",
+			Route:       "/api/v1/me/data-export?format=xml",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 400,
+			ExpectedBody: `{"code":400,"message":"Invalid export format"}`,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
+
+func TestPrivacyErasure(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+
+	const (
+		erasedID    = "f47ac10b-58cc-0372-8562-0b8e853961c1"
+		erasedEmail = "erasable@test.com"
+		erasedIP    = "198.51.100.23"
+	)
+	token, err := tdb.CreateUser(erasedID, erasedEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useCases := []helpers.Test{
+		{
+			Description: "User update by the user",
+			Route:       "/api/v1/users/" + erasedID,
+			Method:      "PUT",
+			Body: strings.NewReader(helpers.JsonToString(requests.UserUpdate{
+				ID:        erasedID,
+				Email:     erasedEmail,
+				Password:  helpers.UserPassword,
+				Lastname:  "Erasable",
+				Firstname: "Zebulon",
+			})),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + token},
+				{Key: "If-Match", Value: "*"},
+				{Key: "X-Real-IP", Value: erasedIP},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "User deletion",
+			Route:       "/api/v1/users/" + erasedID,
+			Method:      "DELETE",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 204,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+
+	repos, err := repositories.New(tdb.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := storage.NewLocalStorage(t.TempDir(), "http://localhost/storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditService := services.NewAudit(repos.Audit)
+	userUseCase := usecases.NewUser(repos.User, auditService, services.NewOutbox(repos.Outbox), st, repos.Tx)
+	privacyUseCase := usecases.NewPrivacy(userUseCase, repos.User, repos.Audit, repos.Outbox, auditService, st, repos.Tx)
+
+	res, errRes := privacyUseCase.EraseDeleted(helpers.TenantContext(), requests.UsersErasure{RetentionDays: 0})
+	if !assert.Nil(t, errRes) {
+		return
+	}
+	assert.Equal(t, 1, res.Erased)
+
+	// The audit trail is kept without the personal data
+	events, err := repos.Audit.GetByUser(helpers.TenantContext(), erasedID)
+	assert.Nil(t, err)
+	assert.Len(t, events, 3)
+
+	dump, err := tdb.Dump()
+	if errors.Is(err, helpers.ErrNoSQL) {
+		t.Skip("no tables with the in-memory repositories")
+	}
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Contains(t, dump, erasedID)
+	for _, pii := range []string{erasedEmail, "Erasable", "Zebulon", erasedIP} {
+		assert.NotContains(t, dump, pii)
+	}
+}
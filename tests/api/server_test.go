@@ -0,0 +1,181 @@
+package api
+
+import (
+	"chi_boilerplate/pkg/adapters/storage"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/infrastructure/chi_router"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"chi_boilerplate/tests/helpers"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer returns a server of an in-memory database and a listener on a free port
+func newTestServer(t *testing.T, shutdownTimeout time.Duration) (chi_router.ChiServer, net.Listener) {
+	tdb := helpers.InitMemory("../../.env")
+	t.Cleanup(func() { tdb.Drop() })
+
+	l, _ := logger.NewZapLogger()
+	st, _ := storage.NewLocalStorage(t.TempDir(), "http://localhost/storage")
+	s := chi_router.NewChiServer("", "", tdb.DB, st, l)
+	s.Timeouts = chi_router.Timeouts{ReadHeader: time.Second, Shutdown: shutdownTimeout}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, ln
+}
+
+// TestServerGracefulShutdown checks that the in-flight requests are finished when the server stops
+func TestServerGracefulShutdown(t *testing.T) {
+	s, ln := newTestServer(t, 5*time.Second)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errServe := make(chan error, 1)
+	go func() { errServe <- s.Serve(ctx, ln, handler) }()
+
+	body := make(chan string, 1)
+	go func() {
+		res, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer res.Body.Close()
+		b, _ := io.ReadAll(res.Body)
+		body <- string(b)
+	}()
+
+	<-started
+	cancel()
+
+	// New connections are refused while the in-flight request is drained
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err == nil {
+			conn.Close()
+		}
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	close(release)
+	assert.Equal(t, "done", <-body)
+	assert.Nil(t, <-errServe)
+}
+
+// TestServerShutdownTimeout checks that the server stops after the shutdown timeout
+func TestServerShutdownTimeout(t *testing.T) {
+	s, ln := newTestServer(t, 100*time.Millisecond)
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errServe := make(chan error, 1)
+	go func() { errServe <- s.Serve(ctx, ln, handler) }()
+
+	go func() {
+		res, err := http.Get("http://" + ln.Addr().String())
+		if err == nil {
+			res.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-errServe:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server is not stopped after the shutdown timeout")
+	}
+}
+
+// TestServerReadHeaderTimeout checks that the connections not sending their headers are closed (slowloris)
+func TestServerReadHeaderTimeout(t *testing.T) {
+	s, ln := newTestServer(t, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = s.Serve(ctx, ln, http.NotFoundHandler()) }()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n"))
+	assert.Nil(t, err)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	start := time.Now()
+	_, _ = io.ReadAll(conn)
+	assert.Less(t, time.Since(start), 3*time.Second, "the connection is closed by the server")
+}
+
+// TestServerReadYourWrites checks that a write of a client routes its next requests to the primary during the window
+func TestServerReadYourWrites(t *testing.T) {
+	tdb := helpers.InitSQLite("../../.env", "../../migrations")
+	defer tdb.Drop()
+
+	l, _ := logger.NewZapLogger()
+	st, _ := storage.NewLocalStorage(t.TempDir(), "http://localhost/storage")
+	s := chi_router.NewChiServer("", "", tdb.DB, st, l)
+	s.ReadYourWritesWindow = 5 * time.Second
+	app, err := s.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(method, body string) *http.Response {
+		req := httptest.NewRequest(method, "/api/v1/users/"+helpers.UserID, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tdb.Token)
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		req.Header.Set("If-Match", "*")
+		res := httptest.NewRecorder()
+		app.ServeHTTP(res, req)
+
+		return res.Result()
+	}
+
+	// Reads do not open the window
+	res := request(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Empty(t, res.Cookies())
+
+	res = request(http.MethodPut, helpers.JsonToString(requests.UserUpdate{
+		ID:        helpers.UserID,
+		Email:     helpers.UserEmail,
+		Password:  helpers.UserPassword,
+		Lastname:  "Test",
+		Firstname: "Written",
+	}))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	if assert.Len(t, res.Cookies(), 1) {
+		cookie := res.Cookies()[0]
+		assert.Equal(t, "read_your_writes", cookie.Name)
+		assert.Equal(t, 5, cookie.MaxAge)
+		assert.True(t, cookie.HttpOnly)
+	}
+}
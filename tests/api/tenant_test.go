@@ -0,0 +1,184 @@
+package api
+
+import (
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/tests/helpers"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	acmeOrganizationID = "5b1e7c7e-3f7a-4c8e-9d4b-2f6a1c0e8d21"
+	acmeUserID         = "9a2a4a6e-54a5-4b7f-a2b1-0b8e853961b3"
+	acmeUserEmail      = "acme@test.com"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+	acmeToken, err := tdb.CreateOrganization(acmeOrganizationID, "acme", acmeUserID, acmeUserEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useCases := []helpers.Test{
+		{
+			Description: "Get a user of another organization",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + acmeToken},
+			},
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description: "Suspend a user of another organization",
+			Route:       "/api/v1/users/" + helpers.UserID + "/suspend",
+			Method:      "POST",
+			Body:        strings.NewReader(`{"reason": "Spam"}`),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + acmeToken},
+			},
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description: "The header cannot change the organization of the token",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + acmeToken},
+				{Key: "X-Organization-Id", Value: "00000000-0000-0000-0000-000000000001"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description: "Get a user of the same organization",
+			Route:       "/api/v1/users/" + acmeUserID,
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + acmeToken},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "The user of the default organization is still active",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
+
+func TestTenantLogin(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+	if _, err := tdb.CreateOrganization(acmeOrganizationID, "acme", acmeUserID, acmeUserEmail); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set("TENANT_DOMAIN", "example.com")
+	defer viper.Set("TENANT_DOMAIN", "")
+
+	login := func(email string) *strings.Reader {
+		return strings.NewReader(helpers.JsonToString(requests.GetToken{Email: email, Password: helpers.UserPassword}))
+	}
+
+	useCases := []helpers.Test{
+		{
+			Description: "Login in the default organization",
+			Route:       "/api/v1/token",
+			Method:      "POST",
+			Body:        login(helpers.UserEmail),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Login of a user of another organization",
+			Route:       "/api/v1/token",
+			Method:      "POST",
+			Body:        login(acmeUserEmail),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description: "Login with the organization header",
+			Route:       "/api/v1/token",
+			Method:      "POST",
+			Body:        login(acmeUserEmail),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "X-Organization-Id", Value: acmeOrganizationID},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Login with the organization subdomain",
+			Route:       "/api/v1/token",
+			Method:      "POST",
+			Body:        login(acmeUserEmail),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Host", Value: "acme.example.com:3002"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Login with an unknown organization subdomain",
+			Route:       "/api/v1/token",
+			Method:      "POST",
+			Body:        login(acmeUserEmail),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Host", Value: "unknown.example.com"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description: "Login with an unknown organization",
+			Route:       "/api/v1/token",
+			Method:      "POST",
+			Body:        login(acmeUserEmail),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "X-Organization-Id", Value: "f47ac10b-58cc-0372-8562-0b8e853961c1"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description: "Login with an invalid organization",
+			Route:       "/api/v1/token",
+			Method:      "POST",
+			Body:        login(acmeUserEmail),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "X-Organization-Id", Value: "acme"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 400,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
@@ -1,15 +1,24 @@
 package api
 
 import (
+	"bytes"
 	"chi_boilerplate/pkg/domain/requests"
 	"chi_boilerplate/tests/helpers"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	imagepng "image/png"
+	"mime/multipart"
 	"strings"
 	"testing"
 )
 
 func TestUserLogin(t *testing.T) {
-	tdb := helpers.InitMySQL("../../.env", "../../migrations")
+	tdb := helpers.Init("../../.env", "../../migrations")
 	defer tdb.Drop()
+	if err := tdb.Seed("../../fixtures/users.yaml"); err != nil {
+		t.Fatal(err)
+	}
 
 	useCases := []helpers.Test{
 		{
@@ -26,13 +35,27 @@ func TestUserLogin(t *testing.T) {
 			CheckCode:    true,
 			ExpectedCode: 200,
 		},
+		{
+			Description: "User login with a fixtures user",
+			Route:       "/api/v1/token",
+			Method:      "POST",
+			Body: strings.NewReader(helpers.JsonToString(requests.GetToken{
+				Email:    "john.doe@example.com",
+				Password: "john1234",
+			})),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
 	}
 
 	tdb.Execute(t, useCases, "../../templates")
 }
 
 func TestUserCreation(t *testing.T) {
-	tdb := helpers.InitMySQL("../../.env", "../../migrations")
+	tdb := helpers.Init("../../.env", "../../migrations")
 	defer tdb.Drop()
 
 	useCases := []helpers.Test{
@@ -91,13 +114,32 @@ func TestUserCreation(t *testing.T) {
 			ExpectedCode: 400,
 			ExpectedBody: `{"code":400,"message":"Invalid request data","details":[{"FailedField":"Email","Tag":"email","Value":""}]}`,
 		},
+		{
+			Description: "User creation with existing email",
+			Route:       "/api/v1/users",
+			Method:      "POST",
+			Body: strings.NewReader(helpers.JsonToString(requests.UserCreation{
+				Email:     helpers.UserEmail,
+				Password:  "11111111",
+				Lastname:  "Test",
+				Firstname: "Creation",
+			})),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 409,
+			ExpectedBody: `{"code":409,"message":"Email already exists","details":[{"FailedField":"Email","Tag":"unique","Value":""}]}`,
+		},
 	}
 
 	tdb.Execute(t, useCases, "../../templates")
 }
 
 func TestUserByID(t *testing.T) {
-	tdb := helpers.InitMySQL("../../.env", "../../migrations")
+	tdb := helpers.Init("../../.env", "../../migrations")
 	defer tdb.Drop()
 
 	useCases := []helpers.Test{
@@ -112,6 +154,18 @@ func TestUserByID(t *testing.T) {
 			CheckCode:    true,
 			ExpectedCode: 200,
 		},
+		{
+			Description: "Get user by ID with matching If-None-Match",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-None-Match", Value: `"1"`},
+			},
+			CheckCode:    true,
+			ExpectedCode: 304,
+		},
 		{
 			Description: "Get user by ID with unknown user ID",
 			Route:       "/api/v1/users/f47ac10b-58cc-0372-8562-0b8e853961b3",
@@ -129,10 +183,39 @@ func TestUserByID(t *testing.T) {
 }
 
 func TestUserDelete(t *testing.T) {
-	tdb := helpers.InitMySQL("../../.env", "../../migrations")
+	tdb := helpers.Init("../../.env", "../../migrations")
 	defer tdb.Drop()
 
+	// The token of the deleted user is rejected: the last requests are sent by another user
+	token, err := tdb.CreateUser("f47ac10b-58cc-0372-8562-0b8e853961a2", "delete@test.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	useCases := []helpers.Test{
+		{
+			Description: "Delete user without If-Match header",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "DELETE",
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 428,
+		},
+		{
+			Description: "Delete user with outdated If-Match header",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "DELETE",
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: `"2"`},
+			},
+			CheckCode:    true,
+			ExpectedCode: 412,
+		},
 		{
 			Description: "Delete user by ID",
 			Route:       "/api/v1/users/" + helpers.UserID,
@@ -140,17 +223,31 @@ func TestUserDelete(t *testing.T) {
 			Headers: []helpers.Header{
 				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
 				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: `"1"`},
 			},
 			CheckCode:    true,
 			ExpectedCode: 204,
 		},
 		{
-			Description: "Delete user with unknown user ID",
+			Description: "Delete user with the token of the deleted user",
 			Route:       "/api/v1/users/f47ac10b-58cc-0372-8562-0b8e853961b3",
 			Method:      "DELETE",
 			Headers: []helpers.Header{
 				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
 				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 401,
+		},
+		{
+			Description: "Delete user with unknown user ID",
+			Route:       "/api/v1/users/f47ac10b-58cc-0372-8562-0b8e853961b3",
+			Method:      "DELETE",
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + token},
+				{Key: "If-Match", Value: "*"},
 			},
 			CheckCode:    true,
 			ExpectedCode: 404,
@@ -161,7 +258,7 @@ func TestUserDelete(t *testing.T) {
 }
 
 func TestUserGetAll(t *testing.T) {
-	tdb := helpers.InitMySQL("../../.env", "../../migrations")
+	tdb := helpers.Init("../../.env", "../../migrations")
 	defer tdb.Drop()
 
 	useCases := []helpers.Test{
@@ -176,9 +273,415 @@ func TestUserGetAll(t *testing.T) {
 			CheckCode:    true,
 			ExpectedCode: 200,
 			CheckBody:    true,
-			ExpectedBody: `{"data":[{"id":"` + helpers.UserID + `","email":"` + helpers.UserEmail + `","lastname":"Test","firstname":"Test","created_at":"` + helpers.UserCreatedAt + `","updated_at":"` + helpers.UserUpdatedAt + `"}],"total":1}`,
+			ExpectedBody: `{"data":[{"id":"` + helpers.UserID + `","email":"` + helpers.UserEmail + `","lastname":"Test","firstname":"Test","status":"active","created_at":"` + helpers.UserCreatedAt + `","updated_at":"` + helpers.UserUpdatedAt + `"}],"total":1}`,
+		},
+		{
+			Description: "Get all users filtered by status",
+			Route:       "/api/v1/users/?status=suspended",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+			CheckBody:    true,
+			ExpectedBody: `{"data":null,"total":0}`,
+		},
+		{
+			Description: "Get all users with invalid status",
+			Route:       "/api/v1/users/?status=unknown",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 400,
+		},
+		{
+			Description: "Get all users sorted by a column which cannot be sorted",
+			Route:       "/api/v1/users/?s=%2Bpassword",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 400,
 		},
 	}
 
 	tdb.Execute(t, useCases, "../../templates")
 }
+
+func TestUserSearch(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+	if err := tdb.Seed("../../fixtures/users.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	acmeToken, err := tdb.CreateOrganization(acmeOrganizationID, "acme", acmeUserID, acmeUserEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useCases := []helpers.Test{
+		{
+			Description: "Search users",
+			Route:       "/api/v1/users/search?q=doe&l=1",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Search users without result",
+			Route:       "/api/v1/users/search?q=unknown",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+			CheckBody:    true,
+			ExpectedBody: `{"data":null}`,
+		},
+		{
+			Description: "Search the users of another organization",
+			Route:       "/api/v1/users/search?q=doe",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + acmeToken},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+			CheckBody:    true,
+			ExpectedBody: `{"data":null}`,
+		},
+		{
+			Description: "Search users without query",
+			Route:       "/api/v1/users/search",
+			Method:      "GET",
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 400,
+		},
+		{
+			Description:  "Search users without authentication",
+			Route:        "/api/v1/users/search?q=doe",
+			Method:       "GET",
+			CheckCode:    true,
+			ExpectedCode: 401,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
+
+func TestUserUpdate(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+
+	body := requests.UserUpdate{
+		Email:     helpers.UserEmail,
+		Password:  helpers.UserPassword,
+		Lastname:  "Test",
+		Firstname: "Update",
+	}
+
+	useCases := []helpers.Test{
+		{
+			Description: "Other user creation",
+			Route:       "/api/v1/users",
+			Method:      "POST",
+			Body: strings.NewReader(helpers.JsonToString(requests.UserCreation{
+				Email:     "test1@gmail.com",
+				Password:  "11111111",
+				Lastname:  "Test",
+				Firstname: "Other",
+			})),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Update user without If-Match header",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "PUT",
+			Body:        strings.NewReader(helpers.JsonToString(body)),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 428,
+		},
+		{
+			Description: "Update user with weak If-Match header",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "PUT",
+			Body:        strings.NewReader(helpers.JsonToString(body)),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: `W/"1"`},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 412,
+			ExpectedBody: `{"code":412,"message":"Invalid If-Match header"}`,
+		},
+		{
+			Description: "Update user with current If-Match header",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "PUT",
+			Body:        strings.NewReader(helpers.JsonToString(body)),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: `"1"`},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Update user with outdated If-Match header",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "PUT",
+			Body:        strings.NewReader(helpers.JsonToString(body)),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: `"1"`},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 412,
+			ExpectedBody: `{"code":412,"message":"User has been modified"}`,
+		},
+		{
+			Description: "Update user with email of another user",
+			Route:       "/api/v1/users/" + helpers.UserID,
+			Method:      "PUT",
+			Body: strings.NewReader(helpers.JsonToString(requests.UserUpdate{
+				Email:     "test1@gmail.com",
+				Password:  helpers.UserPassword,
+				Lastname:  "Test",
+				Firstname: "Update",
+			})),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 409,
+			ExpectedBody: `{"code":409,"message":"Email already exists","details":[{"FailedField":"Email","Tag":"unique","Value":""}]}`,
+		},
+		{
+			Description: "Update user with unknown user ID",
+			Route:       "/api/v1/users/f47ac10b-58cc-0372-8562-0b8e853961b3",
+			Method:      "PUT",
+			Body:        strings.NewReader(helpers.JsonToString(body)),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
+
+func TestUserChangeStatus(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+
+	useCases := []helpers.Test{
+		{
+			Description: "Suspend own account",
+			Route:       "/api/v1/users/" + helpers.UserID + "/suspend",
+			Method:      "POST",
+			Body:        strings.NewReader(`{"reason":"Test"}`),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 403,
+			ExpectedBody: `{"code":403,"message":"Cannot change your own status"}`,
+		},
+		{
+			Description: "Suspend unknown user",
+			Route:       "/api/v1/users/f47ac10b-58cc-0372-8562-0b8e853961b3/suspend",
+			Method:      "POST",
+			Body:        strings.NewReader(`{"reason":"Test"}`),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description: "Suspend user without reason",
+			Route:       "/api/v1/users/f47ac10b-58cc-0372-8562-0b8e853961b3/suspend",
+			Method:      "POST",
+			Body:        strings.NewReader(`{}`),
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: "application/json; charset=utf-8"},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 400,
+			ExpectedBody: `{"code":400,"message":"Invalid request data","details":[{"FailedField":"Reason","Tag":"required","Value":""}]}`,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
+
+func TestUserUpdateAvatar(t *testing.T) {
+	tdb := helpers.Init("../../.env", "../../migrations")
+	defer tdb.Drop()
+
+	var png bytes.Buffer
+	_ = imagepng.Encode(&png, image.NewRGBA(image.Rect(0, 0, 600, 300)))
+	pngBody, pngContentType := avatarMultipart(t, png.Bytes())
+	outdatedBody, outdatedContentType := avatarMultipart(t, png.Bytes())
+	textBody, textContentType := avatarMultipart(t, []byte("not an image"))
+
+	// PNG header declaring 100000 x 100000 pixels (decompression bomb)
+	var bomb bytes.Buffer
+	_ = imagepng.Encode(&bomb, image.NewGray(image.Rect(0, 0, 1, 1)))
+	bombContent := bomb.Bytes()
+	binary.BigEndian.PutUint32(bombContent[16:], 100000)
+	binary.BigEndian.PutUint32(bombContent[20:], 100000)
+	binary.BigEndian.PutUint32(bombContent[29:], crc32.ChecksumIEEE(bombContent[12:29]))
+	bombBody, bombContentType := avatarMultipart(t, bombContent)
+
+	useCases := []helpers.Test{
+		{
+			Description: "Upload avatar without If-Match header",
+			Route:       "/api/v1/users/" + helpers.UserID + "/avatar",
+			Method:      "PUT",
+			Body:        strings.NewReader(""),
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+			},
+			CheckCode:    true,
+			ExpectedCode: 428,
+		},
+		{
+			Description: "Upload PNG avatar",
+			Route:       "/api/v1/users/" + helpers.UserID + "/avatar",
+			Method:      "PUT",
+			Body:        pngBody,
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: pngContentType},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: `"1"`},
+			},
+			CheckCode:    true,
+			ExpectedCode: 200,
+		},
+		{
+			Description: "Upload avatar with outdated If-Match header",
+			Route:       "/api/v1/users/" + helpers.UserID + "/avatar",
+			Method:      "PUT",
+			Body:        outdatedBody,
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: outdatedContentType},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: `"1"`},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 412,
+			ExpectedBody: `{"code":412,"message":"User has been modified"}`,
+		},
+		{
+			Description:  "Uploaded files directories are not listed",
+			Route:        "/storage/avatars/" + helpers.UserID + "/",
+			Method:       "GET",
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description:  "Storage root is not listed",
+			Route:        "/storage/",
+			Method:       "GET",
+			CheckCode:    true,
+			ExpectedCode: 404,
+		},
+		{
+			Description: "Upload image with too large dimensions",
+			Route:       "/api/v1/users/" + helpers.UserID + "/avatar",
+			Method:      "PUT",
+			Body:        bombBody,
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: bombContentType},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 413,
+			ExpectedBody: `{"code":413,"message":"Image dimensions are too large"}`,
+		},
+		{
+			Description: "Upload unsupported file",
+			Route:       "/api/v1/users/" + helpers.UserID + "/avatar",
+			Method:      "PUT",
+			Body:        textBody,
+			Headers: []helpers.Header{
+				{Key: "Content-Type", Value: textContentType},
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+			},
+			CheckCode:    true,
+			CheckBody:    true,
+			ExpectedCode: 415,
+			ExpectedBody: `{"code":415,"message":"Unsupported image type","details":"text/plain; charset=utf-8"}`,
+		},
+		{
+			Description: "Upload without file",
+			Route:       "/api/v1/users/" + helpers.UserID + "/avatar",
+			Method:      "PUT",
+			Body:        strings.NewReader(""),
+			Headers: []helpers.Header{
+				{Key: "Authorization", Value: "Bearer " + tdb.Token},
+				{Key: "If-Match", Value: "*"},
+			},
+			CheckCode:    true,
+			ExpectedCode: 400,
+		},
+	}
+
+	tdb.Execute(t, useCases, "../../templates")
+}
+
+// avatarMultipart returns a multipart body containing an avatar file and its content type
+func avatarMultipart(t *testing.T, content []byte) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("avatar", "avatar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write(content)
+	_ = mw.Close()
+
+	return &body, mw.FormDataContentType()
+}
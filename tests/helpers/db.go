@@ -0,0 +1,373 @@
+package helpers
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/fixtures"
+	"chi_boilerplate/pkg/adapters/repositories"
+	"chi_boilerplate/pkg/adapters/storage"
+	"chi_boilerplate/pkg/domain/entities"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/services"
+	"chi_boilerplate/pkg/domain/usecases"
+	vo "chi_boilerplate/pkg/domain/value_objects"
+	"chi_boilerplate/pkg/infrastructure/chi_router"
+	"chi_boilerplate/pkg/infrastructure/logger"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDB is used to create and use a database for tests.
+type TestDB struct {
+	DB    db.Connection
+	Token string
+	drop  func() error
+}
+
+// Init initializes configuration from .env path and returns a TestDB.
+// The database driver is read from the TEST_DB_DRIVER environment variable:
+// "mysql" uses the MySQL server of the .env file, the default is an in-memory SQLite database
+// so that tests run without any database server.
+// With MySQL, TEST_DB_BACKEND=gorm uses the GORM repositories instead of the sqlx ones.
+// "postgres" uses the PostgreSQL server of the .env file and "memory" the in-memory repositories.
+func Init(p, m string) TestDB {
+	switch os.Getenv("TEST_DB_DRIVER") {
+	case db.DriverMySQL:
+		if os.Getenv("TEST_DB_BACKEND") == db.BackendGorm {
+			return InitGormMySQL(p, m)
+		}
+		return InitMySQL(p, m)
+	case db.DriverPostgres:
+		return InitPostgres(p, m)
+	case db.DriverMemory:
+		return InitMemory(p)
+	default:
+		return InitSQLite(p, m)
+	}
+}
+
+// MySQLEnabled returns true if tests can use the MySQL server of the .env file (TEST_DB_DRIVER=mysql)
+func MySQLEnabled() bool {
+	return os.Getenv("TEST_DB_DRIVER") == db.DriverMySQL
+}
+
+// PostgresEnabled returns true if tests can use the PostgreSQL server of the .env file (TEST_DB_DRIVER=postgres)
+func PostgresEnabled() bool {
+	return os.Getenv("TEST_DB_DRIVER") == db.DriverPostgres
+}
+
+// initConfig reads the .env file and overrides the values needed by tests.
+func initConfig(p string) {
+	viper.SetConfigFile(p)
+	viper.ReadInConfig()
+
+	viper.Set("APP_ENV", "test")
+	viper.Set("JWT_ALGO", "HS512")
+	viper.Set("JWT_SECRET", "mySecretForTest")
+	viper.Set("SERVER_PPROF", false)
+	viper.Set("LOG_ACCESS_ENABLE", false)
+
+	// Default values when there is no .env file
+	if viper.GetInt("JWT_LIFETIME") == 0 {
+		viper.Set("JWT_LIFETIME", 2)
+	}
+	if viper.GetInt("SERVER_TIMEOUT") == 0 {
+		viper.Set("SERVER_TIMEOUT", 10)
+	}
+}
+
+// Execute runs all tests.
+func (tdb *TestDB) Execute(t *testing.T, tests []Test, templatesPath string) {
+	// Set up the app as it is done in the main function
+	l, _ := logger.NewZapLogger()
+	st, _ := storage.NewLocalStorage(t.TempDir(), "http://localhost/storage")
+	s := chi_router.NewChiServer("", "", tdb.DB, st, l)
+	app, err := s.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Iterate through test single test cases
+	for _, test := range tests {
+		// Create a new http request with the route from the test case
+		req, _ := http.NewRequest(test.Method, test.Route, test.Body)
+		for _, h := range test.Headers {
+			if h.Key == "Host" {
+				req.Host = h.Value
+				continue
+			}
+			req.Header.Add(h.Key, h.Value)
+		}
+
+		// Perform the request plain with the app.
+		// The -1 disables request latency.
+		res := executeRequest(req, app)
+
+		// Verify if the status code is as expected
+		if test.CheckCode {
+			assert.Equalf(t, test.ExpectedCode, res.Code, test.Description)
+		}
+
+		// Verify if the body is as expected
+		if test.CheckBody {
+			// Read the response body
+			body, err := io.ReadAll(res.Body)
+
+			// Reading the response body should work everytime, such that
+			// the err variable should be nil
+			assert.Nilf(t, err, test.Description)
+
+			// Verify, that the response body equals the expected body
+			assert.Equalf(t, test.ExpectedBody, string(body), test.Description)
+		}
+
+		if test.Check != nil {
+			t.Run(test.Description, func(t *testing.T) {
+				test.Check(t, res)
+			})
+		}
+	}
+}
+
+// Drop database after the test.
+func (tdb *TestDB) Drop() error {
+	return tdb.drop()
+}
+
+// createUserAndAuthenticate creates the first user and returns its JWT.
+func createUserAndAuthenticate(conn db.Connection) (string, error) {
+	repos, err := repositories.New(conn)
+	if err != nil {
+		return "", err
+	}
+
+	// Create first user
+	_, errRes := usecases.NewSeed(repos.User).Users(TenantContext(), requests.UsersSeed{
+		Users: []requests.UserSeed{{
+			ID:        UserID,
+			Email:     UserEmail,
+			Password:  UserPassword,
+			Lastname:  "Test",
+			Firstname: "Test",
+			CreatedAt: UserCreatedAt,
+			UpdatedAt: UserUpdatedAt,
+		}},
+	})
+	if errRes != nil {
+		return "", fmt.Errorf("%v (%v)", errRes.Message, errRes.Details)
+	}
+
+	return newJWT(UserID, entities.DefaultOrganizationID)
+}
+
+// newJWT returns a JWT of a user of an organization.
+func newJWT(id, organizationID string) (string, error) {
+	userID, err := vo.NewIDFrom(id)
+	if err != nil {
+		return "", err
+	}
+	orgID, err := vo.NewIDFrom(organizationID)
+	if err != nil {
+		return "", err
+	}
+	jwt, err := services.NewJWT(userID, orgID, viper.GetDuration("JWT_LIFETIME"), viper.GetString("JWT_ALGO"), viper.GetString("JWT_SECRET"))
+	if err != nil {
+		return "", err
+	}
+
+	return jwt.Value, nil
+}
+
+// CreateOrganization creates an organization with a user (password: UserPassword) and returns the JWT of this user.
+func (tdb *TestDB) CreateOrganization(id, slug, userID, email string) (string, error) {
+	repos, err := repositories.New(tdb.DB)
+	if err != nil {
+		return "", err
+	}
+
+	err = repos.Organization.Create(context.Background(), requests.OrganizationCreationRepository{
+		ID:        id,
+		Name:      slug,
+		Slug:      slug,
+		CreatedAt: "2024-08-19 09:36:18",
+		UpdatedAt: "2024-08-19 09:36:18",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	ctx := domain.WithTenant(context.Background(), id)
+	_, errRes := usecases.NewSeed(repos.User).Users(ctx, requests.UsersSeed{
+		Users: []requests.UserSeed{{
+			ID:        userID,
+			Email:     email,
+			Password:  UserPassword,
+			Lastname:  "Test",
+			Firstname: "Test",
+		}},
+	})
+	if errRes != nil {
+		return "", fmt.Errorf("%v (%v)", errRes.Message, errRes.Details)
+	}
+
+	return newJWT(userID, id)
+}
+
+// CreateUser creates a user (password: UserPassword) in the default organization and returns its JWT.
+func (tdb *TestDB) CreateUser(id, email string) (string, error) {
+	repos, err := repositories.New(tdb.DB)
+	if err != nil {
+		return "", err
+	}
+
+	_, errRes := usecases.NewSeed(repos.User).Users(TenantContext(), requests.UsersSeed{
+		Users: []requests.UserSeed{{
+			ID:        id,
+			Email:     email,
+			Password:  UserPassword,
+			Lastname:  "Test",
+			Firstname: "Test",
+		}},
+	})
+	if errRes != nil {
+		return "", fmt.Errorf("%v (%v)", errRes.Message, errRes.Details)
+	}
+
+	return newJWT(id, entities.DefaultOrganizationID)
+}
+
+// TenantContext returns a context scoping the repositories to the default organization.
+func TenantContext() context.Context {
+	return domain.WithTenant(context.Background(), entities.DefaultOrganizationID)
+}
+
+// Seed loads fixtures files (YAML or JSON) in the database of the default organization.
+func (tdb *TestDB) Seed(paths ...string) error {
+	repos, err := repositories.New(tdb.DB)
+	if err != nil {
+		return err
+	}
+
+	var req requests.UsersSeed
+	for _, p := range paths {
+		f, err := fixtures.Load(p)
+		if err != nil {
+			return err
+		}
+		req.Users = append(req.Users, f.Users...)
+	}
+
+	if _, errRes := usecases.NewSeed(repos.User).Users(TenantContext(), req); errRes != nil {
+		return fmt.Errorf("%v (%v)", errRes.Message, errRes.Details)
+	}
+
+	return nil
+}
+
+// ErrNoSQL is returned by Dump with the in-memory repositories
+var ErrNoSQL = errors.New("not a SQL database")
+
+// Dump returns the rows of all the tables of the database, one line per row with the columns separated by "|".
+func (tdb *TestDB) Dump() (string, error) {
+	var conn *sql.DB
+	var tablesQuery string
+	switch c := tdb.DB.(type) {
+	case *db.SqlxSQLite:
+		conn, tablesQuery = c.DB.DB, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'"
+	case *db.SqlxMySQL:
+		conn, tablesQuery = c.DB.DB, "SHOW TABLES"
+	case *db.SqlxPostgres:
+		conn, tablesQuery = c.DB.DB, "SELECT tablename FROM pg_tables WHERE schemaname = current_schema()"
+	case *db.GormMySQL:
+		sqlDB, err := c.DB.DB()
+		if err != nil {
+			return "", err
+		}
+		conn, tablesQuery = sqlDB, "SHOW TABLES"
+	default:
+		return "", ErrNoSQL
+	}
+
+	var tables []string
+	rows, err := conn.Query(tablesQuery)
+	if err != nil {
+		return "", err
+	}
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return "", err
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+
+	var dump strings.Builder
+	for _, table := range tables {
+		rows, err := conn.Query("SELECT * FROM " + table)
+		if err != nil {
+			return "", err
+		}
+		columns, err := rows.Columns()
+		if err != nil {
+			rows.Close()
+			return "", err
+		}
+
+		values := make([]sql.RawBytes, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				rows.Close()
+				return "", err
+			}
+			dump.WriteString(table)
+			for _, v := range values {
+				dump.WriteString("|")
+				dump.Write(v)
+			}
+			dump.WriteString("\n")
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return dump.String(), nil
+}
+
+// mustInit panics if the test database cannot be initialized.
+func mustInit(tdb TestDB, err error) TestDB {
+	if err != nil {
+		log.Panicf("%v\n", err)
+	}
+	return tdb
+}
+
+// runMigrations applies the migrations of the m directory to the database of the connection.
+func runMigrations(m string, conn db.Connection) error {
+	mg, err := db.NewMigrator(conn, os.DirFS(m))
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+
+	return mg.Up()
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"testing"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -37,6 +38,9 @@ type Test struct {
 	ExpectedError bool
 	ExpectedCode  int
 	ExpectedBody  string
+
+	// Check is called with the response for the checks of its fields (optional)
+	Check func(t *testing.T, res *httptest.ResponseRecorder)
 }
 
 // Header represents an header value.
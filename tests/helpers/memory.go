@@ -0,0 +1,25 @@
+package helpers
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories/memory"
+)
+
+// newTestMemory returns a TestDB using a new in-memory database (no SQL, no migrations).
+func newTestMemory() (TestDB, error) {
+	dbt := memory.NewDatabase()
+
+	// Create first user and get token
+	token, err := createUserAndAuthenticate(dbt)
+	if err != nil {
+		return TestDB{}, err
+	}
+
+	return TestDB{DB: dbt, Token: token, drop: func() error { return nil }}, nil
+}
+
+// InitMemory initializes configuration from .env path and returns a TestDB using in-memory repositories.
+func InitMemory(p string) TestDB {
+	initConfig(p)
+
+	return mustInit(newTestMemory())
+}
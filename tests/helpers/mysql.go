@@ -2,40 +2,17 @@ package helpers
 
 import (
 	"chi_boilerplate/pkg/adapters/db"
-	"chi_boilerplate/pkg/adapters/repositories/sqlx_mysql"
-	"chi_boilerplate/pkg/domain/requests"
-	"chi_boilerplate/pkg/domain/services"
-	vo "chi_boilerplate/pkg/domain/value_objects"
-	"chi_boilerplate/pkg/infrastructure/chi_router"
-	"chi_boilerplate/pkg/infrastructure/logger"
-	"chi_boilerplate/utils"
-
-	"net/http"
-	"testing"
-
 	"fmt"
-	"io"
-	"log"
 	"math/rand"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-
 	"github.com/spf13/viper"
-	"github.com/stretchr/testify/assert"
 )
 
-// TestMysql is used to create and use a database for tests.
-type TestMysql struct {
-	name  string
-	DB    *db.SqlxMySQL
-	Token string
-}
-
-// newTestMysql returns a TestMysql instance.
-func newTestMysql(m string) (TestMysql, error) {
+// newTestMysql returns a TestDB using a new MySQL database.
+// The backend (sqlx or gorm) is the library used by the connection of the TestDB,
+// the database is created and migrated with sqlx.
+func newTestMysql(m, backend string) (TestDB, error) {
 	rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
 	dbName := viper.GetString("DB_DATABASE") + "__" + fmt.Sprintf("%08d", rand.Int63n(1e8))
 	config := db.Config{
@@ -54,154 +31,52 @@ func newTestMysql(m string) (TestMysql, error) {
 
 	dbt, err := db.NewSqlxMySQL(&config)
 	if err != nil {
-		return TestMysql{}, err
+		return TestDB{}, err
 	}
 
 	// Create database for test, use it and run migrations
 	dbt.DB.Exec(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`;", dbName))
 	dbt.DB.Exec(fmt.Sprintf("USE `%s`;", dbName))
 	dbt.Database(dbName)
-	err = runMySQLMigrations(m, dbt)
-	if err != nil {
-		return TestMysql{}, err
-	}
-
-	// Create first user and get token
-	token, err := createMySQLUserAndAuthenticate(dbt)
-	if err != nil {
-		return TestMysql{}, err
-	}
-
-	return TestMysql{DB: dbt, name: dbName, Token: token}, nil
-}
-
-// Init initializes configuration from .env path and returns TestMysql.
-func InitMySQL(p, m string) TestMysql {
-	viper.SetConfigFile(p)
-	viper.ReadInConfig()
-
-	viper.Set("APP_ENV", "test")
-	viper.Set("JWT_ALGO", "HS512")
-	viper.Set("JWT_SECRET", "mySecretForTest")
-	viper.Set("SERVER_PPROF", false)
-	viper.Set("LOG_ACCESS_ENABLE", false)
-
-	tdb, err := newTestMysql(m)
+	err = runMigrations(m, dbt)
 	if err != nil {
-		log.Panicf("%v\n", err)
+		return TestDB{}, err
 	}
-	return tdb
-}
 
-// Execute runs all tests.
-func (tdb *TestMysql) Execute(t *testing.T, tests []Test, templatesPath string) {
-	// Set up the app as it is done in the main function
-	l, _ := logger.NewZapLogger()
-	s := chi_router.NewChiServer("", "", tdb.DB, l)
-	app, _ := s.Setup()
-
-	// Iterate through test single test cases
-	for _, test := range tests {
-		// Create a new http request with the route from the test case
-		req, _ := http.NewRequest(test.Method, test.Route, test.Body)
-		for _, h := range test.Headers {
-			req.Header.Add(h.Key, h.Value)
-		}
-
-		// Perform the request plain with the app.
-		// The -1 disables request latency.
-		res := executeRequest(req, app)
-
-		// Verify if the status code is as expected
-		if test.CheckCode {
-			assert.Equalf(t, test.ExpectedCode, res.Code, test.Description)
-		}
-
-		// Verify if the body is as expected
-		if test.CheckBody {
-			// Read the response body
-			body, err := io.ReadAll(res.Body)
-
-			// Reading the response body should work everytime, such that
-			// the err variable should be nil
-			assert.Nilf(t, err, test.Description)
-
-			// Verify, that the response body equals the expected body
-			assert.Equalf(t, test.ExpectedBody, string(body), test.Description)
+	var conn db.Connection = dbt
+	if backend == db.BackendGorm {
+		config.Database = dbName
+		gdb, err := db.NewGormMySQL(&config)
+		if err != nil {
+			return TestDB{}, err
 		}
+		conn = gdb
 	}
-}
 
-// Drop database after the test.
-func (tdb *TestMysql) Drop() error {
-	_, err := tdb.DB.DB.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS `%s`;", tdb.name))
-
-	return err
-}
-
-func runMySQLMigrations(m string, db *db.SqlxMySQL) error {
-	newDSN, err := db.DSN()
-	if err != nil {
-		return err
-	}
-
-	mg, err := migrate.New(
-		fmt.Sprintf("file://%s", m),
-		fmt.Sprintf("mysql://%s", newDSN))
+	// Create first user and get token
+	token, err := createUserAndAuthenticate(conn)
 	if err != nil {
-		return err
+		return TestDB{}, err
 	}
 
-	err = mg.Up()
-	if err != nil {
+	drop := func() error {
+		_, err := dbt.DB.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS `%s`;", dbName))
 		return err
 	}
 
-	return nil
+	return TestDB{DB: conn, Token: token, drop: drop}, nil
 }
 
-func createMySQLUserAndAuthenticate(db *db.SqlxMySQL) (string, error) {
-	// Create first user
-	created_at, err := time.Parse(time.RFC3339, UserCreatedAt)
-	if err != nil {
-		return "", err
-	}
-	updated_at, err := time.Parse(time.RFC3339, UserUpdatedAt)
-	if err != nil {
-		return "", err
-	}
-	userID, err := vo.NewIDFrom(UserID)
-	if err != nil {
-		return "", err
-	}
-	userRepo := sqlx_mysql.NewUserMysqlRepository(db)
+// InitMySQL initializes configuration from .env path and returns a TestDB using MySQL with sqlx.
+func InitMySQL(p, m string) TestDB {
+	initConfig(p)
 
-	password, err := vo.NewPassword(UserPassword)
-	if err != nil {
-		return "", err
-	}
-	hashedPassword, err := password.HashUserPassword()
-	if err != nil {
-		return "", err
-	}
-	err = userRepo.Create(requests.UserCreationRepository{
-		ID:        userID.String(),
-		Lastname:  "Test",
-		Firstname: "Test",
-		Password:  hashedPassword,
-		Email:     UserEmail,
-		CreatedAt: created_at.Format(utils.SqlDateTimeFormat),
-		UpdatedAt: updated_at.Format(utils.SqlDateTimeFormat),
-	})
-	if err != nil {
-		return "", err
-	}
+	return mustInit(newTestMysql(m, db.BackendSqlx))
+}
 
-	// Generate JWT token
-	jwt, err := services.NewJWT(userID, viper.GetDuration("JWT_LIFETIME"), viper.GetString("JWT_ALGO"), viper.GetString("JWT_SECRET"))
-	if err != nil {
-		return "", err
-	}
+// InitGormMySQL initializes configuration from .env path and returns a TestDB using MySQL with GORM.
+func InitGormMySQL(p, m string) TestDB {
+	initConfig(p)
 
-	return jwt.Value, nil
+	return mustInit(newTestMysql(m, db.BackendGorm))
 }
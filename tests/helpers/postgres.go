@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// newTestPostgres returns a TestDB using a new PostgreSQL database.
+// The database is created from the postgres maintenance database of the server and migrated with sqlx.
+func newTestPostgres(m string) (TestDB, error) {
+	dbName := viper.GetString("DB_DATABASE") + "__" + fmt.Sprintf("%08d", rand.Int63n(1e8))
+	config := db.Config{
+		Host:            viper.GetString("DB_HOST"),
+		Username:        viper.GetString("DB_USERNAME"),
+		Password:        viper.GetString("DB_PASSWORD"),
+		Port:            viper.GetInt("DB_PORT"),
+		Database:        "postgres",
+		Location:        viper.GetString("DB_LOCATION"),
+		SSLMode:         viper.GetString("DB_SSL_MODE"),
+		MaxIdleConns:    viper.GetInt("DB_MAX_IDLE_CONNS"),
+		MaxOpenConns:    viper.GetInt("DB_MAX_OPEN_CONNS"),
+		ConnMaxLifetime: viper.GetDuration("DB_CONN_MAX_LIFETIME") * time.Hour,
+	}
+
+	admin, err := db.NewSqlxPostgres(&config)
+	if err != nil {
+		return TestDB{}, err
+	}
+	if _, err := admin.DB.Exec(fmt.Sprintf(`CREATE DATABASE "%s"`, dbName)); err != nil {
+		admin.Close()
+		return TestDB{}, err
+	}
+	drop := func() error {
+		_, err := admin.DB.Exec(fmt.Sprintf(`DROP DATABASE IF EXISTS "%s"`, dbName))
+		return errors.Join(err, admin.Close())
+	}
+
+	// A PostgreSQL connection cannot change of database: the test database has its own connection
+	testConfig := config
+	testConfig.Database = dbName
+	dbt, err := db.NewSqlxPostgres(&testConfig)
+	if err != nil {
+		return TestDB{}, errors.Join(err, drop())
+	}
+	dropAll := func() error {
+		return errors.Join(dbt.Close(), drop())
+	}
+
+	if err := runMigrations(m, dbt); err != nil {
+		return TestDB{}, errors.Join(err, dropAll())
+	}
+
+	// Create first user and get token
+	token, err := createUserAndAuthenticate(dbt)
+	if err != nil {
+		return TestDB{}, errors.Join(err, dropAll())
+	}
+
+	return TestDB{DB: dbt, Token: token, drop: dropAll}, nil
+}
+
+// InitPostgres initializes configuration from .env path and returns a TestDB using PostgreSQL with sqlx.
+// m is the migrations directory, PostgreSQL migrations are in its "postgres" subdirectory.
+func InitPostgres(p, m string) TestDB {
+	initConfig(p)
+
+	return mustInit(newTestPostgres(m))
+}
@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+)
+
+// newTestSQLite returns a TestDB using a new in-memory SQLite database.
+func newTestSQLite(m string) (TestDB, error) {
+	dbt, err := db.NewSqlxSQLite(&db.Config{Database: db.SQLiteMemory})
+	if err != nil {
+		return TestDB{}, err
+	}
+
+	err = runMigrations(m, dbt)
+	if err != nil {
+		return TestDB{}, err
+	}
+
+	// Create first user and get token
+	token, err := createUserAndAuthenticate(dbt)
+	if err != nil {
+		return TestDB{}, err
+	}
+
+	// The in-memory database is deleted with its connection
+	return TestDB{DB: dbt, Token: token, drop: dbt.DB.Close}, nil
+}
+
+// InitSQLite initializes configuration from .env path and returns a TestDB using an in-memory SQLite database.
+// m is the migrations directory, SQLite migrations are in its "sqlite" subdirectory.
+func InitSQLite(p, m string) TestDB {
+	initConfig(p)
+
+	return mustInit(newTestSQLite(m))
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories"
+	"chi_boilerplate/pkg/domain/entities"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestJobRunRepositoryContract runs the same tests against every JobRunRepository implementation
+func TestJobRunRepositoryContract(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			repos, err := repositories.New(tdb.DB)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testJobRunRepository(t, repos.JobRuns)
+			testJobLocker(t, repos.JobLocker)
+		})
+	}
+}
+
+func testJobRunRepository(t *testing.T, repo domain.JobRunRepository) {
+	ctx := context.Background()
+
+	for _, run := range []requests.JobRunCreationRepository{
+		{ID: userID2, Job: "users-erasure", Instance: "host-1:1", ScheduledAt: "2025-03-01 03:30:00", StartedAt: "2025-03-01 03:30:01"},
+		{ID: userID3, Job: "users-erasure", Instance: "host-2:1", ScheduledAt: "2025-03-02 03:30:00", StartedAt: "2025-03-02 03:30:01"},
+		{ID: userID4, Job: "job-runs-purge", Instance: "host-1:1", ScheduledAt: "2025-03-02 04:00:00", StartedAt: "2025-03-02 04:00:00"},
+	} {
+		assert.Nil(t, repo.Create(ctx, run))
+	}
+
+	err := repo.Finish(ctx, requests.JobRunFinishRepository{
+		ID:         userID3,
+		Status:     string(entities.JobRunStatusFailed),
+		Error:      "database unavailable",
+		FinishedAt: "2025-03-02 03:30:05",
+	})
+	assert.Nil(t, err)
+
+	t.Run("Latest runs first", func(t *testing.T) {
+		runs, err := repo.GetAll(ctx, requests.JobRunsList{Limit: 10})
+		assert.Nil(t, err)
+		if assert.Len(t, runs, 3) {
+			assert.Equal(t, userID4, runs[0].ID)
+			assert.Equal(t, userID3, runs[1].ID)
+			assert.Equal(t, userID2, runs[2].ID)
+		}
+
+		runs, err = repo.GetAll(ctx, requests.JobRunsList{Job: "users-erasure", Limit: 1})
+		assert.Nil(t, err)
+		if assert.Len(t, runs, 1) {
+			run := runs[0]
+			assert.Equal(t, userID3, run.ID)
+			assert.Equal(t, "host-2:1", run.Instance)
+			assert.Equal(t, string(entities.JobRunStatusFailed), run.Status)
+			assert.Equal(t, "database unavailable", run.Error)
+			assert.True(t, strings.HasPrefix(run.ScheduledAt, "2025-03-02T03:30:00"), run.ScheduledAt)
+			if assert.NotNil(t, run.FinishedAt) {
+				assert.True(t, strings.HasPrefix(*run.FinishedAt, "2025-03-02T03:30:05"), *run.FinishedAt)
+			}
+		}
+	})
+
+	t.Run("Running runs", func(t *testing.T) {
+		runs, err := repo.GetAll(ctx, requests.JobRunsList{Job: "job-runs-purge", Limit: 10})
+		assert.Nil(t, err)
+		if assert.Len(t, runs, 1) {
+			assert.Equal(t, string(entities.JobRunStatusRunning), runs[0].Status)
+			assert.Nil(t, runs[0].FinishedAt)
+		}
+	})
+
+	t.Run("Delete old runs", func(t *testing.T) {
+		deleted, err := repo.DeleteBefore(ctx, "2025-03-02 00:00:00")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		runs, err := repo.GetAll(ctx, requests.JobRunsList{Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, runs, 2)
+	})
+}
+
+func testJobLocker(t *testing.T, locker domain.JobLocker) {
+	ctx := context.Background()
+
+	unlock, ok, err := locker.TryLock(ctx, "users-erasure")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = locker.TryLock(ctx, "users-erasure")
+	assert.Nil(t, err)
+	assert.False(t, ok, "the lock is held")
+
+	unlock()
+
+	unlock, ok, err = locker.TryLock(ctx, "users-erasure")
+	assert.Nil(t, err)
+	assert.True(t, ok, "the lock is released")
+	unlock()
+}
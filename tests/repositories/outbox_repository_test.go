@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/tests/helpers"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	outboxEventID1 = "0c6a9f4e-5b1d-4c3e-9a7f-1e2d3c4b5a61"
+	outboxEventID2 = "0c6a9f4e-5b1d-4c3e-9a7f-1e2d3c4b5a62"
+	outboxEventID3 = "0c6a9f4e-5b1d-4c3e-9a7f-1e2d3c4b5a63"
+	outboxEventID4 = "0c6a9f4e-5b1d-4c3e-9a7f-1e2d3c4b5a64"
+	outboxNow      = "2024-01-01 12:00:00"
+	outboxLater    = "2024-01-01 13:00:00"
+)
+
+// TestOutboxRepositoryContract runs the same tests against every OutboxRepository implementation
+func TestOutboxRepositoryContract(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			repos, err := repositories.New(tdb.DB)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testOutboxRepository(t, repos.Outbox)
+		})
+	}
+}
+
+// TestOutboxRepositoryRollback checks that the events recorded in a rolled back transaction are not saved
+func TestOutboxRepositoryRollback(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			if name == "memory" {
+				t.Skip("In-memory operations are not rolled back")
+			}
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			repos, err := repositories.New(tdb.DB)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			errRollback := errors.New("rollback")
+
+			err = repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := repos.Outbox.Create(ctx, newOutboxEvent(outboxEventID1, helpers.UserID, "user.created")); err != nil {
+					return err
+				}
+
+				return errRollback
+			})
+			assert.ErrorIs(t, err, errRollback)
+
+			assert.Empty(t, pendingEventIDs(t, repos.Outbox, outboxLater))
+			dump, err := tdb.Dump()
+			assert.Nil(t, err)
+			assert.NotContains(t, dump, outboxEventID1)
+		})
+	}
+}
+
+// newOutboxEvent returns the request to save an event of a user created at userCreatedAt
+func newOutboxEvent(id, userID, eventType string) requests.OutboxEventCreationRepository {
+	return requests.OutboxEventCreationRepository{
+		ID:            id,
+		AggregateType: "user",
+		AggregateID:   userID,
+		EventType:     eventType,
+		Payload:       `{"id": "` + userID + `"}`,
+		RequestID:     "request-1",
+		CreatedAt:     userCreatedAt,
+	}
+}
+
+// pendingEventIDs returns the IDs of the events ready to be published at a date
+func pendingEventIDs(t *testing.T, repo domain.OutboxRepository, now string) []string {
+	events, err := repo.GetPending(context.Background(), requests.OutboxEventsPendingRepository{Now: now, Limit: 10})
+	assert.Nil(t, err)
+
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	return ids
+}
+
+func testOutboxRepository(t *testing.T, repo domain.OutboxRepository) {
+	ctx := context.Background()
+	for _, event := range []requests.OutboxEventCreationRepository{
+		newOutboxEvent(outboxEventID1, helpers.UserID, "user.created"),
+		newOutboxEvent(outboxEventID2, helpers.UserID, "user.updated"),
+		newOutboxEvent(outboxEventID3, userID2, "user.created"),
+		newOutboxEvent(outboxEventID4, helpers.UserID, "user.deleted"),
+	} {
+		assert.Nil(t, repo.Create(ctx, event))
+	}
+
+	// Events are returned in creation order
+	events, err := repo.GetPending(ctx, requests.OutboxEventsPendingRepository{Now: outboxNow, Limit: 2})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, outboxEventID1, events[0].ID)
+		assert.Equal(t, outboxEventID2, events[1].ID)
+		assert.Less(t, events[0].Seq, events[1].Seq)
+		assert.Equal(t, "user.created", events[0].EventType)
+		assert.Equal(t, helpers.UserID, events[0].AggregateID)
+		assert.Equal(t, "request-1", events[0].RequestID)
+		assert.JSONEq(t, `{"id": "`+helpers.UserID+`"}`, string(events[0].Payload))
+		assert.Equal(t, 0, events[0].Attempts)
+		assert.Equal(t, events[0].CreatedAt, events[0].NextAttemptAt)
+	}
+
+	// Events are not ready before their creation
+	assert.Empty(t, pendingEventIDs(t, repo, "2023-12-31 10:00:00"))
+
+	// A failed event blocks the next events of its aggregate until its next attempt
+	err = repo.MarkFailed(ctx, requests.OutboxEventFailureRepository{ID: outboxEventID2, LastError: "error", NextAttemptAt: outboxLater})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{outboxEventID1, outboxEventID3}, pendingEventIDs(t, repo, outboxNow))
+
+	events, err = repo.GetPending(ctx, requests.OutboxEventsPendingRepository{Now: outboxLater, Limit: 10})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 4) {
+		assert.Equal(t, outboxEventID2, events[1].ID)
+		assert.Equal(t, 1, events[1].Attempts)
+		assert.NotEqual(t, events[1].CreatedAt, events[1].NextAttemptAt)
+	}
+
+	// Publication
+	err = repo.MarkPublished(ctx, requests.OutboxEventPublishedRepository{ID: outboxEventID1, PublishedAt: userUpdatedAt})
+	assert.Nil(t, err)
+	err = repo.MarkPublished(ctx, requests.OutboxEventPublishedRepository{ID: outboxEventID3, PublishedAt: userUpdatedAt})
+	assert.Nil(t, err)
+	assert.Empty(t, pendingEventIDs(t, repo, outboxNow))
+	assert.Equal(t, []string{outboxEventID2, outboxEventID4}, pendingEventIDs(t, repo, outboxLater))
+
+	// Purge: only the events published before the date are deleted
+	deleted, err := repo.DeletePublishedBefore(ctx, userUpdatedAt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	deleted, err = repo.DeletePublishedBefore(ctx, "2024-01-03 00:00:00")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), deleted)
+	assert.Equal(t, []string{outboxEventID2, outboxEventID4}, pendingEventIDs(t, repo, outboxLater))
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories"
+	"chi_boilerplate/pkg/domain/entities"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/tests/helpers"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const organizationID2 = "5b1e7c7e-3f7a-4c8e-9d4b-2f6a1c0e8d21"
+
+// TestTenantRepositoryContract checks with every implementation that an organization
+// can never read or mutate the users and the audit events of another organization.
+func TestTenantRepositoryContract(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			repos, err := repositories.New(tdb.DB)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testOrganizationRepository(t, repos.Organization)
+			testTenantIsolation(t, repos)
+		})
+	}
+}
+
+// testOrganizationRepository is the contract of the OrganizationRepository interface.
+// It creates the second organization used by testTenantIsolation.
+func testOrganizationRepository(t *testing.T, repo domain.OrganizationRepository) {
+	ctx := context.Background()
+
+	organization, err := repo.GetByID(ctx, requests.OrganizationByID{ID: entities.DefaultOrganizationID})
+	assert.Nil(t, err)
+	assert.Equal(t, "default", organization.Slug)
+
+	err = repo.Create(ctx, requests.OrganizationCreationRepository{
+		ID:        organizationID2,
+		Name:      "Acme",
+		Slug:      "acme",
+		CreatedAt: userCreatedAt,
+		UpdatedAt: userUpdatedAt,
+	})
+	assert.Nil(t, err)
+
+	organization, err = repo.GetBySlug(ctx, requests.OrganizationBySlug{Slug: "acme"})
+	assert.Nil(t, err)
+	assert.Equal(t, organizationID2, organization.ID)
+	assert.Equal(t, "Acme", organization.Name)
+	assert.Equal(t, "2024-01-01T10:00:00Z", organization.CreatedAt)
+
+	err = repo.Create(ctx, requests.OrganizationCreationRepository{
+		ID:        unknownUserID,
+		Name:      "Acme 2",
+		Slug:      "acme",
+		CreatedAt: userCreatedAt,
+		UpdatedAt: userUpdatedAt,
+	})
+	assert.ErrorIs(t, err, domain.ErrSlugAlreadyExists)
+
+	_, err = repo.GetBySlug(ctx, requests.OrganizationBySlug{Slug: "unknown"})
+	assert.ErrorIs(t, err, domain.ErrOrganizationNotFound)
+
+	organizations, err := repo.GetAll(ctx)
+	assert.Nil(t, err)
+	if assert.Len(t, organizations, 2) {
+		assert.Equal(t, "acme", organizations[0].Slug)
+		assert.Equal(t, "default", organizations[1].Slug)
+	}
+}
+
+// testTenantIsolation checks that the user of the default organization (helpers.UserID)
+// is invisible from the second organization.
+func testTenantIsolation(t *testing.T, repos repositories.Repositories) {
+	ctx := helpers.TenantContext()
+	other := domain.WithTenant(context.Background(), organizationID2)
+
+	t.Run("Missing organization", func(t *testing.T) {
+		_, err := repos.User.GetByID(context.Background(), requests.UserByID{ID: helpers.UserID})
+		assert.ErrorIs(t, err, domain.ErrNoTenant)
+
+		_, err = repos.User.CountAll(context.Background(), requests.UsersList{})
+		assert.ErrorIs(t, err, domain.ErrNoTenant)
+
+		_, err = repos.Audit.GetByUser(context.Background(), helpers.UserID)
+		assert.ErrorIs(t, err, domain.ErrNoTenant)
+
+		_, err = repos.Audit.DeleteBefore(context.Background(), userUpdatedAt)
+		assert.ErrorIs(t, err, domain.ErrNoTenant)
+	})
+
+	t.Run("Same email in another organization", func(t *testing.T) {
+		err := repos.User.Create(other, newUser(userID2, helpers.UserEmail, "John", "Doe"))
+		assert.Nil(t, err)
+
+		err = repos.User.Create(other, newUser(userID3, helpers.UserEmail, "John", "Doe"))
+		assert.ErrorIs(t, err, domain.ErrEmailAlreadyExists)
+
+		login, err := repos.User.GetByEmail(other, requests.GetByEmail{Email: helpers.UserEmail})
+		assert.Nil(t, err)
+		assert.Equal(t, userID2, login.ID.String())
+
+		login, err = repos.User.GetByEmail(ctx, requests.GetByEmail{Email: helpers.UserEmail})
+		assert.Nil(t, err)
+		assert.Equal(t, helpers.UserID, login.ID.String())
+	})
+
+	t.Run("Read", func(t *testing.T) {
+		_, err := repos.User.GetByID(other, requests.UserByID{ID: helpers.UserID})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		user, err := repos.User.GetByID(other, requests.UserByID{ID: userID2})
+		assert.Nil(t, err)
+		assert.Equal(t, organizationID2, user.OrganizationID)
+
+		total, err := repos.User.CountAll(other, requests.UsersList{})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+
+		users, err := repos.User.GetAll(other, requests.UsersList{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{helpers.UserEmail}, emails(users))
+		if assert.Len(t, users, 1) {
+			assert.Equal(t, userID2, users[0].ID)
+		}
+	})
+
+	t.Run("Mutations", func(t *testing.T) {
+		err := repos.User.Update(other, requests.UserUpdateRepository{
+			ID:        helpers.UserID,
+			Email:     "hacked@test.com",
+			Password:  hashedPassword,
+			Lastname:  "Hacked",
+			Firstname: "Hacked",
+			UpdatedAt: userUpdatedAt,
+		})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		err = repos.User.UpdateStatus(other, requests.UserStatusUpdateRepository{ID: helpers.UserID, Status: "disabled", UpdatedAt: userUpdatedAt})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		err = repos.User.UpdateAvatar(other, requests.UserAvatarUpdateRepository{ID: helpers.UserID, Avatar: "avatar.png", UpdatedAt: userUpdatedAt})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		err = repos.User.Delete(other, requests.UserDelete{ID: helpers.UserID})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		user, err := repos.User.GetByID(ctx, requests.UserByID{ID: helpers.UserID})
+		assert.Nil(t, err)
+		assert.Equal(t, helpers.UserEmail, user.Email)
+		assert.Equal(t, "active", user.Status)
+		assert.Equal(t, "", user.Avatar)
+		assert.Equal(t, uint64(1), user.Version)
+	})
+
+	t.Run("Erasure", func(t *testing.T) {
+		err := repos.User.Delete(ctx, requests.UserDelete{ID: helpers.UserID})
+		assert.Nil(t, err)
+
+		users, err := repos.User.GetErasable(other, requests.UsersErasableRepository{DeletedBefore: deletedBefore, Limit: 10})
+		assert.Nil(t, err)
+		assert.Empty(t, users)
+
+		err = repos.User.Erase(other, requests.UserErasureRepository{ID: helpers.UserID, Email: "erased@test.com", ErasedAt: userErasedAt})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		users, err = repos.User.GetErasable(ctx, requests.UsersErasableRepository{DeletedBefore: deletedBefore, Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+	})
+
+	t.Run("Audit events", func(t *testing.T) {
+		err := repos.Audit.Create(ctx, requests.AuditEventCreationRepository{
+			ID:         userID3,
+			ActorID:    helpers.UserID,
+			Action:     string(entities.AuditActionUpdate),
+			TargetType: "user",
+			TargetID:   helpers.UserID,
+			Changes:    "{}",
+			CreatedAt:  userCreatedAt,
+		})
+		assert.Nil(t, err)
+
+		events, err := repos.Audit.GetByUser(other, helpers.UserID)
+		assert.Nil(t, err)
+		assert.Empty(t, events)
+
+		total, err := repos.Audit.CountAll(other, requests.AuditEventsList{})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), total)
+
+		list, err := repos.Audit.GetAll(other, requests.AuditEventsList{ActorID: helpers.UserID})
+		assert.Nil(t, err)
+		assert.Empty(t, list)
+
+		events, err = repos.Audit.GetByUser(ctx, helpers.UserID)
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+
+		deleted, err := repos.Audit.DeleteBefore(other, userUpdatedAt)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), deleted)
+
+		deleted, err = repos.Audit.DeleteBefore(ctx, userCreatedAt)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), deleted, "the events created at the date are kept")
+
+		deleted, err = repos.Audit.DeleteBefore(ctx, userUpdatedAt)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		events, err = repos.Audit.GetByUser(ctx, helpers.UserID)
+		assert.Nil(t, err)
+		assert.Empty(t, events)
+	})
+}
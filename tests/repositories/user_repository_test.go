@@ -0,0 +1,485 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/adapters/db"
+	"chi_boilerplate/pkg/adapters/repositories"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/tests/helpers"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	userID2        = "9a2a4a6e-54a5-4b7f-a2b1-0b8e853961b3"
+	userID3        = "c1d8a3f0-1e2b-4c5d-8e9f-0a1b2c3d4e5f"
+	unknownUserID  = "f47ac10b-58cc-0372-8562-0b8e853961b3"
+	userCreatedAt  = "2024-01-01 10:00:00"
+	userUpdatedAt  = "2024-01-02 10:00:00"
+	userErasedAt   = "2024-02-01 10:00:00"
+	deletedBefore  = "2999-01-01 00:00:00"
+	hashedPassword = "$2a$10$0mOpnVRGsYuWHkVAd2zZBOWEYJGqqXxv7E0pv9xaPTsUAE8xROzji"
+)
+
+// userRepositoryImplementations returns the UserRepository implementations to test.
+// In-memory and sqlx with SQLite are always tested, sqlx and GORM with MySQL only with TEST_DB_DRIVER=mysql
+// and sqlx with PostgreSQL only with TEST_DB_DRIVER=postgres (see skipDisabled).
+func userRepositoryImplementations() map[string]func() helpers.TestDB {
+	return map[string]func() helpers.TestDB{
+		"memory": func() helpers.TestDB {
+			return helpers.InitMemory("../../.env")
+		},
+		"sqlx_sqlite": func() helpers.TestDB {
+			return helpers.InitSQLite("../../.env", "../../migrations")
+		},
+		"sqlx_mysql": func() helpers.TestDB {
+			return helpers.InitMySQL("../../.env", "../../migrations")
+		},
+		"gorm_mysql": func() helpers.TestDB {
+			return helpers.InitGormMySQL("../../.env", "../../migrations")
+		},
+		"sqlx_postgres": func() helpers.TestDB {
+			return helpers.InitPostgres("../../.env", "../../migrations")
+		},
+	}
+}
+
+// skipDisabled skips the test of an implementation whose database server is not enabled
+func skipDisabled(t *testing.T, name string) {
+	if strings.HasSuffix(name, "_mysql") && !helpers.MySQLEnabled() {
+		t.Skip("MySQL is not enabled (TEST_DB_DRIVER=mysql)")
+	}
+	if strings.HasSuffix(name, "_postgres") && !helpers.PostgresEnabled() {
+		t.Skip("PostgreSQL is not enabled (TEST_DB_DRIVER=postgres)")
+	}
+}
+
+// TestUserRepositoryContract runs the same tests against every UserRepository implementation
+func TestUserRepositoryContract(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			repos, err := repositories.New(tdb.DB)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			testUserRepository(t, repos.User)
+		})
+	}
+}
+
+// TestUserRepositoryQueryCancellation checks that the deadline of the context aborts a query
+// waiting for a user locked by another transaction.
+func TestUserRepositoryQueryCancellation(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			if name == "memory" {
+				t.Skip("In-memory operations never wait")
+			}
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			repos, err := repositories.New(tdb.DB)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tx := lockUser(t, tdb.DB, helpers.UserID)
+			defer tx.Rollback()
+
+			ctx, cancel := context.WithTimeout(helpers.TenantContext(), 200*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			err = repos.User.UpdateStatus(ctx, requests.UserStatusUpdateRepository{
+				ID:        helpers.UserID,
+				Status:    "disabled",
+				UpdatedAt: userUpdatedAt,
+			})
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) {
+				// PostgreSQL cancels the query: query_canceled
+				assert.Equal(t, pq.ErrorCode("57014"), pqErr.Code)
+			} else {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+			}
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+// TestTxManager checks that the repositories take part in the transaction of the context
+func TestTxManager(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			if name == "memory" {
+				t.Skip("In-memory operations are not rolled back")
+			}
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			repos, err := repositories.New(tdb.DB)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := helpers.TenantContext()
+			errRollback := errors.New("rollback")
+
+			err = repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := repos.User.Create(ctx, newUser(userID2, "john.doe@test.com", "John", "Doe")); err != nil {
+					return err
+				}
+
+				total, err := repos.User.CountAll(ctx, requests.UsersList{})
+				assert.Nil(t, err)
+				assert.Equal(t, int64(2), total)
+
+				return errRollback
+			})
+			assert.ErrorIs(t, err, errRollback)
+
+			_, err = repos.User.GetByID(ctx, requests.UserByID{ID: userID2})
+			assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+			err = repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				return repos.User.Create(ctx, newUser(userID2, "john.doe@test.com", "John", "Doe"))
+			})
+			assert.Nil(t, err)
+
+			_, err = repos.User.GetByID(ctx, requests.UserByID{ID: userID2})
+			assert.Nil(t, err)
+		})
+	}
+}
+
+// lockUser starts a transaction holding a write lock on a user.
+// With the in-memory SQLite database, the transaction holds the only connection.
+func lockUser(t *testing.T, conn db.Connection, id string) *sql.Tx {
+	var sqlDB *sql.DB
+	query := "UPDATE users SET version = version WHERE id = ?"
+	switch c := conn.(type) {
+	case *db.SqlxSQLite:
+		sqlDB = c.DB.DB
+	case *db.SqlxMySQL:
+		sqlDB = c.DB.DB
+	case *db.SqlxPostgres:
+		sqlDB, query = c.DB.DB, c.DB.Rebind(query)
+	case *db.GormMySQL:
+		d, err := c.DB.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sqlDB = d
+	default:
+		t.Fatalf("unsupported connection: %T", conn)
+	}
+
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(query, id); err != nil {
+		t.Fatal(err)
+	}
+
+	return tx
+}
+
+// testUserRepository is the contract of the UserRepository interface.
+// The database already contains the user created by helpers (helpers.UserID).
+func testUserRepository(t *testing.T, repo domain.UserRepository) {
+	ctx := helpers.TenantContext()
+
+	t.Run("Create and get by ID", func(t *testing.T) {
+		err := repo.Create(ctx, newUser(userID2, "john.doe@test.com", "John", "Doe"))
+		assert.Nil(t, err)
+
+		user, err := repo.GetByID(ctx, requests.UserByID{ID: userID2})
+		assert.Nil(t, err)
+		assert.Equal(t, userID2, user.ID)
+		assert.Equal(t, "john.doe@test.com", user.Email)
+		assert.Equal(t, "Doe", user.Lastname)
+		assert.Equal(t, "John", user.Firstname)
+		assert.Equal(t, "active", user.Status)
+		assert.Equal(t, "", user.Avatar)
+		assert.Equal(t, uint64(1), user.Version)
+		assert.Equal(t, "2024-01-01T10:00:00Z", user.CreatedAt)
+		assert.Equal(t, "2024-01-02T10:00:00Z", user.UpdatedAt)
+	})
+
+	t.Run("Create with existing email", func(t *testing.T) {
+		err := repo.Create(ctx, newUser(userID3, "John.Doe@test.com", "John", "Doe"))
+		assert.ErrorIs(t, err, domain.ErrEmailAlreadyExists)
+
+		err = repo.Create(ctx, newUser(userID3, "jane.smith@test.com", "Jane", "Smith"))
+		assert.Nil(t, err)
+	})
+
+	t.Run("Get by unknown ID", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, requests.UserByID{ID: unknownUserID})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Get by email", func(t *testing.T) {
+		user, err := repo.GetByEmail(ctx, requests.GetByEmail{Email: "john.doe@test.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, userID2, user.ID.String())
+		assert.Equal(t, hashedPassword, user.Password.String())
+		assert.Equal(t, "active", string(user.Status))
+
+		_, err = repo.GetByEmail(ctx, requests.GetByEmail{Email: "unknown@test.com"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		// LIKE wildcards are not interpreted
+		_, err = repo.GetByEmail(ctx, requests.GetByEmail{Email: "john_doe@test.com"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Get all with sort, pagination and filters", func(t *testing.T) {
+		count, err := repo.CountAll(ctx, requests.UsersList{})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), count)
+
+		users, err := repo.GetAll(ctx, requests.UsersList{Sorts: "+email"})
+		assert.Nil(t, err)
+		assert.Len(t, users, 3)
+		assert.Equal(t, []string{"jane.smith@test.com", "john.doe@test.com", helpers.UserEmail}, emails(users))
+
+		users, err = repo.GetAll(ctx, requests.UsersList{Sorts: "-email"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{helpers.UserEmail, "john.doe@test.com", "jane.smith@test.com"}, emails(users))
+
+		users, err = repo.GetAll(ctx, requests.UsersList{Sorts: "+email", Page: "2", Limit: "2"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{helpers.UserEmail}, emails(users))
+
+		users, err = repo.GetAll(ctx, requests.UsersList{Sorts: "+email", Status: "suspended"})
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		count, err = repo.CountAll(ctx, requests.UsersList{Status: "suspended"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		err := repo.Update(ctx, requests.UserUpdateRepository{
+			ID:        userID2,
+			Email:     "john.doe2@test.com",
+			Password:  hashedPassword,
+			Lastname:  "Doe2",
+			Firstname: "John2",
+			UpdatedAt: "2024-01-03 10:00:00",
+			Version:   1,
+		})
+		assert.Nil(t, err)
+
+		user, err := repo.GetByID(ctx, requests.UserByID{ID: userID2})
+		assert.Nil(t, err)
+		assert.Equal(t, "john.doe2@test.com", user.Email)
+		assert.Equal(t, "Doe2", user.Lastname)
+		assert.Equal(t, "John2", user.Firstname)
+		assert.Equal(t, uint64(2), user.Version)
+		assert.Equal(t, "2024-01-01T10:00:00Z", user.CreatedAt)
+		assert.Equal(t, "2024-01-03T10:00:00Z", user.UpdatedAt)
+	})
+
+	t.Run("Update with outdated version, unknown ID or existing email", func(t *testing.T) {
+		req := requests.UserUpdateRepository{
+			ID:        userID2,
+			Email:     "john.doe3@test.com",
+			Password:  hashedPassword,
+			Lastname:  "Doe3",
+			Firstname: "John3",
+			UpdatedAt: "2024-01-04 10:00:00",
+			Version:   1,
+		}
+		assert.ErrorIs(t, repo.Update(ctx, req), domain.ErrUserVersionMismatch)
+
+		req.ID = unknownUserID
+		req.Version = 0
+		assert.ErrorIs(t, repo.Update(ctx, req), domain.ErrUserNotFound)
+
+		req.ID = userID2
+		req.Email = "jane.smith@test.com"
+		assert.ErrorIs(t, repo.Update(ctx, req), domain.ErrEmailAlreadyExists)
+	})
+
+	t.Run("Update status", func(t *testing.T) {
+		err := repo.UpdateStatus(ctx, requests.UserStatusUpdateRepository{
+			ID:        userID3,
+			Status:    "suspended",
+			Reason:    "Spam",
+			UpdatedAt: "2024-01-03 10:00:00",
+			Version:   1,
+		})
+		assert.Nil(t, err)
+
+		user, err := repo.GetByID(ctx, requests.UserByID{ID: userID3})
+		assert.Nil(t, err)
+		assert.Equal(t, "suspended", user.Status)
+		assert.Equal(t, "Spam", user.StatusReason)
+		assert.Equal(t, uint64(2), user.Version)
+
+		users, err := repo.GetAll(ctx, requests.UsersList{Status: "suspended"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"jane.smith@test.com"}, emails(users))
+
+		err = repo.UpdateStatus(ctx, requests.UserStatusUpdateRepository{ID: userID3, Status: "active", UpdatedAt: userUpdatedAt, Version: 1})
+		assert.ErrorIs(t, err, domain.ErrUserVersionMismatch)
+	})
+
+	t.Run("Update avatar", func(t *testing.T) {
+		err := repo.UpdateAvatar(ctx, requests.UserAvatarUpdateRepository{
+			ID:        userID3,
+			Avatar:    "avatars/" + userID3 + "/avatar.jpg",
+			UpdatedAt: "2024-01-04 10:00:00",
+			Version:   2,
+		})
+		assert.Nil(t, err)
+
+		user, err := repo.GetByID(ctx, requests.UserByID{ID: userID3})
+		assert.Nil(t, err)
+		assert.Equal(t, "avatars/"+userID3+"/avatar.jpg", user.Avatar)
+		assert.Equal(t, uint64(3), user.Version)
+
+		err = repo.UpdateAvatar(ctx, requests.UserAvatarUpdateRepository{ID: userID3, Avatar: "avatar.jpg", UpdatedAt: userUpdatedAt, Version: 2})
+		assert.ErrorIs(t, err, domain.ErrUserVersionMismatch)
+
+		err = repo.UpdateAvatar(ctx, requests.UserAvatarUpdateRepository{ID: unknownUserID, Avatar: "avatar.jpg", UpdatedAt: userUpdatedAt})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := repo.Delete(ctx, requests.UserDelete{ID: userID3, Version: 1})
+		assert.ErrorIs(t, err, domain.ErrUserVersionMismatch)
+
+		err = repo.Delete(ctx, requests.UserDelete{ID: userID3, Version: 3})
+		assert.Nil(t, err)
+
+		_, err = repo.GetByID(ctx, requests.UserByID{ID: userID3})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		_, err = repo.GetByEmail(ctx, requests.GetByEmail{Email: "jane.smith@test.com"})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		count, err := repo.CountAll(ctx, requests.UsersList{})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), count)
+
+		users, err := repo.GetAll(ctx, requests.UsersList{Sorts: "+email"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"john.doe2@test.com", helpers.UserEmail}, emails(users))
+
+		// Deleted users can be neither deleted again nor updated
+		assert.ErrorIs(t, repo.Delete(ctx, requests.UserDelete{ID: userID3}), domain.ErrUserNotFound)
+		err = repo.UpdateStatus(ctx, requests.UserStatusUpdateRepository{ID: userID3, Status: "active", UpdatedAt: userUpdatedAt})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		err = repo.UpdateAvatar(ctx, requests.UserAvatarUpdateRepository{ID: userID3, Avatar: "avatar.jpg", UpdatedAt: userUpdatedAt})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("Get erasable users and erase them", func(t *testing.T) {
+		users, err := repo.GetErasable(ctx, requests.UsersErasableRepository{DeletedBefore: deletedBefore, Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, userID3, users[0].ID)
+		assert.Equal(t, "avatars/"+userID3+"/avatar.jpg", users[0].Avatar)
+
+		users, err = repo.GetErasable(ctx, requests.UsersErasableRepository{DeletedBefore: "2000-01-01 00:00:00", Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		// Only deleted users can be erased
+		err = repo.Erase(ctx, requests.UserErasureRepository{ID: userID2, Email: userID2 + "@erased.invalid", ErasedAt: userErasedAt})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		err = repo.Erase(ctx, requests.UserErasureRepository{ID: userID3, Email: userID3 + "@erased.invalid", ErasedAt: userErasedAt})
+		assert.Nil(t, err)
+
+		err = repo.Erase(ctx, requests.UserErasureRepository{ID: userID3, Email: userID3 + "@erased.invalid", ErasedAt: userErasedAt})
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+		users, err = repo.GetErasable(ctx, requests.UsersErasableRepository{DeletedBefore: deletedBefore, Limit: 10})
+		assert.Nil(t, err)
+		assert.Len(t, users, 0)
+
+		// The email of an erased user can be used again
+		err = repo.Create(ctx, newUser(unknownUserID, "jane.smith@test.com", "Jane", "Smith"))
+		assert.Nil(t, err)
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(helpers.TenantContext())
+		cancel()
+
+		err := repo.Create(ctx, newUser(userID3, "cancelled@test.com", "John", "Doe"))
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repo.GetByID(ctx, requests.UserByID{ID: helpers.UserID})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repo.GetByEmail(ctx, requests.GetByEmail{Email: "john.doe@test.com"})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repo.GetAll(ctx, requests.UsersList{})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repo.CountAll(ctx, requests.UsersList{})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		err = repo.UpdateStatus(ctx, requests.UserStatusUpdateRepository{ID: helpers.UserID, Status: "disabled", UpdatedAt: userUpdatedAt})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		err = repo.Delete(ctx, requests.UserDelete{ID: helpers.UserID})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = repo.GetErasable(ctx, requests.UsersErasableRepository{DeletedBefore: deletedBefore, Limit: 10})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		// Nothing has been changed
+		user, err := repo.GetByID(helpers.TenantContext(), requests.UserByID{ID: helpers.UserID})
+		assert.Nil(t, err)
+		assert.Equal(t, "active", user.Status)
+	})
+}
+
+// newUser returns a user creation request
+func newUser(id, email, firstname, lastname string) requests.UserCreationRepository {
+	return requests.UserCreationRepository{
+		ID:        id,
+		Email:     email,
+		Password:  hashedPassword,
+		Lastname:  lastname,
+		Firstname: firstname,
+		CreatedAt: userCreatedAt,
+		UpdatedAt: userUpdatedAt,
+	}
+}
+
+// emails returns the emails of a list of users
+func emails(users []responses.UsersListRepository) []string {
+	list := make([]string, 0, len(users))
+	for _, user := range users {
+		list = append(list, user.Email)
+	}
+
+	return list
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"chi_boilerplate/pkg/adapters/repositories"
+	domain "chi_boilerplate/pkg/domain/repositories"
+	"chi_boilerplate/pkg/domain/requests"
+	"chi_boilerplate/pkg/domain/responses"
+	"chi_boilerplate/tests/helpers"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const userID4 = "d2e9b4a1-2f3c-4d6e-9fa0-1b2c3d4e5f60"
+
+// searchRepositories returns the repositories of a test database with a user search:
+// the full-text index of MySQL or the embedded index
+func searchRepositories(t *testing.T, tdb helpers.TestDB) repositories.Repositories {
+	repos, err := repositories.New(tdb.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repos.UserSearch == nil {
+		if repos, err = repos.WithSearchIndex(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return repos
+}
+
+// TestUserSearchRepositoryContract runs the same tests against every UserSearchRepository implementation
+func TestUserSearchRepositoryContract(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			testUserSearchRepository(t, searchRepositories(t, tdb))
+		})
+	}
+}
+
+// TestUserSearchTransaction checks that the users written in a transaction are only searchable once it is committed
+func TestUserSearchTransaction(t *testing.T) {
+	for name, init := range userRepositoryImplementations() {
+		t.Run(name, func(t *testing.T) {
+			if name == "memory" {
+				t.Skip("In-memory operations are not rolled back")
+			}
+			skipDisabled(t, name)
+
+			tdb := init()
+			defer tdb.Drop()
+
+			repos := searchRepositories(t, tdb)
+			ctx := helpers.TenantContext()
+			errRollback := errors.New("rollback")
+
+			err := repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := repos.User.Create(ctx, newUser(userID2, "john.doe@test.com", "John", "Doe")); err != nil {
+					return err
+				}
+				return errRollback
+			})
+			assert.ErrorIs(t, err, errRollback)
+			assert.Empty(t, searchIDs(t, repos.UserSearch, ctx, "doe"))
+
+			err = repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				return repos.User.Create(ctx, newUser(userID2, "john.doe@test.com", "John", "Doe"))
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{userID2}, searchIDs(t, repos.UserSearch, ctx, "doe"))
+		})
+	}
+}
+
+func testUserSearchRepository(t *testing.T, repos repositories.Repositories) {
+	ctx := helpers.TenantContext()
+	search := repos.UserSearch
+
+	for _, user := range []requests.UserCreationRepository{
+		newUser(userID2, "john.doe@test.com", "John", "Doe"),
+		newUser(userID3, "jane.smith@test.com", "Jane", "Smith"),
+		newUser(userID4, "smithers@test.com", "Bob", "Brown"),
+	} {
+		if err := repos.User.Create(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Words and prefixes", func(t *testing.T) {
+		assert.Equal(t, []string{userID2}, searchIDs(t, search, ctx, "doe"))
+		assert.Equal(t, []string{userID3}, searchIDs(t, search, ctx, "JAN"))
+		assert.Equal(t, []string{userID2}, searchIDs(t, search, ctx, "john.doe@"))
+	})
+
+	t.Run("All the words must match", func(t *testing.T) {
+		assert.Equal(t, []string{userID2}, searchIDs(t, search, ctx, "john doe"))
+		assert.Empty(t, searchIDs(t, search, ctx, "jane doe"))
+		assert.Empty(t, searchIDs(t, search, ctx, "unknown"))
+		assert.Empty(t, searchIDs(t, search, ctx, "!?"))
+	})
+
+	t.Run("Relevance", func(t *testing.T) {
+		// A name is more relevant than a part of an email
+		hits, err := search.Search(ctx, requests.UserSearchRepository{Query: "smith", Limit: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{userID3, userID4}, hitIDs(hits))
+		assert.Greater(t, hits[0].Score, hits[1].Score)
+		assert.Equal(t, "jane.smith@test.com", hits[0].Email)
+		assert.Equal(t, "Smith", hits[0].Lastname)
+		assert.Equal(t, "active", hits[0].Status)
+
+		hits, err = search.Search(ctx, requests.UserSearchRepository{Query: "smith", Limit: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{userID3}, hitIDs(hits))
+	})
+
+	t.Run("Organizations are isolated", func(t *testing.T) {
+		otherCtx := domain.WithTenant(context.Background(), organizationID2)
+		assert.Empty(t, searchIDs(t, search, otherCtx, "doe"))
+	})
+
+	t.Run("Writes are searchable", func(t *testing.T) {
+		err := repos.User.Update(ctx, requests.UserUpdateRepository{
+			ID:        userID2,
+			Email:     "john.dupont@test.com",
+			Password:  hashedPassword,
+			Lastname:  "Dupont",
+			Firstname: "John",
+			UpdatedAt: userUpdatedAt,
+		})
+		assert.Nil(t, err)
+		assert.Empty(t, searchIDs(t, search, ctx, "doe"))
+		assert.Equal(t, []string{userID2}, searchIDs(t, search, ctx, "dupont"))
+
+		err = repos.User.Delete(ctx, requests.UserDelete{ID: userID2})
+		assert.Nil(t, err)
+		assert.Empty(t, searchIDs(t, search, ctx, "dupont"))
+	})
+}
+
+// searchIDs returns the IDs of the users found by a query
+func searchIDs(t *testing.T, search domain.UserSearchRepository, ctx context.Context, query string) []string {
+	hits, err := search.Search(ctx, requests.UserSearchRepository{Query: query, Limit: 10})
+	assert.Nil(t, err)
+
+	return hitIDs(hits)
+}
+
+// hitIDs returns the IDs of the users of search hits
+func hitIDs(hits []responses.UserSearchHit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+
+	return ids
+}
@@ -7,7 +7,7 @@ import (
 )
 
 func TestWebRoutes(t *testing.T) {
-	tdb := helpers.InitMySQL("../.env", "../migrations")
+	tdb := helpers.Init("../.env", "../migrations")
 	defer tdb.Drop()
 
 	useCases := []helpers.Test{
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "0 3 * * *", "*/15 8-18 * * 1-5", "0,30 * 1 1,6 7", "5/10 * * * *", "@daily", "@HOURLY"} {
+		_, err := ParseCron(expr)
+		assert.Nil(t, err, expr)
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *", "0 0 30 2 *", "@never"} {
+		_, err := ParseCron(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	from := time.Date(2025, 3, 1, 10, 17, 30, 0, time.UTC) // Saturday
+
+	tests := []struct {
+		expr   string
+		from   time.Time
+		wanted time.Time
+	}{
+		{"* * * * *", from, time.Date(2025, 3, 1, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", from, time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", from, time.Date(2025, 3, 2, 3, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", from, time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", from, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", from, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", from, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// The day of month or the day of week
+		{"0 0 15 * 1", from, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)},
+		// The time is already matching: the next one is returned
+		{"17 10 * * *", time.Date(2025, 3, 1, 10, 17, 0, 0, time.UTC), time.Date(2025, 3, 2, 10, 17, 0, 0, time.UTC)},
+		// Local time
+		{"0 3 * * *", time.Date(2025, 3, 1, 10, 0, 0, 0, paris), time.Date(2025, 3, 2, 3, 0, 0, 0, paris)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wanted, s.Next(tt.from))
+		})
+	}
+}
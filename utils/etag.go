@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidETag is returned when an entity tag cannot be parsed.
+var ErrInvalidETag = errors.New("invalid ETag")
+
+// AnyETag is the If-Match value matching any current representation.
+const AnyETag = "*"
+
+// ETagFromVersion returns a strong entity tag from a resource version.
+func ETagFromVersion(version uint64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// VersionFromETag returns the resource version from an If-Match entity tag.
+// If-Match uses the strong comparison (RFC 9110 §13.1.1): weak validators (W/"...") are rejected.
+func VersionFromETag(etag string) (uint64, error) {
+	etag = strings.TrimSpace(etag)
+	if len(etag) < 2 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		return 0, ErrInvalidETag
+	}
+
+	version, err := strconv.ParseUint(etag[1:len(etag)-1], 10, 64)
+	if err != nil || version == 0 {
+		return 0, ErrInvalidETag
+	}
+
+	return version, nil
+}
+
+// ETagMatch checks if one of the entity tags of an If-None-Match header
+// matches the given entity tag (weak comparison, RFC 9110 §13.1.2).
+func ETagMatch(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == AnyETag || strings.TrimPrefix(v, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETagFromVersion(t *testing.T) {
+	assert.Equal(t, `"1"`, ETagFromVersion(1))
+	assert.Equal(t, `"42"`, ETagFromVersion(42))
+}
+
+func TestVersionFromETag(t *testing.T) {
+	type result struct {
+		version uint64
+		err     error
+	}
+
+	tests := []struct {
+		name   string
+		etag   string
+		wanted result
+	}{
+		{
+			name:   "Strong ETag",
+			etag:   `"3"`,
+			wanted: result{version: 3, err: nil},
+		},
+		{
+			name:   "Weak ETag",
+			etag:   `W/"3"`,
+			wanted: result{version: 0, err: ErrInvalidETag},
+		},
+		{
+			name:   "Without quotes",
+			etag:   `3`,
+			wanted: result{version: 0, err: ErrInvalidETag},
+		},
+		{
+			name:   "Not a number",
+			etag:   `"abc"`,
+			wanted: result{version: 0, err: ErrInvalidETag},
+		},
+		{
+			name:   "Zero version",
+			etag:   `"0"`,
+			wanted: result{version: 0, err: ErrInvalidETag},
+		},
+		{
+			name:   "Empty",
+			etag:   ``,
+			wanted: result{version: 0, err: ErrInvalidETag},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := VersionFromETag(tt.etag)
+
+			assert.Equal(t, tt.wanted, result{version, err})
+		})
+	}
+}
+
+func TestETagMatch(t *testing.T) {
+	assert.True(t, ETagMatch(`"1"`, `"1"`))
+	assert.True(t, ETagMatch(`W/"1"`, `"1"`))
+	assert.True(t, ETagMatch(`"2", "1"`, `"1"`))
+	assert.True(t, ETagMatch(`*`, `"1"`))
+	assert.False(t, ETagMatch(`"2"`, `"1"`))
+	assert.False(t, ETagMatch(``, `"1"`))
+}
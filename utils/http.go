@@ -133,3 +133,9 @@ func NoContent(w http.ResponseWriter) error {
 
 	return nil
 }
+
+func NotModified(w http.ResponseWriter) error {
+	w.WriteHeader(http.StatusNotModified)
+
+	return nil
+}